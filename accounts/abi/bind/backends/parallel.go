@@ -0,0 +1,400 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ParallelConfig holds the options of the parallel execution of transaction
+// batches by the simulated backend.
+type ParallelConfig struct {
+	// Threads is the number of transactions executed concurrently. A batch is
+	// split into generations of this size, each executed on top of the state
+	// left by the previous one. Zero executes the whole batch in one generation.
+	Threads int
+
+	// RetryConflicts re-executes the conflicting transactions sequentially after
+	// their generation, as Arcology does, instead of dropping them.
+	RetryConflicts bool
+}
+
+// ParallelResult is the outcome of a transaction of a parallel batch.
+type ParallelResult struct {
+	Tx         *types.Transaction
+	Generation int   // Index of the generation the transaction was executed in
+	Conflicts  []int // Batch indices of the earlier transactions of the generation it conflicts with
+	Retried    bool  // Whether the transaction was re-executed after its generation
+	Included   bool  // Whether the transaction was included in the pending block
+	Err        error // Failure preventing the inclusion of the transaction, if any
+}
+
+// SendParallelTransactions adds a batch of transactions to the pending block as
+// if they were executed concurrently, reporting the conflicts between them.
+//
+// The transactions of a generation are all executed on the same state. A
+// transaction conflicts with an earlier one of its generation if it accesses
+// any state the earlier one writes, the gas fees paid to the coinbase aside.
+// The state behind the Arcology APIs is opaque unless the router reports it
+// through vm.ArcologyAccessReporter, so by default calls intercepted by the
+// router conflict with any other intercepted call from the same contract to the
+// same API address.
+// Conflicting transactions are dropped, or retried if configured so.
+func (b *SimulatedBackend) SendParallelTransactions(ctx context.Context, txs []*types.Transaction) ([]*ParallelResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parent, err := b.blockByHash(ctx, b.pendingBlock.ParentHash())
+	if err != nil {
+		return nil, errors.New("could not fetch parent")
+	}
+	var (
+		header   = b.pendingBlock.Header()
+		signer   = types.MakeSigner(b.config, header.Number, header.Time)
+		work     = b.pendingState.Copy()
+		gasPool  = new(core.GasPool).AddGas(header.GasLimit - header.GasUsed)
+		results  = make([]*ParallelResult, len(txs))
+		included []*types.Transaction
+		threads  = b.parallel.Threads
+	)
+	if threads <= 0 {
+		threads = len(txs)
+	}
+	for start := 0; start < len(txs); start += threads {
+		end := start + threads
+		if end > len(txs) {
+			end = len(txs)
+		}
+		var (
+			accepted []int
+			writes   = make(map[int]map[stateKey]struct{})
+			senders  = make(map[common.Address]int)
+			retries  []int
+		)
+		for i := start; i < end; i++ {
+			results[i] = &ParallelResult{Tx: txs[i], Generation: start / threads}
+
+			sender, err := types.Sender(signer, txs[i])
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			// Transactions of the same sender depend on each other's nonce
+			if prev, ok := senders[sender]; ok {
+				results[i].Conflicts = []int{prev}
+				retries = append(retries, i)
+				continue
+			}
+			senders[sender] = i
+
+			tracker := newAccessTracker(work.Copy(), header.Coinbase)
+			if err := b.applyParallelTx(tracker, tracker.StateDB, new(core.GasPool).AddGas(gasPool.Gas()), header, signer, txs[i]); err != nil {
+				results[i].Err = err
+				continue
+			}
+			for _, j := range accepted {
+				if tracker.conflicts(writes[j]) {
+					results[i].Conflicts = append(results[i].Conflicts, j)
+				}
+			}
+			if len(results[i].Conflicts) > 0 {
+				retries = append(retries, i)
+				continue
+			}
+			accepted = append(accepted, i)
+			writes[i] = tracker.writes
+		}
+		// Commit the conflict free transactions of the generation, which yields
+		// the same state as the concurrent execution
+		for _, i := range accepted {
+			if err := b.applyParallelTx(work, work, gasPool, header, signer, txs[i]); err != nil {
+				results[i].Err = err // Only the block gas limit may be exhausted by now
+				continue
+			}
+			results[i].Included = true
+			included = append(included, txs[i])
+		}
+		if !b.parallel.RetryConflicts {
+			continue
+		}
+		for _, i := range retries {
+			results[i].Retried = true
+			snapshot := work.Snapshot()
+			if err := b.applyParallelTx(work, work, gasPool, header, signer, txs[i]); err != nil {
+				work.RevertToSnapshot(snapshot)
+				results[i].Err = err
+				continue
+			}
+			results[i].Included = true
+			included = append(included, txs[i])
+		}
+	}
+	if len(included) == 0 {
+		return results, nil
+	}
+	// Rebuild the pending block with the included transactions
+	blocks, receipts := core.GenerateChain(b.config, parent, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
+		for _, tx := range b.pendingBlock.Transactions() {
+			block.AddTxWithChainAndVMConfig(b.blockchain, tx, b.vmConfig)
+		}
+		for _, tx := range included {
+			block.AddTxWithChainAndVMConfig(b.blockchain, tx, b.vmConfig)
+		}
+	})
+	stateDB, err := b.blockchain.State()
+	if err != nil {
+		return nil, err
+	}
+	b.pendingBlock = blocks[0]
+	b.pendingState, _ = state.New(b.pendingBlock.Root(), stateDB.Database(), nil)
+	b.pendingReceipts = receipts[0]
+	return results, nil
+}
+
+// applyParallelTx executes a transaction of a parallel batch on top of the
+// given state, without creating a receipt. The state is accessed through db,
+// which may be a wrapper around statedb.
+func (b *SimulatedBackend) applyParallelTx(db vm.StateDB, statedb *state.StateDB, gasPool *core.GasPool, header *types.Header, signer types.Signer, tx *types.Transaction) error {
+	msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+	if err != nil {
+		return err
+	}
+	statedb.SetTxContext(tx.Hash(), 0)
+
+	// Record the Arcology API calls along with the state accesses
+	config := b.vmConfig
+	if tracker, ok := db.(*accessTracker); ok {
+		config.ArcologyAPIs = &routerTracker{router: config.ArcologyAPIs, tracker: tracker}
+	}
+	var (
+		blockContext = core.NewEVMBlockContext(header, b.blockchain, nil, b.config, statedb)
+		evm          = vm.NewEVM(blockContext, core.NewEVMTxContext(msg), db, b.config, config)
+	)
+	if _, err := core.ApplyMessage(evm, msg, gasPool); err != nil {
+		return err
+	}
+	statedb.Finalise(true)
+	return nil
+}
+
+// stateKey identifies an item of the state accessed by a transaction. The slot
+// is empty for the account fields, which are told apart by the kind.
+type stateKey struct {
+	addr common.Address
+	kind byte
+	slot common.Hash
+}
+
+const (
+	balanceKey byte = iota
+	nonceKey
+	codeKey
+	storageKey
+	arcologyKey     // State behind an Arcology API owned by the calling contract in the slot
+	arcologyItemKey // Item behind an Arcology API reported by the router, hashed into the slot
+)
+
+// accessTracker is a state database recording the state read and written by a
+// transaction, ignoring the coinbase collecting the gas fees.
+type accessTracker struct {
+	*state.StateDB
+	coinbase common.Address
+	reads    map[stateKey]struct{}
+	writes   map[stateKey]struct{}
+}
+
+func newAccessTracker(db *state.StateDB, coinbase common.Address) *accessTracker {
+	return &accessTracker{
+		StateDB:  db,
+		coinbase: coinbase,
+		reads:    make(map[stateKey]struct{}),
+		writes:   make(map[stateKey]struct{}),
+	}
+}
+
+func (t *accessTracker) read(addr common.Address, kinds ...byte) {
+	if addr == t.coinbase {
+		return
+	}
+	for _, kind := range kinds {
+		t.reads[stateKey{addr: addr, kind: kind}] = struct{}{}
+	}
+}
+
+func (t *accessTracker) write(addr common.Address, kinds ...byte) {
+	if addr == t.coinbase {
+		return
+	}
+	for _, kind := range kinds {
+		t.writes[stateKey{addr: addr, kind: kind}] = struct{}{}
+	}
+}
+
+// readAPI records an access to the state of an Arcology API.
+func (t *accessTracker) readAPI(api common.Address, kind byte, slot common.Hash) {
+	t.reads[stateKey{addr: api, kind: kind, slot: slot}] = struct{}{}
+}
+
+// writeAPI records a change to the state of an Arcology API.
+func (t *accessTracker) writeAPI(api common.Address, kind byte, slot common.Hash) {
+	t.writes[stateKey{addr: api, kind: kind, slot: slot}] = struct{}{}
+}
+
+// conflicts reports whether the transaction accessed any of the given writes of
+// another transaction.
+func (t *accessTracker) conflicts(writes map[stateKey]struct{}) bool {
+	for key := range writes {
+		if _, ok := t.reads[key]; ok {
+			return true
+		}
+		if _, ok := t.writes[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// routerTracker is an Arcology API router recording the calls intercepted by
+// the wrapped router as accesses of a transaction. If the router reports the
+// items a call accessed, those are recorded. Otherwise the call is assumed to
+// read and write all the state the calling contract holds in the API.
+type routerTracker struct {
+	router  vm.ArcologyAPIRouterInterface
+	tracker *accessTracker
+}
+
+// Call implements vm.ArcologyAPIRouterInterface.
+func (r *routerTracker) Call(caller, callee [20]byte, input []byte, origin [20]byte, nonce uint64, blockhash common.Hash) (bool, []byte, bool, int64) {
+	called, ret, ok, gasUsed := r.router.Call(caller, callee, input, origin, nonce, blockhash)
+	if !called {
+		return called, ret, ok, gasUsed
+	}
+	if reporter, ok := r.router.(vm.ArcologyAccessReporter); ok {
+		reads, writes := reporter.Accesses(caller, callee, input)
+		for _, item := range reads {
+			r.tracker.readAPI(callee, arcologyItemKey, crypto.Keccak256Hash(item))
+		}
+		for _, item := range writes {
+			r.tracker.writeAPI(callee, arcologyItemKey, crypto.Keccak256Hash(item))
+		}
+	} else {
+		owner := common.BytesToHash(caller[:])
+		r.tracker.readAPI(callee, arcologyKey, owner)
+		r.tracker.writeAPI(callee, arcologyKey, owner)
+	}
+	return called, ret, ok, gasUsed
+}
+
+func (t *accessTracker) CreateAccount(addr common.Address) {
+	t.write(addr, balanceKey, nonceKey, codeKey)
+	t.StateDB.CreateAccount(addr)
+}
+
+func (t *accessTracker) SubBalance(addr common.Address, amount *big.Int) {
+	if amount.Sign() != 0 {
+		t.read(addr, balanceKey)
+		t.write(addr, balanceKey)
+	}
+	t.StateDB.SubBalance(addr, amount)
+}
+
+func (t *accessTracker) AddBalance(addr common.Address, amount *big.Int) {
+	if amount.Sign() != 0 {
+		t.read(addr, balanceKey)
+		t.write(addr, balanceKey)
+	}
+	t.StateDB.AddBalance(addr, amount)
+}
+
+func (t *accessTracker) GetBalance(addr common.Address) *big.Int {
+	t.read(addr, balanceKey)
+	return t.StateDB.GetBalance(addr)
+}
+
+func (t *accessTracker) GetNonce(addr common.Address) uint64 {
+	t.read(addr, nonceKey)
+	return t.StateDB.GetNonce(addr)
+}
+
+func (t *accessTracker) SetNonce(addr common.Address, nonce uint64) {
+	t.write(addr, nonceKey)
+	t.StateDB.SetNonce(addr, nonce)
+}
+
+func (t *accessTracker) GetCodeHash(addr common.Address) common.Hash {
+	t.read(addr, codeKey)
+	return t.StateDB.GetCodeHash(addr)
+}
+
+func (t *accessTracker) GetCode(addr common.Address) []byte {
+	t.read(addr, codeKey)
+	return t.StateDB.GetCode(addr)
+}
+
+func (t *accessTracker) SetCode(addr common.Address, code []byte) {
+	t.write(addr, codeKey)
+	t.StateDB.SetCode(addr, code)
+}
+
+func (t *accessTracker) GetCodeSize(addr common.Address) int {
+	t.read(addr, codeKey)
+	return t.StateDB.GetCodeSize(addr)
+}
+
+func (t *accessTracker) GetCommittedState(addr common.Address, slot common.Hash) common.Hash {
+	t.reads[stateKey{addr: addr, kind: storageKey, slot: slot}] = struct{}{}
+	return t.StateDB.GetCommittedState(addr, slot)
+}
+
+func (t *accessTracker) GetState(addr common.Address, slot common.Hash) common.Hash {
+	t.reads[stateKey{addr: addr, kind: storageKey, slot: slot}] = struct{}{}
+	return t.StateDB.GetState(addr, slot)
+}
+
+func (t *accessTracker) SetState(addr common.Address, slot common.Hash, value common.Hash) {
+	t.writes[stateKey{addr: addr, kind: storageKey, slot: slot}] = struct{}{}
+	t.StateDB.SetState(addr, slot, value)
+}
+
+func (t *accessTracker) SelfDestruct(addr common.Address) {
+	t.write(addr, balanceKey, nonceKey, codeKey)
+	t.StateDB.SelfDestruct(addr)
+}
+
+func (t *accessTracker) Selfdestruct6780(addr common.Address) {
+	t.write(addr, balanceKey, nonceKey, codeKey)
+	t.StateDB.Selfdestruct6780(addr)
+}
+
+func (t *accessTracker) Exist(addr common.Address) bool {
+	t.read(addr, balanceKey, nonceKey, codeKey)
+	return t.StateDB.Exist(addr)
+}
+
+func (t *accessTracker) Empty(addr common.Address) bool {
+	t.read(addr, balanceKey, nonceKey, codeKey)
+	return t.StateDB.Empty(addr)
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testArcologyAPIs is an Arcology API router answering all calls to a single
+// system address with a fixed output.
+type testArcologyAPIs struct {
+	addr   common.Address
+	output []byte
+	calls  int
+}
+
+func (r *testArcologyAPIs) Call(caller, callee [20]byte, input []byte, origin [20]byte, nonce uint64, blockhash common.Hash) (bool, []byte, bool, int64) {
+	if common.Address(callee) != r.addr {
+		return false, nil, false, 0
+	}
+	r.calls++
+	return true, r.output, true, 0
+}
+
+func TestSimulatedBackendArcologyAPIs(t *testing.T) {
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+	router := &testArcologyAPIs{
+		addr:   common.HexToAddress("0x84"),
+		output: common.FromHex("0x2a"),
+	}
+	sim := NewSimulatedBackendWithOpts(
+		WithAlloc(core.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}}),
+		WithArcologyAPIs(router),
+	)
+	defer sim.Close()
+
+	call := ethereum.CallMsg{From: testAddr, To: &router.addr, Data: []byte{0x01}}
+	out, err := sim.CallContract(context.Background(), call, nil)
+	if err != nil {
+		t.Fatalf("failed to call the Arcology API: %v", err)
+	}
+	if !bytes.Equal(out, router.output) {
+		t.Fatalf("output mismatch: have %x, want %x", out, router.output)
+	}
+	if out, err = sim.PendingCallContract(context.Background(), call); err != nil {
+		t.Fatalf("failed to call the Arcology API on the pending state: %v", err)
+	}
+	if !bytes.Equal(out, router.output) {
+		t.Fatalf("pending output mismatch: have %x, want %x", out, router.output)
+	}
+	if router.calls != 2 {
+		t.Fatalf("router call count mismatch: have %d, want 2", router.calls)
+	}
+}
+
+func TestSendParallelTransactions(t *testing.T) {
+	var (
+		keys  = make([]*ecdsa.PrivateKey, 3)
+		alloc = make(core.GenesisAlloc)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	transfer := func(sim *SimulatedBackend, key *ecdsa.PrivateKey, nonce uint64, to common.Address) *types.Transaction {
+		head, _ := sim.HeaderByNumber(context.Background(), nil)
+		gasPrice := new(big.Int).Mul(head.BaseFee, big.NewInt(2))
+		tx := types.NewTransaction(nonce, to, big.NewInt(1), params.TxGas, gasPrice, nil)
+		tx, _ = types.SignTx(tx, types.HomesteadSigner{}, key)
+		return tx
+	}
+	var (
+		alice = common.HexToAddress("0xa11ce")
+		bob   = common.HexToAddress("0xb0b")
+	)
+	tests := []struct {
+		name     string
+		config   ParallelConfig
+		txs      func(sim *SimulatedBackend) []*types.Transaction
+		included []bool
+		retried  []bool
+		nonces   []uint64
+	}{
+		{
+			name: "disjoint",
+			txs: func(sim *SimulatedBackend) []*types.Transaction {
+				return []*types.Transaction{transfer(sim, keys[0], 0, alice), transfer(sim, keys[1], 0, bob)}
+			},
+			included: []bool{true, true},
+			retried:  []bool{false, false},
+			nonces:   []uint64{1, 1, 0},
+		},
+		{
+			name: "same recipient",
+			txs: func(sim *SimulatedBackend) []*types.Transaction {
+				return []*types.Transaction{transfer(sim, keys[0], 0, alice), transfer(sim, keys[1], 0, alice)}
+			},
+			included: []bool{true, false},
+			retried:  []bool{false, false},
+			nonces:   []uint64{1, 0, 0},
+		},
+		{
+			name:   "same recipient retried",
+			config: ParallelConfig{RetryConflicts: true},
+			txs: func(sim *SimulatedBackend) []*types.Transaction {
+				return []*types.Transaction{transfer(sim, keys[0], 0, alice), transfer(sim, keys[1], 0, alice)}
+			},
+			included: []bool{true, true},
+			retried:  []bool{false, true},
+			nonces:   []uint64{1, 1, 0},
+		},
+		{
+			name: "same sender",
+			txs: func(sim *SimulatedBackend) []*types.Transaction {
+				return []*types.Transaction{transfer(sim, keys[0], 0, alice), transfer(sim, keys[0], 1, bob)}
+			},
+			included: []bool{true, false},
+			retried:  []bool{false, false},
+			nonces:   []uint64{1, 0, 0},
+		},
+		{
+			name:   "separate generations",
+			config: ParallelConfig{Threads: 1},
+			txs: func(sim *SimulatedBackend) []*types.Transaction {
+				return []*types.Transaction{transfer(sim, keys[0], 0, alice), transfer(sim, keys[1], 0, alice), transfer(sim, keys[2], 0, alice)}
+			},
+			included: []bool{true, true, true},
+			retried:  []bool{false, false, false},
+			nonces:   []uint64{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := NewSimulatedBackendWithOpts(WithAlloc(alloc), WithParallelExecution(tt.config))
+			defer sim.Close()
+
+			results, err := sim.SendParallelTransactions(context.Background(), tt.txs(sim))
+			if err != nil {
+				t.Fatalf("failed to send the batch: %v", err)
+			}
+			if len(results) != len(tt.included) {
+				t.Fatalf("result count mismatch: have %d, want %d", len(results), len(tt.included))
+			}
+			for i, res := range results {
+				if res.Included != tt.included[i] {
+					t.Errorf("tx %d: inclusion mismatch: have %v, want %v (err %v)", i, res.Included, tt.included[i], res.Err)
+				}
+				if res.Retried != tt.retried[i] {
+					t.Errorf("tx %d: retry mismatch: have %v, want %v", i, res.Retried, tt.retried[i])
+				}
+				if !res.Included && !res.Retried && len(res.Conflicts) == 0 {
+					t.Errorf("tx %d: dropped without conflicts", i)
+				}
+			}
+			for i, key := range keys {
+				nonce, err := sim.PendingNonceAt(context.Background(), crypto.PubkeyToAddress(key.PublicKey))
+				if err != nil {
+					t.Fatalf("failed to retrieve pending nonce: %v", err)
+				}
+				if nonce != tt.nonces[i] {
+					t.Errorf("account %d: pending nonce mismatch: have %d, want %d", i, nonce, tt.nonces[i])
+				}
+			}
+		})
+	}
+}
+
+// testContainerAPIs is an Arcology API router reporting the first byte of the
+// call input as the container accessed by the call.
+type testContainerAPIs struct {
+	testArcologyAPIs
+}
+
+func (r *testContainerAPIs) Accesses(caller, callee [20]byte, input []byte) ([][]byte, [][]byte) {
+	return [][]byte{input[:1]}, [][]byte{input[:1]}
+}
+
+// testAPIProxy is the code of a contract forwarding its call data to the test
+// Arcology API router at 0x84.
+var testAPIProxy = []byte{
+	byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.CALLDATACOPY),
+	byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00,
+	byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x84, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP),
+}
+
+// Tests the conflicts between transactions calling the Arcology APIs through
+// a contract, which don't touch any common state of the EVM.
+func TestSendParallelTransactionsArcologyAPIs(t *testing.T) {
+	proxy := common.HexToAddress("0xc0ffee")
+	tests := []struct {
+		name     string
+		router   vm.ArcologyAPIRouterInterface
+		inputs   []byte // Container accessed by each call, 0 for a call not reaching the router
+		included []bool
+	}{
+		// Without reported accesses, calls from the same contract conflict
+		{"opaque", &testArcologyAPIs{addr: common.HexToAddress("0x84")}, []byte{1, 0, 2}, []bool{true, true, false}},
+		// Reported accesses only conflict on the same container
+		{"reported", &testContainerAPIs{testArcologyAPIs{addr: common.HexToAddress("0x84")}}, []byte{1, 2, 1}, []bool{true, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				keys  = make([]*ecdsa.PrivateKey, len(tt.inputs))
+				alloc = core.GenesisAlloc{proxy: {Code: testAPIProxy}}
+			)
+			for i := range keys {
+				keys[i], _ = crypto.GenerateKey()
+				alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+			}
+			sim := NewSimulatedBackendWithOpts(WithAlloc(alloc), WithArcologyAPIs(tt.router))
+			defer sim.Close()
+
+			head, _ := sim.HeaderByNumber(context.Background(), nil)
+			gasPrice := new(big.Int).Mul(head.BaseFee, big.NewInt(2))
+
+			txs := make([]*types.Transaction, len(keys))
+			for i, input := range tt.inputs {
+				to := proxy
+				if input == 0 {
+					to = common.HexToAddress("0xa11ce")
+				}
+				txs[i], _ = types.SignTx(types.NewTransaction(0, to, new(big.Int), 100000, gasPrice, []byte{input}), types.HomesteadSigner{}, keys[i])
+			}
+			results, err := sim.SendParallelTransactions(context.Background(), txs)
+			if err != nil {
+				t.Fatalf("failed to send the batch: %v", err)
+			}
+			for i, want := range tt.included {
+				if results[i].Included != want {
+					t.Errorf("tx %d: inclusion mismatch: have %v, want %v (err %v)", i, results[i].Included, want, results[i].Err)
+				}
+			}
+			if conflicts := results[2].Conflicts; len(conflicts) != 1 || conflicts[0] != 0 {
+				t.Errorf("conflict mismatch: have %v, want [0]", conflicts)
+			}
+		})
+	}
+}
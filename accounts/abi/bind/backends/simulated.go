@@ -76,7 +76,9 @@ type SimulatedBackend struct {
 	events       *filters.EventSystem  // for filtering log events live
 	filterSystem *filters.FilterSystem // for filtering database logs
 
-	config *params.ChainConfig
+	config   *params.ChainConfig
+	vmConfig vm.Config      // EVM configuration, carrying the Arcology API router
+	parallel ParallelConfig // Options of the parallel execution of transaction batches
 }
 
 // NewSimulatedBackendWithDatabase creates a new binding backend based on the given database
@@ -94,11 +96,13 @@ func NewSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBac
 }
 
 type simulatedBackendConfig struct {
-	genesis     core.Genesis
-	cacheConfig *core.CacheConfig
-	database    ethdb.Database
-	vmConfig    vm.Config
-	consensus   consensus.Engine
+	genesis      core.Genesis
+	cacheConfig  *core.CacheConfig
+	database     ethdb.Database
+	vmConfig     vm.Config
+	consensus    consensus.Engine
+	arcologyAPIs vm.ArcologyAPIRouterInterface
+	parallel     ParallelConfig
 }
 
 type SimulatedBackendOpt func(s *simulatedBackendConfig)
@@ -145,6 +149,23 @@ func WithConsensus(consensus consensus.Engine) SimulatedBackendOpt {
 	}
 }
 
+// WithArcologyAPIs sets the router handling the calls to the Arcology system
+// APIs. By default a router intercepting no calls is used, executing contracts
+// just like on Ethereum.
+func WithArcologyAPIs(router vm.ArcologyAPIRouterInterface) SimulatedBackendOpt {
+	return func(s *simulatedBackendConfig) {
+		s.arcologyAPIs = router
+	}
+}
+
+// WithParallelExecution sets the options of the parallel execution of the
+// transaction batches sent with SendParallelTransactions.
+func WithParallelExecution(config ParallelConfig) SimulatedBackendOpt {
+	return func(s *simulatedBackendConfig) {
+		s.parallel = config
+	}
+}
+
 // NewSimulatedBackendWithOpts creates a new binding backend based on the given database
 // and uses a simulated blockchain for testing purposes. It exposes additional configuration
 // options that are useful to
@@ -158,7 +179,10 @@ func NewSimulatedBackendWithOpts(opts ...SimulatedBackendOpt) *SimulatedBackend
 	for _, opt := range opts {
 		opt(config)
 	}
-
+	config.vmConfig.ArcologyAPIs = config.arcologyAPIs
+	if config.vmConfig.ArcologyAPIs == nil {
+		config.vmConfig.ArcologyAPIs = vm.NoopArcologyAPIs{}
+	}
 	config.genesis.MustCommit(config.database, trie.NewDatabase(config.database, trie.HashDefaults))
 	blockchain, _ := core.NewBlockChain(config.database, config.cacheConfig, &config.genesis, nil, config.consensus, config.vmConfig, nil, nil)
 
@@ -167,6 +191,8 @@ func NewSimulatedBackendWithOpts(opts ...SimulatedBackendOpt) *SimulatedBackend
 		blockchain: blockchain,
 		config:     config.genesis.Config,
 		consensus:  config.consensus,
+		vmConfig:   config.vmConfig,
+		parallel:   config.parallel,
 	}
 
 	filterBackend := &filterBackend{config.database, blockchain, backend}
@@ -773,7 +799,7 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallM
 	// about the transaction and calling mechanisms.
 	txContext := core.NewEVMTxContext(msg)
 	evmContext := core.NewEVMBlockContext(header, b.blockchain, nil, b.config, stateDB)
-	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, b.config, vm.Config{NoBaseFee: true})
+	vmEnv := vm.NewEVM(evmContext, txContext, stateDB, b.config, vm.Config{NoBaseFee: true, ArcologyAPIs: b.vmConfig.ArcologyAPIs})
 	gasPool := new(core.GasPool).AddGas(math.MaxUint64)
 
 	return core.ApplyMessage(vmEnv, msg, gasPool)
@@ -802,9 +828,9 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	// Include tx in chain
 	blocks, receipts := core.GenerateChain(b.config, block, b.consensus, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTxWithChain(b.blockchain, tx)
+			block.AddTxWithChainAndVMConfig(b.blockchain, tx, b.vmConfig)
 		}
-		block.AddTxWithChain(b.blockchain, tx)
+		block.AddTxWithChainAndVMConfig(b.blockchain, tx, b.vmConfig)
 	})
 	stateDB, err := b.blockchain.State()
 	if err != nil {
@@ -1027,6 +1053,8 @@ func (fb *filterBackend) SubscribePendingLogsEvent(ch chan<- []*types.Log) event
 
 func (fb *filterBackend) BloomStatus() (uint64, uint64) { return 4096, 0 }
 
+func (fb *filterBackend) LogIndexStatus() (uint64, uint64) { return 4096, 0 }
+
 func (fb *filterBackend) ServiceFilter(ctx context.Context, ms *bloombits.MatcherSession) {
 	panic("not supported")
 }
@@ -118,6 +118,8 @@ type BoundContract struct {
 	caller     ContractCaller     // Read interface to interact with the blockchain
 	transactor ContractTransactor // Write interface to interact with the blockchain
 	filterer   ContractFilterer   // Event filtering to interact with the blockchain
+
+	errorDecoder func(error) error // Optional decoder of reverts into typed custom errors
 }
 
 // NewBoundContract creates a low level contract interface through which calls
@@ -132,6 +134,21 @@ func NewBoundContract(address common.Address, abi abi.ABI, caller ContractCaller
 	}
 }
 
+// SetErrorDecoder sets the function used to decode the reverts of calls and gas
+// estimations into the typed custom errors of the contract. The decoder should
+// return the error as is if it doesn't match any of the custom errors.
+func (c *BoundContract) SetErrorDecoder(decoder func(error) error) {
+	c.errorDecoder = decoder
+}
+
+// decodeError converts a revert into a typed custom error, if a decoder is set.
+func (c *BoundContract) decodeError(err error) error {
+	if err == nil || c.errorDecoder == nil {
+		return err
+	}
+	return c.errorDecoder(err)
+}
+
 // DeployContract deploys a contract onto the Ethereum blockchain and binds the
 // deployment address with a Go wrapper.
 func DeployContract(opts *TransactOpts, abi abi.ABI, bytecode []byte, backend ContractBackend, params ...interface{}) (common.Address, *types.Transaction, *BoundContract, error) {
@@ -180,7 +197,7 @@ func (c *BoundContract) Call(opts *CallOpts, results *[]interface{}, method stri
 		}
 		output, err = pb.PendingCallContract(ctx, msg)
 		if err != nil {
-			return err
+			return c.decodeError(err)
 		}
 		if len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
@@ -197,7 +214,7 @@ func (c *BoundContract) Call(opts *CallOpts, results *[]interface{}, method stri
 		}
 		output, err = bh.CallContractAtHash(ctx, msg, opts.BlockHash)
 		if err != nil {
-			return err
+			return c.decodeError(err)
 		}
 		if len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
@@ -210,7 +227,7 @@ func (c *BoundContract) Call(opts *CallOpts, results *[]interface{}, method stri
 	} else {
 		output, err = c.caller.CallContract(ctx, msg, opts.BlockNumber)
 		if err != nil {
-			return err
+			return c.decodeError(err)
 		}
 		if len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
@@ -410,7 +427,7 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		}
 	}
 	if err != nil {
-		return nil, err
+		return nil, c.decodeError(err)
 	}
 	// Sign the transaction and schedule it for execution
 	if opts.Signer == nil {
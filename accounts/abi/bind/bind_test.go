@@ -2060,6 +2060,46 @@ var bindTests = []struct {
 			if b, err := NewNumericMethodName(common.Address{}, nil); b == nil || err != nil {
 				t.Fatalf("combined binding (%v) nil or error (%v) not nil", b, nil)
 			}
+`,
+	}, {
+		name: "CustomErrors",
+		contract: `
+		// SPDX-License-Identifier: GPL-3.0
+		pragma solidity >=0.8.4 <0.9.0;
+
+		contract CustomErrors {
+			error InsufficientBalance(uint256 available, uint256 required);
+			error Unauthorized();
+			event Transfer(address indexed from, address indexed to, uint256 value);
+
+			function transfer(address to, uint256 value) public {}
+		}
+		`,
+		bytecode: []string{""},
+		abi:      []string{`[{"inputs":[{"internalType":"uint256","name":"available","type":"uint256"},{"internalType":"uint256","name":"required","type":"uint256"}],"name":"InsufficientBalance","type":"error"},{"inputs":[],"name":"Unauthorized","type":"error"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},{"indexed":true,"internalType":"address","name":"to","type":"address"},{"indexed":false,"internalType":"uint256","name":"value","type":"uint256"}],"name":"Transfer","type":"event"},{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`},
+		imports: `
+			"errors"
+
+			"github.com/ethereum/go-ethereum/common"
+		`,
+		tester: `
+			// Custom errors are bound as typed errors
+			var (
+				_ error = new(CustomErrorsInsufficientBalanceError)
+				_ error = new(CustomErrorsUnauthorizedError)
+			)
+			// Errors not carrying revert data are returned as is
+			plain := errors.New("plain")
+			if err := UnpackCustomErrorsError(plain); err != plain {
+				t.Fatalf("plain error decoded: %v", err)
+			}
+			// Typed filters can be constructed for events with indexed fields
+			b, err := NewCustomErrors(common.Address{}, nil)
+			if b == nil || err != nil {
+				t.Fatalf("combined binding (%v) nil or error (%v) not nil", b, err)
+			}
+			_ = b.FilterTransferBy
+			_ = CustomErrorsTransferFilter{From: []common.Address{{1}}}
 `,
 	},
 }
@@ -0,0 +1,63 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RevertData returns the revert data carried by the error of a contract call or
+// gas estimation, as reported by the backend through rpc.DataError.
+func RevertData(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		blob, err := hexutil.Decode(data)
+		if err != nil {
+			return nil, false
+		}
+		return blob, true
+	case []byte:
+		return data, true
+	case hexutil.Bytes:
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+// UnpackError unpacks the revert data of the named custom error into out, which
+// must be a pointer to a struct with a field for each error argument.
+func UnpackError(contractABI *abi.ABI, name string, out interface{}, data []byte) error {
+	errABI, ok := contractABI.Errors[name]
+	if !ok {
+		return fmt.Errorf("abi: could not locate named error: %s", name)
+	}
+	values, err := errABI.Unpack(data)
+	if err != nil {
+		return err
+	}
+	return errABI.Inputs.Copy(out, values.([]interface{}))
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const revertTestABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},{"type":"function","name":"balance","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`
+
+// revertError mimics the errors of backends reporting EVM reverts.
+type revertError struct {
+	data interface{}
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+type insufficientBalance struct {
+	Available *big.Int
+	Required  *big.Int
+	cause     error
+}
+
+func (e *insufficientBalance) Error() string { return "insufficient balance" }
+func (e *insufficientBalance) Unwrap() error { return e.cause }
+
+func packInsufficientBalance(t *testing.T, parsed abi.ABI, available, required int64) []byte {
+	failure := parsed.Errors["InsufficientBalance"]
+	args, err := failure.Inputs.Pack(big.NewInt(available), big.NewInt(required))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(failure.ID[:4:4], args...)
+}
+
+func TestRevertData(t *testing.T) {
+	tests := []struct {
+		err  error
+		data []byte
+		ok   bool
+	}{
+		{errors.New("plain"), nil, false},
+		{&revertError{data: "0x01020304"}, []byte{1, 2, 3, 4}, true},
+		{&revertError{data: hexutil.Bytes{5}}, []byte{5}, true},
+		{&revertError{data: "not hex"}, nil, false},
+		{&revertError{data: 42}, nil, false},
+	}
+	for i, test := range tests {
+		data, ok := bind.RevertData(test.err)
+		if ok != test.ok || string(data) != string(test.data) {
+			t.Errorf("test %d: have %x (%v), want %x (%v)", i, data, ok, test.data, test.ok)
+		}
+	}
+}
+
+func TestUnpackError(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(revertTestABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out insufficientBalance
+	if err := bind.UnpackError(&parsed, "InsufficientBalance", &out, packInsufficientBalance(t, parsed, 1, 2)); err != nil {
+		t.Fatalf("failed to unpack error: %v", err)
+	}
+	if out.Available.Int64() != 1 || out.Required.Int64() != 2 {
+		t.Fatalf("error arguments mismatch: %v, %v", out.Available, out.Required)
+	}
+	if err := bind.UnpackError(&parsed, "Unknown", &out, nil); err == nil {
+		t.Fatal("unpacked unknown error")
+	}
+	if err := bind.UnpackError(&parsed, "InsufficientBalance", &out, []byte{1, 2, 3, 4}); err == nil {
+		t.Fatal("unpacked mismatching selector")
+	}
+}
+
+// Tests that the reverts of calls are converted by the error decoder.
+func TestCallErrorDecoder(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(revertTestABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revert := &revertError{data: hexutil.Encode(packInsufficientBalance(t, parsed, 3, 4))}
+	caller := &mockCaller{callContractErr: revert}
+	contract := bind.NewBoundContract(common.Address{}, parsed, caller, nil, nil)
+	contract.SetErrorDecoder(func(err error) error {
+		data, ok := bind.RevertData(err)
+		if !ok {
+			return err
+		}
+		custom := &insufficientBalance{cause: err}
+		if bind.UnpackError(&parsed, "InsufficientBalance", custom, data) != nil {
+			return err
+		}
+		return custom
+	})
+	err = contract.Call(nil, nil, "balance")
+
+	var custom *insufficientBalance
+	if !errors.As(err, &custom) {
+		t.Fatalf("revert not decoded: %v", err)
+	}
+	if custom.Available.Int64() != 3 || custom.Required.Int64() != 4 || !errors.Is(err, revert) {
+		t.Fatalf("decoded error mismatch: %v", custom)
+	}
+	// Errors without revert data are returned as is
+	caller.callContractErr = errors.New("plain")
+	if err := contract.Call(nil, nil, "balance"); err != caller.callContractErr {
+		t.Fatalf("plain error mismatch: %v", err)
+	}
+}
@@ -38,6 +38,7 @@ type tmplContract struct {
 	Fallback    *tmplMethod            // Additional special fallback function
 	Receive     *tmplMethod            // Additional special receive function
 	Events      map[string]*tmplEvent  // Contract events accessors
+	Errors      map[string]*tmplError  // Contract custom errors
 	Libraries   map[string]string      // Same as tmplData, but filtered to only keep what the contract needs
 	Library     bool                   // Indicator whether the contract is a library
 }
@@ -55,6 +56,14 @@ type tmplMethod struct {
 type tmplEvent struct {
 	Original   abi.Event // Original event as parsed by the abi package
 	Normalized abi.Event // Normalized version of the parsed fields
+	Indexed    bool      // Whether the event has indexed fields to filter on
+}
+
+// tmplError is a wrapper around an abi.Error that contains a few preprocessed
+// and cached data fields.
+type tmplError struct {
+	Original   abi.Error // Original error as parsed by the abi package
+	Normalized abi.Error // Normalized version of the parsed fields
 }
 
 // tmplField is a wrapper around a struct field with binding language
@@ -90,6 +99,7 @@ import (
 	"math/big"
 	"strings"
 	"errors"
+	"fmt"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -102,6 +112,7 @@ import (
 // Reference imports to suppress errors if they are not otherwise used.
 var (
 	_ = errors.New
+	_ = fmt.Sprintf
 	_ = big.NewInt
 	_ = strings.NewReader
 	_ = ethereum.NotFound
@@ -165,8 +176,9 @@ var (
 		  {{end}}
 		  address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex({{.Type}}Bin), backend {{range .Constructor.Inputs}}, {{.Name}}{{end}})
 		  if err != nil {
-		    return common.Address{}, nil, nil, err
+		    return common.Address{}, nil, nil, {{if .Errors}}Unpack{{.Type}}Error(err){{else}}err{{end}}
 		  }
+		  {{if .Errors}}contract.SetErrorDecoder(Unpack{{.Type}}Error){{end}}
 		  return address, tx, &{{.Type}}{ {{.Type}}Caller: {{.Type}}Caller{contract: contract}, {{.Type}}Transactor: {{.Type}}Transactor{contract: contract}, {{.Type}}Filterer: {{.Type}}Filterer{contract: contract} }, nil
 		}
 	{{end}}
@@ -272,7 +284,9 @@ var (
 	  if err != nil {
 	    return nil, err
 	  }
-	  return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+	  contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+	  {{if .Errors}}contract.SetErrorDecoder(Unpack{{.Type}}Error){{end}}
+	  return contract, nil
 	}
 
 	// Call invokes the (constant) contract method with params as input values and
@@ -512,6 +526,22 @@ var (
 			return &{{$contract.Type}}{{.Normalized.Name}}Iterator{contract: _{{$contract.Type}}.contract, event: "{{.Original.Name}}", logs: logs, sub: sub}, nil
  		}
 
+		{{if .Indexed}}
+		// {{$contract.Type}}{{.Normalized.Name}}Filter holds the values of the indexed fields to filter {{.Normalized.Name}} events on.
+		// Empty fields match any value.
+		type {{$contract.Type}}{{.Normalized.Name}}Filter struct { {{range .Normalized.Inputs}}{{if .Indexed}}
+			{{capitalise .Name}} []{{bindtype .Type $structs}}; {{end}}{{end}}
+		}
+
+		// Filter{{.Normalized.Name}}By is a free log retrieval operation binding the contract event 0x{{printf "%x" .Original.ID}},
+		// filtering on the indexed fields set in the typed filter.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Filter{{.Normalized.Name}}By(opts *bind.FilterOpts, filter {{$contract.Type}}{{.Normalized.Name}}Filter) (*{{$contract.Type}}{{.Normalized.Name}}Iterator, error) {
+			return _{{$contract.Type}}.Filter{{.Normalized.Name}}(opts{{range .Normalized.Inputs}}{{if .Indexed}}, filter.{{capitalise .Name}}{{end}}{{end}})
+		}
+		{{end}}
+
 		// Watch{{.Normalized.Name}} is a free log subscription operation binding the contract event 0x{{printf "%x" .Original.ID}}.
 		//
 		// Solidity: {{.Original.String}}
@@ -554,6 +584,16 @@ var (
 			}), nil
 		}
 
+		{{if .Indexed}}
+		// Watch{{.Normalized.Name}}By is a free log subscription operation binding the contract event 0x{{printf "%x" .Original.ID}},
+		// filtering on the indexed fields set in the typed filter.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Watch{{.Normalized.Name}}By(opts *bind.WatchOpts, sink chan<- *{{$contract.Type}}{{.Normalized.Name}}, filter {{$contract.Type}}{{.Normalized.Name}}Filter) (event.Subscription, error) {
+			return _{{$contract.Type}}.Watch{{.Normalized.Name}}(opts, sink{{range .Normalized.Inputs}}{{if .Indexed}}, filter.{{capitalise .Name}}{{end}}{{end}})
+		}
+		{{end}}
+
 		// Parse{{.Normalized.Name}} is a log parse operation binding the contract event 0x{{printf "%x" .Original.ID}}.
 		//
 		// Solidity: {{.Original.String}}
@@ -567,5 +607,63 @@ var (
 		}
 
  	{{end}}
+
+	{{range .Errors}}
+		// {{$contract.Type}}{{.Normalized.Name}}Error represents a {{.Normalized.Name}} error raised by the {{$contract.Type}} contract.
+		type {{$contract.Type}}{{.Normalized.Name}}Error struct { {{range .Normalized.Inputs}}
+			{{capitalise .Name}} {{bindtype .Type $structs}}; {{end}}
+			cause error // Original error carrying the revert data
+		}
+
+		// Error implements the error interface, describing the custom error 0x{{printf "%x" (slice .Original.ID.Bytes 0 4)}}.
+		//
+		// Solidity: {{.Original.String}}
+		func (e *{{$contract.Type}}{{.Normalized.Name}}Error) Error() string {
+			{{if .Normalized.Inputs -}}
+			return fmt.Sprintf("execution reverted: {{.Original.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}}, {{end}}{{.Name}}: %v{{end}})"{{range .Normalized.Inputs}}, e.{{capitalise .Name}}{{end}})
+			{{- else -}}
+			return "execution reverted: {{.Original.Name}}()"
+			{{- end}}
+		}
+
+		// Unwrap returns the original error carrying the revert data.
+		func (e *{{$contract.Type}}{{.Normalized.Name}}Error) Unwrap() error {
+			return e.cause
+		}
+	{{end}}
+
+	{{if .Errors}}
+		// Unpack{{.Type}}Error decodes the revert data carried by err into the matching custom error
+		// of the {{.Type}} contract. The error is returned as is if it doesn't match any.
+		func Unpack{{.Type}}Error(err error) error {
+			data, ok := bind.RevertData(err)
+			if !ok || len(data) < 4 {
+				return err
+			}
+			parsed, perr := {{.Type}}MetaData.GetAbi()
+			if perr != nil {
+				return err
+			}
+			var id [4]byte
+			copy(id[:], data)
+			errABI, perr := parsed.ErrorByID(id)
+			if perr != nil {
+				return err
+			}
+			var custom error
+			switch errABI.Name {
+			{{- range .Errors}}
+			case "{{.Original.Name}}":
+				custom = &{{$contract.Type}}{{.Normalized.Name}}Error{cause: err}
+			{{- end}}
+			default:
+				return err
+			}
+			if bind.UnpackError(parsed, errABI.Name, custom, data) != nil {
+				return err
+			}
+			return custom
+		}
+	{{end}}
 {{end}}
 `
@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// AddressMetaData contains all meta data concerning the Address contract.
+var AddressMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"address\",\"internalType\":\"address\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// AddressABI is the input ABI used to generate the binding from.
+// Deprecated: Use AddressMetaData.ABI instead.
+var AddressABI = AddressMetaData.ABI
+
+// Address is an auto generated Go binding around an Ethereum contract.
+type Address struct {
+	AddressCaller     // Read-only binding to the contract
+	AddressTransactor // Write-only binding to the contract
+	AddressFilterer   // Log filterer for contract events
+}
+
+// AddressCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AddressCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AddressTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AddressFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AddressSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AddressSession struct {
+	Contract     *Address          // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AddressCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AddressCallerSession struct {
+	Contract *AddressCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts  // Call options to use throughout this session
+}
+
+// AddressTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AddressTransactorSession struct {
+	Contract     *AddressTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// AddressRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AddressRaw struct {
+	Contract *Address // Generic contract binding to access the raw methods on
+}
+
+// AddressCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AddressCallerRaw struct {
+	Contract *AddressCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AddressTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AddressTransactorRaw struct {
+	Contract *AddressTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAddress creates a new instance of Address, bound to a specific deployed contract.
+func NewAddress(address common.Address, backend bind.ContractBackend) (*Address, error) {
+	contract, err := bindAddress(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{AddressCaller: AddressCaller{contract: contract}, AddressTransactor: AddressTransactor{contract: contract}, AddressFilterer: AddressFilterer{contract: contract}}, nil
+}
+
+// NewAddressCaller creates a new read-only instance of Address, bound to a specific deployed contract.
+func NewAddressCaller(address common.Address, caller bind.ContractCaller) (*AddressCaller, error) {
+	contract, err := bindAddress(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressCaller{contract: contract}, nil
+}
+
+// NewAddressTransactor creates a new write-only instance of Address, bound to a specific deployed contract.
+func NewAddressTransactor(address common.Address, transactor bind.ContractTransactor) (*AddressTransactor, error) {
+	contract, err := bindAddress(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressTransactor{contract: contract}, nil
+}
+
+// NewAddressFilterer creates a new log filterer instance of Address, bound to a specific deployed contract.
+func NewAddressFilterer(address common.Address, filterer bind.ContractFilterer) (*AddressFilterer, error) {
+	contract, err := bindAddress(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressFilterer{contract: contract}, nil
+}
+
+// bindAddress binds a generic wrapper to an already deployed contract.
+func bindAddress(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := AddressMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Address *AddressRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Address.Contract.AddressCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Address *AddressRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Address.Contract.AddressTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Address *AddressRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Address.Contract.AddressTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Address *AddressCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Address.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Address *AddressTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Address.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Address *AddressTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Address.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(address)
+func (_Address *AddressCaller) Get(opts *bind.CallOpts, idx *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _Address.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(address)
+func (_Address *AddressSession) Get(idx *big.Int) (common.Address, error) {
+	return _Address.Contract.Get(&_Address.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(address)
+func (_Address *AddressCallerSession) Get(idx *big.Int) (common.Address, error) {
+	return _Address.Contract.Get(&_Address.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Address *AddressCaller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Address.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Address *AddressSession) Length() (*big.Int, error) {
+	return _Address.Contract.Length(&_Address.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Address *AddressCallerSession) Length() (*big.Int, error) {
+	return _Address.Contract.Length(&_Address.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Address *AddressTransactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Address.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Address *AddressSession) Clear() (*types.Transaction, error) {
+	return _Address.Contract.Clear(&_Address.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Address *AddressTransactorSession) Clear() (*types.Transaction, error) {
+	return _Address.Contract.Clear(&_Address.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(address)
+func (_Address *AddressTransactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Address.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(address)
+func (_Address *AddressSession) Pop() (*types.Transaction, error) {
+	return _Address.Contract.Pop(&_Address.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(address)
+func (_Address *AddressTransactorSession) Pop() (*types.Transaction, error) {
+	return _Address.Contract.Pop(&_Address.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x89b09de7.
+//
+// Solidity: function push(address elem) returns()
+func (_Address *AddressTransactor) Push(opts *bind.TransactOpts, elem common.Address) (*types.Transaction, error) {
+	return _Address.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x89b09de7.
+//
+// Solidity: function push(address elem) returns()
+func (_Address *AddressSession) Push(elem common.Address) (*types.Transaction, error) {
+	return _Address.Contract.Push(&_Address.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x89b09de7.
+//
+// Solidity: function push(address elem) returns()
+func (_Address *AddressTransactorSession) Push(elem common.Address) (*types.Transaction, error) {
+	return _Address.Contract.Push(&_Address.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x2f30c6f6.
+//
+// Solidity: function set(uint256 idx, address elem) returns()
+func (_Address *AddressTransactor) Set(opts *bind.TransactOpts, idx *big.Int, elem common.Address) (*types.Transaction, error) {
+	return _Address.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x2f30c6f6.
+//
+// Solidity: function set(uint256 idx, address elem) returns()
+func (_Address *AddressSession) Set(idx *big.Int, elem common.Address) (*types.Transaction, error) {
+	return _Address.Contract.Set(&_Address.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x2f30c6f6.
+//
+// Solidity: function set(uint256 idx, address elem) returns()
+func (_Address *AddressTransactorSession) Set(idx *big.Int, elem common.Address) (*types.Transaction, error) {
+	return _Address.Contract.Set(&_Address.TransactOpts, idx, elem)
+}
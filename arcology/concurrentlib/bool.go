@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// BoolMetaData contains all meta data concerning the Bool contract.
+var BoolMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"bool\",\"internalType\":\"bool\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// BoolABI is the input ABI used to generate the binding from.
+// Deprecated: Use BoolMetaData.ABI instead.
+var BoolABI = BoolMetaData.ABI
+
+// Bool is an auto generated Go binding around an Ethereum contract.
+type Bool struct {
+	BoolCaller     // Read-only binding to the contract
+	BoolTransactor // Write-only binding to the contract
+	BoolFilterer   // Log filterer for contract events
+}
+
+// BoolCaller is an auto generated read-only Go binding around an Ethereum contract.
+type BoolCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BoolTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type BoolTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BoolFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type BoolFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BoolSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type BoolSession struct {
+	Contract     *Bool             // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BoolCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type BoolCallerSession struct {
+	Contract *BoolCaller   // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// BoolTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type BoolTransactorSession struct {
+	Contract     *BoolTransactor   // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BoolRaw is an auto generated low-level Go binding around an Ethereum contract.
+type BoolRaw struct {
+	Contract *Bool // Generic contract binding to access the raw methods on
+}
+
+// BoolCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type BoolCallerRaw struct {
+	Contract *BoolCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// BoolTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type BoolTransactorRaw struct {
+	Contract *BoolTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBool creates a new instance of Bool, bound to a specific deployed contract.
+func NewBool(address common.Address, backend bind.ContractBackend) (*Bool, error) {
+	contract, err := bindBool(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Bool{BoolCaller: BoolCaller{contract: contract}, BoolTransactor: BoolTransactor{contract: contract}, BoolFilterer: BoolFilterer{contract: contract}}, nil
+}
+
+// NewBoolCaller creates a new read-only instance of Bool, bound to a specific deployed contract.
+func NewBoolCaller(address common.Address, caller bind.ContractCaller) (*BoolCaller, error) {
+	contract, err := bindBool(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BoolCaller{contract: contract}, nil
+}
+
+// NewBoolTransactor creates a new write-only instance of Bool, bound to a specific deployed contract.
+func NewBoolTransactor(address common.Address, transactor bind.ContractTransactor) (*BoolTransactor, error) {
+	contract, err := bindBool(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BoolTransactor{contract: contract}, nil
+}
+
+// NewBoolFilterer creates a new log filterer instance of Bool, bound to a specific deployed contract.
+func NewBoolFilterer(address common.Address, filterer bind.ContractFilterer) (*BoolFilterer, error) {
+	contract, err := bindBool(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &BoolFilterer{contract: contract}, nil
+}
+
+// bindBool binds a generic wrapper to an already deployed contract.
+func bindBool(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := BoolMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bool *BoolRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bool.Contract.BoolCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bool *BoolRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bool.Contract.BoolTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bool *BoolRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bool.Contract.BoolTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bool *BoolCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bool.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bool *BoolTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bool.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bool *BoolTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bool.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bool)
+func (_Bool *BoolCaller) Get(opts *bind.CallOpts, idx *big.Int) (bool, error) {
+	var out []interface{}
+	err := _Bool.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bool)
+func (_Bool *BoolSession) Get(idx *big.Int) (bool, error) {
+	return _Bool.Contract.Get(&_Bool.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bool)
+func (_Bool *BoolCallerSession) Get(idx *big.Int) (bool, error) {
+	return _Bool.Contract.Get(&_Bool.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bool *BoolCaller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Bool.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bool *BoolSession) Length() (*big.Int, error) {
+	return _Bool.Contract.Length(&_Bool.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bool *BoolCallerSession) Length() (*big.Int, error) {
+	return _Bool.Contract.Length(&_Bool.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bool *BoolTransactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bool.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bool *BoolSession) Clear() (*types.Transaction, error) {
+	return _Bool.Contract.Clear(&_Bool.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bool *BoolTransactorSession) Clear() (*types.Transaction, error) {
+	return _Bool.Contract.Clear(&_Bool.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bool)
+func (_Bool *BoolTransactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bool.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bool)
+func (_Bool *BoolSession) Pop() (*types.Transaction, error) {
+	return _Bool.Contract.Pop(&_Bool.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bool)
+func (_Bool *BoolTransactorSession) Pop() (*types.Transaction, error) {
+	return _Bool.Contract.Pop(&_Bool.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xdf08e005.
+//
+// Solidity: function push(bool elem) returns()
+func (_Bool *BoolTransactor) Push(opts *bind.TransactOpts, elem bool) (*types.Transaction, error) {
+	return _Bool.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xdf08e005.
+//
+// Solidity: function push(bool elem) returns()
+func (_Bool *BoolSession) Push(elem bool) (*types.Transaction, error) {
+	return _Bool.Contract.Push(&_Bool.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xdf08e005.
+//
+// Solidity: function push(bool elem) returns()
+func (_Bool *BoolTransactorSession) Push(elem bool) (*types.Transaction, error) {
+	return _Bool.Contract.Push(&_Bool.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x62f46d56.
+//
+// Solidity: function set(uint256 idx, bool elem) returns()
+func (_Bool *BoolTransactor) Set(opts *bind.TransactOpts, idx *big.Int, elem bool) (*types.Transaction, error) {
+	return _Bool.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x62f46d56.
+//
+// Solidity: function set(uint256 idx, bool elem) returns()
+func (_Bool *BoolSession) Set(idx *big.Int, elem bool) (*types.Transaction, error) {
+	return _Bool.Contract.Set(&_Bool.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x62f46d56.
+//
+// Solidity: function set(uint256 idx, bool elem) returns()
+func (_Bool *BoolTransactorSession) Set(idx *big.Int, elem bool) (*types.Transaction, error) {
+	return _Bool.Contract.Set(&_Bool.TransactOpts, idx, elem)
+}
@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// BytesMetaData contains all meta data concerning the Bytes contract.
+var BytesMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// BytesABI is the input ABI used to generate the binding from.
+// Deprecated: Use BytesMetaData.ABI instead.
+var BytesABI = BytesMetaData.ABI
+
+// Bytes is an auto generated Go binding around an Ethereum contract.
+type Bytes struct {
+	BytesCaller     // Read-only binding to the contract
+	BytesTransactor // Write-only binding to the contract
+	BytesFilterer   // Log filterer for contract events
+}
+
+// BytesCaller is an auto generated read-only Go binding around an Ethereum contract.
+type BytesCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BytesTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type BytesTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BytesFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type BytesFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BytesSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type BytesSession struct {
+	Contract     *Bytes            // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BytesCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type BytesCallerSession struct {
+	Contract *BytesCaller  // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// BytesTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type BytesTransactorSession struct {
+	Contract     *BytesTransactor  // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BytesRaw is an auto generated low-level Go binding around an Ethereum contract.
+type BytesRaw struct {
+	Contract *Bytes // Generic contract binding to access the raw methods on
+}
+
+// BytesCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type BytesCallerRaw struct {
+	Contract *BytesCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// BytesTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type BytesTransactorRaw struct {
+	Contract *BytesTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBytes creates a new instance of Bytes, bound to a specific deployed contract.
+func NewBytes(address common.Address, backend bind.ContractBackend) (*Bytes, error) {
+	contract, err := bindBytes(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Bytes{BytesCaller: BytesCaller{contract: contract}, BytesTransactor: BytesTransactor{contract: contract}, BytesFilterer: BytesFilterer{contract: contract}}, nil
+}
+
+// NewBytesCaller creates a new read-only instance of Bytes, bound to a specific deployed contract.
+func NewBytesCaller(address common.Address, caller bind.ContractCaller) (*BytesCaller, error) {
+	contract, err := bindBytes(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BytesCaller{contract: contract}, nil
+}
+
+// NewBytesTransactor creates a new write-only instance of Bytes, bound to a specific deployed contract.
+func NewBytesTransactor(address common.Address, transactor bind.ContractTransactor) (*BytesTransactor, error) {
+	contract, err := bindBytes(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BytesTransactor{contract: contract}, nil
+}
+
+// NewBytesFilterer creates a new log filterer instance of Bytes, bound to a specific deployed contract.
+func NewBytesFilterer(address common.Address, filterer bind.ContractFilterer) (*BytesFilterer, error) {
+	contract, err := bindBytes(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &BytesFilterer{contract: contract}, nil
+}
+
+// bindBytes binds a generic wrapper to an already deployed contract.
+func bindBytes(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := BytesMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bytes *BytesRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bytes.Contract.BytesCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bytes *BytesRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes.Contract.BytesTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bytes *BytesRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bytes.Contract.BytesTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bytes *BytesCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bytes.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bytes *BytesTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bytes *BytesTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bytes.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes)
+func (_Bytes *BytesCaller) Get(opts *bind.CallOpts, idx *big.Int) ([]byte, error) {
+	var out []interface{}
+	err := _Bytes.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes)
+func (_Bytes *BytesSession) Get(idx *big.Int) ([]byte, error) {
+	return _Bytes.Contract.Get(&_Bytes.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes)
+func (_Bytes *BytesCallerSession) Get(idx *big.Int) ([]byte, error) {
+	return _Bytes.Contract.Get(&_Bytes.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes *BytesCaller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Bytes.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes *BytesSession) Length() (*big.Int, error) {
+	return _Bytes.Contract.Length(&_Bytes.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes *BytesCallerSession) Length() (*big.Int, error) {
+	return _Bytes.Contract.Length(&_Bytes.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes *BytesTransactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes *BytesSession) Clear() (*types.Transaction, error) {
+	return _Bytes.Contract.Clear(&_Bytes.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes *BytesTransactorSession) Clear() (*types.Transaction, error) {
+	return _Bytes.Contract.Clear(&_Bytes.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes)
+func (_Bytes *BytesTransactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes)
+func (_Bytes *BytesSession) Pop() (*types.Transaction, error) {
+	return _Bytes.Contract.Pop(&_Bytes.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes)
+func (_Bytes *BytesTransactorSession) Pop() (*types.Transaction, error) {
+	return _Bytes.Contract.Pop(&_Bytes.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x7dacda03.
+//
+// Solidity: function push(bytes elem) returns()
+func (_Bytes *BytesTransactor) Push(opts *bind.TransactOpts, elem []byte) (*types.Transaction, error) {
+	return _Bytes.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x7dacda03.
+//
+// Solidity: function push(bytes elem) returns()
+func (_Bytes *BytesSession) Push(elem []byte) (*types.Transaction, error) {
+	return _Bytes.Contract.Push(&_Bytes.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x7dacda03.
+//
+// Solidity: function push(bytes elem) returns()
+func (_Bytes *BytesTransactorSession) Push(elem []byte) (*types.Transaction, error) {
+	return _Bytes.Contract.Push(&_Bytes.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x8b282947.
+//
+// Solidity: function set(uint256 idx, bytes elem) returns()
+func (_Bytes *BytesTransactor) Set(opts *bind.TransactOpts, idx *big.Int, elem []byte) (*types.Transaction, error) {
+	return _Bytes.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x8b282947.
+//
+// Solidity: function set(uint256 idx, bytes elem) returns()
+func (_Bytes *BytesSession) Set(idx *big.Int, elem []byte) (*types.Transaction, error) {
+	return _Bytes.Contract.Set(&_Bytes.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x8b282947.
+//
+// Solidity: function set(uint256 idx, bytes elem) returns()
+func (_Bytes *BytesTransactorSession) Set(idx *big.Int, elem []byte) (*types.Transaction, error) {
+	return _Bytes.Contract.Set(&_Bytes.TransactOpts, idx, elem)
+}
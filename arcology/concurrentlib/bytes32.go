@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// Bytes32MetaData contains all meta data concerning the Bytes32 contract.
+var Bytes32MetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"bytes32\",\"internalType\":\"bytes32\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// Bytes32ABI is the input ABI used to generate the binding from.
+// Deprecated: Use Bytes32MetaData.ABI instead.
+var Bytes32ABI = Bytes32MetaData.ABI
+
+// Bytes32 is an auto generated Go binding around an Ethereum contract.
+type Bytes32 struct {
+	Bytes32Caller     // Read-only binding to the contract
+	Bytes32Transactor // Write-only binding to the contract
+	Bytes32Filterer   // Log filterer for contract events
+}
+
+// Bytes32Caller is an auto generated read-only Go binding around an Ethereum contract.
+type Bytes32Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Bytes32Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type Bytes32Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Bytes32Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type Bytes32Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// Bytes32Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type Bytes32Session struct {
+	Contract     *Bytes32          // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// Bytes32CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type Bytes32CallerSession struct {
+	Contract *Bytes32Caller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts  // Call options to use throughout this session
+}
+
+// Bytes32TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type Bytes32TransactorSession struct {
+	Contract     *Bytes32Transactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// Bytes32Raw is an auto generated low-level Go binding around an Ethereum contract.
+type Bytes32Raw struct {
+	Contract *Bytes32 // Generic contract binding to access the raw methods on
+}
+
+// Bytes32CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type Bytes32CallerRaw struct {
+	Contract *Bytes32Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// Bytes32TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type Bytes32TransactorRaw struct {
+	Contract *Bytes32Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBytes32 creates a new instance of Bytes32, bound to a specific deployed contract.
+func NewBytes32(address common.Address, backend bind.ContractBackend) (*Bytes32, error) {
+	contract, err := bindBytes32(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Bytes32{Bytes32Caller: Bytes32Caller{contract: contract}, Bytes32Transactor: Bytes32Transactor{contract: contract}, Bytes32Filterer: Bytes32Filterer{contract: contract}}, nil
+}
+
+// NewBytes32Caller creates a new read-only instance of Bytes32, bound to a specific deployed contract.
+func NewBytes32Caller(address common.Address, caller bind.ContractCaller) (*Bytes32Caller, error) {
+	contract, err := bindBytes32(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Bytes32Caller{contract: contract}, nil
+}
+
+// NewBytes32Transactor creates a new write-only instance of Bytes32, bound to a specific deployed contract.
+func NewBytes32Transactor(address common.Address, transactor bind.ContractTransactor) (*Bytes32Transactor, error) {
+	contract, err := bindBytes32(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Bytes32Transactor{contract: contract}, nil
+}
+
+// NewBytes32Filterer creates a new log filterer instance of Bytes32, bound to a specific deployed contract.
+func NewBytes32Filterer(address common.Address, filterer bind.ContractFilterer) (*Bytes32Filterer, error) {
+	contract, err := bindBytes32(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &Bytes32Filterer{contract: contract}, nil
+}
+
+// bindBytes32 binds a generic wrapper to an already deployed contract.
+func bindBytes32(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := Bytes32MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bytes32 *Bytes32Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bytes32.Contract.Bytes32Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bytes32 *Bytes32Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes32.Contract.Bytes32Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bytes32 *Bytes32Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bytes32.Contract.Bytes32Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Bytes32 *Bytes32CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Bytes32.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Bytes32 *Bytes32TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes32.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Bytes32 *Bytes32TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Bytes32.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes32)
+func (_Bytes32 *Bytes32Caller) Get(opts *bind.CallOpts, idx *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _Bytes32.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes32)
+func (_Bytes32 *Bytes32Session) Get(idx *big.Int) ([32]byte, error) {
+	return _Bytes32.Contract.Get(&_Bytes32.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(bytes32)
+func (_Bytes32 *Bytes32CallerSession) Get(idx *big.Int) ([32]byte, error) {
+	return _Bytes32.Contract.Get(&_Bytes32.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes32 *Bytes32Caller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Bytes32.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes32 *Bytes32Session) Length() (*big.Int, error) {
+	return _Bytes32.Contract.Length(&_Bytes32.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Bytes32 *Bytes32CallerSession) Length() (*big.Int, error) {
+	return _Bytes32.Contract.Length(&_Bytes32.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes32 *Bytes32Transactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes32.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes32 *Bytes32Session) Clear() (*types.Transaction, error) {
+	return _Bytes32.Contract.Clear(&_Bytes32.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Bytes32 *Bytes32TransactorSession) Clear() (*types.Transaction, error) {
+	return _Bytes32.Contract.Clear(&_Bytes32.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes32)
+func (_Bytes32 *Bytes32Transactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Bytes32.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes32)
+func (_Bytes32 *Bytes32Session) Pop() (*types.Transaction, error) {
+	return _Bytes32.Contract.Pop(&_Bytes32.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(bytes32)
+func (_Bytes32 *Bytes32TransactorSession) Pop() (*types.Transaction, error) {
+	return _Bytes32.Contract.Pop(&_Bytes32.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xb298e36b.
+//
+// Solidity: function push(bytes32 elem) returns()
+func (_Bytes32 *Bytes32Transactor) Push(opts *bind.TransactOpts, elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xb298e36b.
+//
+// Solidity: function push(bytes32 elem) returns()
+func (_Bytes32 *Bytes32Session) Push(elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.Contract.Push(&_Bytes32.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0xb298e36b.
+//
+// Solidity: function push(bytes32 elem) returns()
+func (_Bytes32 *Bytes32TransactorSession) Push(elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.Contract.Push(&_Bytes32.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64c4ef1a.
+//
+// Solidity: function set(uint256 idx, bytes32 elem) returns()
+func (_Bytes32 *Bytes32Transactor) Set(opts *bind.TransactOpts, idx *big.Int, elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64c4ef1a.
+//
+// Solidity: function set(uint256 idx, bytes32 elem) returns()
+func (_Bytes32 *Bytes32Session) Set(idx *big.Int, elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.Contract.Set(&_Bytes32.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64c4ef1a.
+//
+// Solidity: function set(uint256 idx, bytes32 elem) returns()
+func (_Bytes32 *Bytes32TransactorSession) Set(idx *big.Int, elem [32]byte) (*types.Transaction, error) {
+	return _Bytes32.Contract.Set(&_Bytes32.TransactOpts, idx, elem)
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package concurrentlib contains the Go bindings of the Arcology concurrent
+// library contracts, the conflict-free containers, cumulative variables and job
+// runner letting contracts take advantage of the parallel execution of Arcology.
+//
+// The contract ABIs are kept in the abi directory, the bindings are generated
+// from them with abigen.
+package concurrentlib
+
+import "github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+//go:generate go run ../../cmd/abigen --abi abi/Address.abi --pkg concurrentlib --type Address --out address.go
+//go:generate go run ../../cmd/abigen --abi abi/Bool.abi --pkg concurrentlib --type Bool --out bool.go
+//go:generate go run ../../cmd/abigen --abi abi/Bytes.abi --pkg concurrentlib --type Bytes --out bytes.go
+//go:generate go run ../../cmd/abigen --abi abi/Bytes32.abi --pkg concurrentlib --type Bytes32 --out bytes32.go
+//go:generate go run ../../cmd/abigen --abi abi/String.abi --pkg concurrentlib --type String --out string.go
+//go:generate go run ../../cmd/abigen --abi abi/U256.abi --pkg concurrentlib --type U256 --out u256.go
+//go:generate go run ../../cmd/abigen --abi abi/U256Cumulative.abi --pkg concurrentlib --type U256Cumulative --out u256cumulative.go
+//go:generate go run ../../cmd/abigen --abi abi/Multiprocess.abi --pkg concurrentlib --type Multiprocess --out multiprocess.go
+
+// Contracts maps the names of the concurrent library contracts to the meta data
+// of their bindings.
+var Contracts = map[string]*bind.MetaData{
+	"Address":        AddressMetaData,
+	"Bool":           BoolMetaData,
+	"Bytes":          BytesMetaData,
+	"Bytes32":        Bytes32MetaData,
+	"String":         StringMetaData,
+	"U256":           U256MetaData,
+	"U256Cumulative": U256CumulativeMetaData,
+	"Multiprocess":   MultiprocessMetaData,
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package concurrentlib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Tests that the bindings are up to date with the contract ABIs.
+func TestBindingsUpToDate(t *testing.T) {
+	for name, meta := range Contracts {
+		abi, err := os.ReadFile(filepath.Join("abi", name+".abi"))
+		if err != nil {
+			t.Fatalf("%s: failed to read abi: %v", name, err)
+		}
+		if _, err := meta.GetAbi(); err != nil {
+			t.Fatalf("%s: failed to parse abi: %v", name, err)
+		}
+		code, err := bind.Bind([]string{name}, []string{string(abi)}, []string{""}, nil, "concurrentlib", bind.LangGo, nil, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to generate binding: %v", name, err)
+		}
+		have, err := os.ReadFile(strings.ToLower(name) + ".go")
+		if err != nil {
+			t.Fatalf("%s: failed to read binding: %v", name, err)
+		}
+		if string(have) != code {
+			t.Errorf("%s: binding out of date, run go generate", name)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// MultiprocessMetaData contains all meta data concerning the Multiprocess contract.
+var MultiprocessMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"threads\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addJob\",\"inputs\":[{\"name\":\"gasLimit\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"contractAddr\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"funcCall\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"run\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// MultiprocessABI is the input ABI used to generate the binding from.
+// Deprecated: Use MultiprocessMetaData.ABI instead.
+var MultiprocessABI = MultiprocessMetaData.ABI
+
+// Multiprocess is an auto generated Go binding around an Ethereum contract.
+type Multiprocess struct {
+	MultiprocessCaller     // Read-only binding to the contract
+	MultiprocessTransactor // Write-only binding to the contract
+	MultiprocessFilterer   // Log filterer for contract events
+}
+
+// MultiprocessCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MultiprocessCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiprocessTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MultiprocessTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiprocessFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MultiprocessFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiprocessSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MultiprocessSession struct {
+	Contract     *Multiprocess     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MultiprocessCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MultiprocessCallerSession struct {
+	Contract *MultiprocessCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// MultiprocessTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MultiprocessTransactorSession struct {
+	Contract     *MultiprocessTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// MultiprocessRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MultiprocessRaw struct {
+	Contract *Multiprocess // Generic contract binding to access the raw methods on
+}
+
+// MultiprocessCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MultiprocessCallerRaw struct {
+	Contract *MultiprocessCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MultiprocessTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MultiprocessTransactorRaw struct {
+	Contract *MultiprocessTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMultiprocess creates a new instance of Multiprocess, bound to a specific deployed contract.
+func NewMultiprocess(address common.Address, backend bind.ContractBackend) (*Multiprocess, error) {
+	contract, err := bindMultiprocess(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Multiprocess{MultiprocessCaller: MultiprocessCaller{contract: contract}, MultiprocessTransactor: MultiprocessTransactor{contract: contract}, MultiprocessFilterer: MultiprocessFilterer{contract: contract}}, nil
+}
+
+// NewMultiprocessCaller creates a new read-only instance of Multiprocess, bound to a specific deployed contract.
+func NewMultiprocessCaller(address common.Address, caller bind.ContractCaller) (*MultiprocessCaller, error) {
+	contract, err := bindMultiprocess(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiprocessCaller{contract: contract}, nil
+}
+
+// NewMultiprocessTransactor creates a new write-only instance of Multiprocess, bound to a specific deployed contract.
+func NewMultiprocessTransactor(address common.Address, transactor bind.ContractTransactor) (*MultiprocessTransactor, error) {
+	contract, err := bindMultiprocess(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiprocessTransactor{contract: contract}, nil
+}
+
+// NewMultiprocessFilterer creates a new log filterer instance of Multiprocess, bound to a specific deployed contract.
+func NewMultiprocessFilterer(address common.Address, filterer bind.ContractFilterer) (*MultiprocessFilterer, error) {
+	contract, err := bindMultiprocess(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiprocessFilterer{contract: contract}, nil
+}
+
+// bindMultiprocess binds a generic wrapper to an already deployed contract.
+func bindMultiprocess(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := MultiprocessMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Multiprocess *MultiprocessRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Multiprocess.Contract.MultiprocessCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Multiprocess *MultiprocessRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Multiprocess.Contract.MultiprocessTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Multiprocess *MultiprocessRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Multiprocess.Contract.MultiprocessTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Multiprocess *MultiprocessCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Multiprocess.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Multiprocess *MultiprocessTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Multiprocess.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Multiprocess *MultiprocessTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Multiprocess.Contract.contract.Transact(opts, method, params...)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Multiprocess *MultiprocessCaller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Multiprocess.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Multiprocess *MultiprocessSession) Length() (*big.Int, error) {
+	return _Multiprocess.Contract.Length(&_Multiprocess.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_Multiprocess *MultiprocessCallerSession) Length() (*big.Int, error) {
+	return _Multiprocess.Contract.Length(&_Multiprocess.CallOpts)
+}
+
+// AddJob is a paid mutator transaction binding the contract method 0xed51b389.
+//
+// Solidity: function addJob(uint256 gasLimit, address contractAddr, bytes funcCall) returns()
+func (_Multiprocess *MultiprocessTransactor) AddJob(opts *bind.TransactOpts, gasLimit *big.Int, contractAddr common.Address, funcCall []byte) (*types.Transaction, error) {
+	return _Multiprocess.contract.Transact(opts, "addJob", gasLimit, contractAddr, funcCall)
+}
+
+// AddJob is a paid mutator transaction binding the contract method 0xed51b389.
+//
+// Solidity: function addJob(uint256 gasLimit, address contractAddr, bytes funcCall) returns()
+func (_Multiprocess *MultiprocessSession) AddJob(gasLimit *big.Int, contractAddr common.Address, funcCall []byte) (*types.Transaction, error) {
+	return _Multiprocess.Contract.AddJob(&_Multiprocess.TransactOpts, gasLimit, contractAddr, funcCall)
+}
+
+// AddJob is a paid mutator transaction binding the contract method 0xed51b389.
+//
+// Solidity: function addJob(uint256 gasLimit, address contractAddr, bytes funcCall) returns()
+func (_Multiprocess *MultiprocessTransactorSession) AddJob(gasLimit *big.Int, contractAddr common.Address, funcCall []byte) (*types.Transaction, error) {
+	return _Multiprocess.Contract.AddJob(&_Multiprocess.TransactOpts, gasLimit, contractAddr, funcCall)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Multiprocess *MultiprocessTransactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Multiprocess.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Multiprocess *MultiprocessSession) Clear() (*types.Transaction, error) {
+	return _Multiprocess.Contract.Clear(&_Multiprocess.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_Multiprocess *MultiprocessTransactorSession) Clear() (*types.Transaction, error) {
+	return _Multiprocess.Contract.Clear(&_Multiprocess.TransactOpts)
+}
+
+// Run is a paid mutator transaction binding the contract method 0xc0406226.
+//
+// Solidity: function run() returns()
+func (_Multiprocess *MultiprocessTransactor) Run(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Multiprocess.contract.Transact(opts, "run")
+}
+
+// Run is a paid mutator transaction binding the contract method 0xc0406226.
+//
+// Solidity: function run() returns()
+func (_Multiprocess *MultiprocessSession) Run() (*types.Transaction, error) {
+	return _Multiprocess.Contract.Run(&_Multiprocess.TransactOpts)
+}
+
+// Run is a paid mutator transaction binding the contract method 0xc0406226.
+//
+// Solidity: function run() returns()
+func (_Multiprocess *MultiprocessTransactorSession) Run() (*types.Transaction, error) {
+	return _Multiprocess.Contract.Run(&_Multiprocess.TransactOpts)
+}
@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// StringMetaData contains all meta data concerning the String contract.
+var StringMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"string\",\"internalType\":\"string\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// StringABI is the input ABI used to generate the binding from.
+// Deprecated: Use StringMetaData.ABI instead.
+var StringABI = StringMetaData.ABI
+
+// String is an auto generated Go binding around an Ethereum contract.
+type String struct {
+	StringCaller     // Read-only binding to the contract
+	StringTransactor // Write-only binding to the contract
+	StringFilterer   // Log filterer for contract events
+}
+
+// StringCaller is an auto generated read-only Go binding around an Ethereum contract.
+type StringCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StringTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type StringTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StringFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type StringFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StringSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type StringSession struct {
+	Contract     *String           // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// StringCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type StringCallerSession struct {
+	Contract *StringCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// StringTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type StringTransactorSession struct {
+	Contract     *StringTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// StringRaw is an auto generated low-level Go binding around an Ethereum contract.
+type StringRaw struct {
+	Contract *String // Generic contract binding to access the raw methods on
+}
+
+// StringCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type StringCallerRaw struct {
+	Contract *StringCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// StringTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type StringTransactorRaw struct {
+	Contract *StringTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewString creates a new instance of String, bound to a specific deployed contract.
+func NewString(address common.Address, backend bind.ContractBackend) (*String, error) {
+	contract, err := bindString(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &String{StringCaller: StringCaller{contract: contract}, StringTransactor: StringTransactor{contract: contract}, StringFilterer: StringFilterer{contract: contract}}, nil
+}
+
+// NewStringCaller creates a new read-only instance of String, bound to a specific deployed contract.
+func NewStringCaller(address common.Address, caller bind.ContractCaller) (*StringCaller, error) {
+	contract, err := bindString(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StringCaller{contract: contract}, nil
+}
+
+// NewStringTransactor creates a new write-only instance of String, bound to a specific deployed contract.
+func NewStringTransactor(address common.Address, transactor bind.ContractTransactor) (*StringTransactor, error) {
+	contract, err := bindString(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StringTransactor{contract: contract}, nil
+}
+
+// NewStringFilterer creates a new log filterer instance of String, bound to a specific deployed contract.
+func NewStringFilterer(address common.Address, filterer bind.ContractFilterer) (*StringFilterer, error) {
+	contract, err := bindString(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &StringFilterer{contract: contract}, nil
+}
+
+// bindString binds a generic wrapper to an already deployed contract.
+func bindString(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := StringMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_String *StringRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _String.Contract.StringCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_String *StringRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _String.Contract.StringTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_String *StringRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _String.Contract.StringTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_String *StringCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _String.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_String *StringTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _String.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_String *StringTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _String.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(string)
+func (_String *StringCaller) Get(opts *bind.CallOpts, idx *big.Int) (string, error) {
+	var out []interface{}
+	err := _String.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(string)
+func (_String *StringSession) Get(idx *big.Int) (string, error) {
+	return _String.Contract.Get(&_String.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(string)
+func (_String *StringCallerSession) Get(idx *big.Int) (string, error) {
+	return _String.Contract.Get(&_String.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_String *StringCaller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _String.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_String *StringSession) Length() (*big.Int, error) {
+	return _String.Contract.Length(&_String.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_String *StringCallerSession) Length() (*big.Int, error) {
+	return _String.Contract.Length(&_String.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_String *StringTransactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _String.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_String *StringSession) Clear() (*types.Transaction, error) {
+	return _String.Contract.Clear(&_String.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_String *StringTransactorSession) Clear() (*types.Transaction, error) {
+	return _String.Contract.Clear(&_String.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(string)
+func (_String *StringTransactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _String.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(string)
+func (_String *StringSession) Pop() (*types.Transaction, error) {
+	return _String.Contract.Pop(&_String.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(string)
+func (_String *StringTransactorSession) Pop() (*types.Transaction, error) {
+	return _String.Contract.Pop(&_String.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x4f3d057a.
+//
+// Solidity: function push(string elem) returns()
+func (_String *StringTransactor) Push(opts *bind.TransactOpts, elem string) (*types.Transaction, error) {
+	return _String.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x4f3d057a.
+//
+// Solidity: function push(string elem) returns()
+func (_String *StringSession) Push(elem string) (*types.Transaction, error) {
+	return _String.Contract.Push(&_String.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x4f3d057a.
+//
+// Solidity: function push(string elem) returns()
+func (_String *StringTransactorSession) Push(elem string) (*types.Transaction, error) {
+	return _String.Contract.Push(&_String.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64371977.
+//
+// Solidity: function set(uint256 idx, string elem) returns()
+func (_String *StringTransactor) Set(opts *bind.TransactOpts, idx *big.Int, elem string) (*types.Transaction, error) {
+	return _String.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64371977.
+//
+// Solidity: function set(uint256 idx, string elem) returns()
+func (_String *StringSession) Set(idx *big.Int, elem string) (*types.Transaction, error) {
+	return _String.Contract.Set(&_String.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x64371977.
+//
+// Solidity: function set(uint256 idx, string elem) returns()
+func (_String *StringTransactorSession) Set(idx *big.Int, elem string) (*types.Transaction, error) {
+	return _String.Contract.Set(&_String.TransactOpts, idx, elem)
+}
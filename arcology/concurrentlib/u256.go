@@ -0,0 +1,331 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// U256MetaData contains all meta data concerning the U256 contract.
+var U256MetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"clear\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"length\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"pop\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"push\",\"inputs\":[{\"name\":\"elem\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"set\",\"inputs\":[{\"name\":\"idx\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"elem\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// U256ABI is the input ABI used to generate the binding from.
+// Deprecated: Use U256MetaData.ABI instead.
+var U256ABI = U256MetaData.ABI
+
+// U256 is an auto generated Go binding around an Ethereum contract.
+type U256 struct {
+	U256Caller     // Read-only binding to the contract
+	U256Transactor // Write-only binding to the contract
+	U256Filterer   // Log filterer for contract events
+}
+
+// U256Caller is an auto generated read-only Go binding around an Ethereum contract.
+type U256Caller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256Transactor is an auto generated write-only Go binding around an Ethereum contract.
+type U256Transactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256Filterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type U256Filterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256Session is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type U256Session struct {
+	Contract     *U256             // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// U256CallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type U256CallerSession struct {
+	Contract *U256Caller   // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// U256TransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type U256TransactorSession struct {
+	Contract     *U256Transactor   // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// U256Raw is an auto generated low-level Go binding around an Ethereum contract.
+type U256Raw struct {
+	Contract *U256 // Generic contract binding to access the raw methods on
+}
+
+// U256CallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type U256CallerRaw struct {
+	Contract *U256Caller // Generic read-only contract binding to access the raw methods on
+}
+
+// U256TransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type U256TransactorRaw struct {
+	Contract *U256Transactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewU256 creates a new instance of U256, bound to a specific deployed contract.
+func NewU256(address common.Address, backend bind.ContractBackend) (*U256, error) {
+	contract, err := bindU256(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &U256{U256Caller: U256Caller{contract: contract}, U256Transactor: U256Transactor{contract: contract}, U256Filterer: U256Filterer{contract: contract}}, nil
+}
+
+// NewU256Caller creates a new read-only instance of U256, bound to a specific deployed contract.
+func NewU256Caller(address common.Address, caller bind.ContractCaller) (*U256Caller, error) {
+	contract, err := bindU256(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &U256Caller{contract: contract}, nil
+}
+
+// NewU256Transactor creates a new write-only instance of U256, bound to a specific deployed contract.
+func NewU256Transactor(address common.Address, transactor bind.ContractTransactor) (*U256Transactor, error) {
+	contract, err := bindU256(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &U256Transactor{contract: contract}, nil
+}
+
+// NewU256Filterer creates a new log filterer instance of U256, bound to a specific deployed contract.
+func NewU256Filterer(address common.Address, filterer bind.ContractFilterer) (*U256Filterer, error) {
+	contract, err := bindU256(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &U256Filterer{contract: contract}, nil
+}
+
+// bindU256 binds a generic wrapper to an already deployed contract.
+func bindU256(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := U256MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_U256 *U256Raw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _U256.Contract.U256Caller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_U256 *U256Raw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256.Contract.U256Transactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_U256 *U256Raw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _U256.Contract.U256Transactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_U256 *U256CallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _U256.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_U256 *U256TransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_U256 *U256TransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _U256.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(uint256)
+func (_U256 *U256Caller) Get(opts *bind.CallOpts, idx *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _U256.contract.Call(opts, &out, "get", idx)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(uint256)
+func (_U256 *U256Session) Get(idx *big.Int) (*big.Int, error) {
+	return _U256.Contract.Get(&_U256.CallOpts, idx)
+}
+
+// Get is a free data retrieval call binding the contract method 0x9507d39a.
+//
+// Solidity: function get(uint256 idx) view returns(uint256)
+func (_U256 *U256CallerSession) Get(idx *big.Int) (*big.Int, error) {
+	return _U256.Contract.Get(&_U256.CallOpts, idx)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_U256 *U256Caller) Length(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _U256.contract.Call(opts, &out, "length")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_U256 *U256Session) Length() (*big.Int, error) {
+	return _U256.Contract.Length(&_U256.CallOpts)
+}
+
+// Length is a free data retrieval call binding the contract method 0x1f7b6d32.
+//
+// Solidity: function length() view returns(uint256)
+func (_U256 *U256CallerSession) Length() (*big.Int, error) {
+	return _U256.Contract.Length(&_U256.CallOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_U256 *U256Transactor) Clear(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256.contract.Transact(opts, "clear")
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_U256 *U256Session) Clear() (*types.Transaction, error) {
+	return _U256.Contract.Clear(&_U256.TransactOpts)
+}
+
+// Clear is a paid mutator transaction binding the contract method 0x52efea6e.
+//
+// Solidity: function clear() returns()
+func (_U256 *U256TransactorSession) Clear() (*types.Transaction, error) {
+	return _U256.Contract.Clear(&_U256.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(uint256)
+func (_U256 *U256Transactor) Pop(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256.contract.Transact(opts, "pop")
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(uint256)
+func (_U256 *U256Session) Pop() (*types.Transaction, error) {
+	return _U256.Contract.Pop(&_U256.TransactOpts)
+}
+
+// Pop is a paid mutator transaction binding the contract method 0xa4ece52c.
+//
+// Solidity: function pop() returns(uint256)
+func (_U256 *U256TransactorSession) Pop() (*types.Transaction, error) {
+	return _U256.Contract.Pop(&_U256.TransactOpts)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x959ac484.
+//
+// Solidity: function push(uint256 elem) returns()
+func (_U256 *U256Transactor) Push(opts *bind.TransactOpts, elem *big.Int) (*types.Transaction, error) {
+	return _U256.contract.Transact(opts, "push", elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x959ac484.
+//
+// Solidity: function push(uint256 elem) returns()
+func (_U256 *U256Session) Push(elem *big.Int) (*types.Transaction, error) {
+	return _U256.Contract.Push(&_U256.TransactOpts, elem)
+}
+
+// Push is a paid mutator transaction binding the contract method 0x959ac484.
+//
+// Solidity: function push(uint256 elem) returns()
+func (_U256 *U256TransactorSession) Push(elem *big.Int) (*types.Transaction, error) {
+	return _U256.Contract.Push(&_U256.TransactOpts, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x1ab06ee5.
+//
+// Solidity: function set(uint256 idx, uint256 elem) returns()
+func (_U256 *U256Transactor) Set(opts *bind.TransactOpts, idx *big.Int, elem *big.Int) (*types.Transaction, error) {
+	return _U256.contract.Transact(opts, "set", idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x1ab06ee5.
+//
+// Solidity: function set(uint256 idx, uint256 elem) returns()
+func (_U256 *U256Session) Set(idx *big.Int, elem *big.Int) (*types.Transaction, error) {
+	return _U256.Contract.Set(&_U256.TransactOpts, idx, elem)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x1ab06ee5.
+//
+// Solidity: function set(uint256 idx, uint256 elem) returns()
+func (_U256 *U256TransactorSession) Set(idx *big.Int, elem *big.Int) (*types.Transaction, error) {
+	return _U256.Contract.Set(&_U256.TransactOpts, idx, elem)
+}
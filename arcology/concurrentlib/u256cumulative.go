@@ -0,0 +1,320 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package concurrentlib
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = fmt.Sprintf
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// U256CumulativeMetaData contains all meta data concerning the U256Cumulative contract.
+var U256CumulativeMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"constructor\",\"inputs\":[{\"name\":\"minv\",\"type\":\"uint256\",\"internalType\":\"uint256\"},{\"name\":\"maxv\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"add\",\"inputs\":[{\"name\":\"delta\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"get\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"max\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"min\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"sub\",\"inputs\":[{\"name\":\"delta\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
+}
+
+// U256CumulativeABI is the input ABI used to generate the binding from.
+// Deprecated: Use U256CumulativeMetaData.ABI instead.
+var U256CumulativeABI = U256CumulativeMetaData.ABI
+
+// U256Cumulative is an auto generated Go binding around an Ethereum contract.
+type U256Cumulative struct {
+	U256CumulativeCaller     // Read-only binding to the contract
+	U256CumulativeTransactor // Write-only binding to the contract
+	U256CumulativeFilterer   // Log filterer for contract events
+}
+
+// U256CumulativeCaller is an auto generated read-only Go binding around an Ethereum contract.
+type U256CumulativeCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256CumulativeTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type U256CumulativeTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256CumulativeFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type U256CumulativeFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// U256CumulativeSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type U256CumulativeSession struct {
+	Contract     *U256Cumulative   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// U256CumulativeCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type U256CumulativeCallerSession struct {
+	Contract *U256CumulativeCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// U256CumulativeTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type U256CumulativeTransactorSession struct {
+	Contract     *U256CumulativeTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// U256CumulativeRaw is an auto generated low-level Go binding around an Ethereum contract.
+type U256CumulativeRaw struct {
+	Contract *U256Cumulative // Generic contract binding to access the raw methods on
+}
+
+// U256CumulativeCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type U256CumulativeCallerRaw struct {
+	Contract *U256CumulativeCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// U256CumulativeTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type U256CumulativeTransactorRaw struct {
+	Contract *U256CumulativeTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewU256Cumulative creates a new instance of U256Cumulative, bound to a specific deployed contract.
+func NewU256Cumulative(address common.Address, backend bind.ContractBackend) (*U256Cumulative, error) {
+	contract, err := bindU256Cumulative(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &U256Cumulative{U256CumulativeCaller: U256CumulativeCaller{contract: contract}, U256CumulativeTransactor: U256CumulativeTransactor{contract: contract}, U256CumulativeFilterer: U256CumulativeFilterer{contract: contract}}, nil
+}
+
+// NewU256CumulativeCaller creates a new read-only instance of U256Cumulative, bound to a specific deployed contract.
+func NewU256CumulativeCaller(address common.Address, caller bind.ContractCaller) (*U256CumulativeCaller, error) {
+	contract, err := bindU256Cumulative(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &U256CumulativeCaller{contract: contract}, nil
+}
+
+// NewU256CumulativeTransactor creates a new write-only instance of U256Cumulative, bound to a specific deployed contract.
+func NewU256CumulativeTransactor(address common.Address, transactor bind.ContractTransactor) (*U256CumulativeTransactor, error) {
+	contract, err := bindU256Cumulative(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &U256CumulativeTransactor{contract: contract}, nil
+}
+
+// NewU256CumulativeFilterer creates a new log filterer instance of U256Cumulative, bound to a specific deployed contract.
+func NewU256CumulativeFilterer(address common.Address, filterer bind.ContractFilterer) (*U256CumulativeFilterer, error) {
+	contract, err := bindU256Cumulative(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &U256CumulativeFilterer{contract: contract}, nil
+}
+
+// bindU256Cumulative binds a generic wrapper to an already deployed contract.
+func bindU256Cumulative(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := U256CumulativeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(address, *parsed, caller, transactor, filterer)
+
+	return contract, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_U256Cumulative *U256CumulativeRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _U256Cumulative.Contract.U256CumulativeCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_U256Cumulative *U256CumulativeRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.U256CumulativeTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_U256Cumulative *U256CumulativeRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.U256CumulativeTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_U256Cumulative *U256CumulativeCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _U256Cumulative.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_U256Cumulative *U256CumulativeTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_U256Cumulative *U256CumulativeTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.contract.Transact(opts, method, params...)
+}
+
+// Get is a free data retrieval call binding the contract method 0x6d4ce63c.
+//
+// Solidity: function get() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCaller) Get(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _U256Cumulative.contract.Call(opts, &out, "get")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Get is a free data retrieval call binding the contract method 0x6d4ce63c.
+//
+// Solidity: function get() view returns(uint256)
+func (_U256Cumulative *U256CumulativeSession) Get() (*big.Int, error) {
+	return _U256Cumulative.Contract.Get(&_U256Cumulative.CallOpts)
+}
+
+// Get is a free data retrieval call binding the contract method 0x6d4ce63c.
+//
+// Solidity: function get() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCallerSession) Get() (*big.Int, error) {
+	return _U256Cumulative.Contract.Get(&_U256Cumulative.CallOpts)
+}
+
+// Max is a free data retrieval call binding the contract method 0x6ac5db19.
+//
+// Solidity: function max() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCaller) Max(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _U256Cumulative.contract.Call(opts, &out, "max")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Max is a free data retrieval call binding the contract method 0x6ac5db19.
+//
+// Solidity: function max() view returns(uint256)
+func (_U256Cumulative *U256CumulativeSession) Max() (*big.Int, error) {
+	return _U256Cumulative.Contract.Max(&_U256Cumulative.CallOpts)
+}
+
+// Max is a free data retrieval call binding the contract method 0x6ac5db19.
+//
+// Solidity: function max() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCallerSession) Max() (*big.Int, error) {
+	return _U256Cumulative.Contract.Max(&_U256Cumulative.CallOpts)
+}
+
+// Min is a free data retrieval call binding the contract method 0xf8897945.
+//
+// Solidity: function min() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCaller) Min(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _U256Cumulative.contract.Call(opts, &out, "min")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Min is a free data retrieval call binding the contract method 0xf8897945.
+//
+// Solidity: function min() view returns(uint256)
+func (_U256Cumulative *U256CumulativeSession) Min() (*big.Int, error) {
+	return _U256Cumulative.Contract.Min(&_U256Cumulative.CallOpts)
+}
+
+// Min is a free data retrieval call binding the contract method 0xf8897945.
+//
+// Solidity: function min() view returns(uint256)
+func (_U256Cumulative *U256CumulativeCallerSession) Min() (*big.Int, error) {
+	return _U256Cumulative.Contract.Min(&_U256Cumulative.CallOpts)
+}
+
+// Add is a paid mutator transaction binding the contract method 0x1003e2d2.
+//
+// Solidity: function add(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeTransactor) Add(opts *bind.TransactOpts, delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.contract.Transact(opts, "add", delta)
+}
+
+// Add is a paid mutator transaction binding the contract method 0x1003e2d2.
+//
+// Solidity: function add(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeSession) Add(delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.Add(&_U256Cumulative.TransactOpts, delta)
+}
+
+// Add is a paid mutator transaction binding the contract method 0x1003e2d2.
+//
+// Solidity: function add(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeTransactorSession) Add(delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.Add(&_U256Cumulative.TransactOpts, delta)
+}
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeTransactor) Sub(opts *bind.TransactOpts, delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.contract.Transact(opts, "sub", delta)
+}
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeSession) Sub(delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.Sub(&_U256Cumulative.TransactOpts, delta)
+}
+
+// Sub is a paid mutator transaction binding the contract method 0x27ee58a6.
+//
+// Solidity: function sub(uint256 delta) returns()
+func (_U256Cumulative *U256CumulativeTransactorSession) Sub(delta *big.Int) (*types.Transaction, error) {
+	return _U256Cumulative.Contract.Sub(&_U256Cumulative.TransactOpts, delta)
+}
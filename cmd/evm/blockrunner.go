@@ -53,7 +53,7 @@ func blockTestCmd(ctx *cli.Context) error {
 	var tracer vm.EVMLogger
 	// Configure the EVM logger
 	if ctx.Bool(MachineFlag.Name) {
-		tracer = logger.NewJSONLogger(&logger.Config{
+		tracer = machineLogger(ctx, &logger.Config{
 			EnableMemory:     !ctx.Bool(DisableMemoryFlag.Name),
 			DisableStack:     ctx.Bool(DisableStackFlag.Name),
 			DisableStorage:   ctx.Bool(DisableStorageFlag.Name),
@@ -102,6 +102,11 @@ var (
 		Usage:    "output trace logs in machine readable format (json)",
 		Category: flags.VMCategory,
 	}
+	MachineStreamFlag = &cli.BoolFlag{
+		Name:     "json.stream",
+		Usage:    "encode --json trace logs in the versioned JSON-lines stream schema",
+		Category: flags.VMCategory,
+	}
 	SenderFlag = &cli.StringFlag{
 		Name:     "sender",
 		Usage:    "The transaction origin",
@@ -213,6 +218,7 @@ var traceFlags = []cli.Flag{
 	DebugFlag,
 	DumpFlag,
 	MachineFlag,
+	MachineStreamFlag,
 	StatDumpFlag,
 	DisableMemoryFlag,
 	DisableStackFlag,
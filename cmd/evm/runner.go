@@ -106,6 +106,15 @@ func timedExec(bench bool, execFunc func() ([]byte, uint64, error)) (output []by
 	return output, gasLeft, stats, err
 }
 
+// machineLogger returns the tracer writing the machine readable (--json) trace
+// logs into w, in the encoding selected by the --json.stream flag.
+func machineLogger(ctx *cli.Context, cfg *logger.Config, w io.Writer) vm.EVMLogger {
+	if ctx.Bool(MachineStreamFlag.Name) {
+		return logger.NewStreamLogger(cfg, w)
+	}
+	return logger.NewJSONLogger(cfg, w)
+}
+
 func runCmd(ctx *cli.Context) error {
 	logconfig := &logger.Config{
 		EnableMemory:     !ctx.Bool(DisableMemoryFlag.Name),
@@ -127,7 +136,7 @@ func runCmd(ctx *cli.Context) error {
 		blobBaseFee = new(big.Int) // TODO (MariusVanDerWijden) implement blob fee in state tests
 	)
 	if ctx.Bool(MachineFlag.Name) {
-		tracer = logger.NewJSONLogger(logconfig, os.Stdout)
+		tracer = machineLogger(ctx, logconfig, os.Stdout)
 	} else if ctx.Bool(DebugFlag.Name) {
 		debugLogger = logger.NewStructLogger(logconfig)
 		tracer = debugLogger
@@ -43,6 +43,11 @@ import (
 )
 
 var (
+	freezerDictionaryFlag = &cli.BoolFlag{
+		Name:  "dictionary",
+		Usage: "Train a compression dictionary from the table items, if the codec supports it",
+		Value: true,
+	}
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
 		Name:      "removedb",
@@ -65,10 +70,12 @@ Remove blockchain and state databases`,
 			dbPutCmd,
 			dbGetSlotsCmd,
 			dbDumpFreezerIndex,
+			dbFreezerRecompressCmd,
 			dbImportCmd,
 			dbExportCmd,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
+			dbShardsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -81,6 +88,26 @@ Remove blockchain and state databases`,
 		Usage:       "Inspect the storage size for each type of data in the database",
 		Description: `This commands iterates the entire database. If the optional 'prefix' and 'start' arguments are provided, then the iteration is limited to the given subset of data.`,
 	}
+	dbShardsCmd = &cli.Command{
+		Name:  "shards",
+		Usage: "Operations on the sharded trie database of the parallel trie backend",
+		Subcommands: []*cli.Command{
+			{
+				Action: inspectShards,
+				Name:   "inspect",
+				Usage:  "Inspect the storage size of each shard",
+				Flags:  flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `This command prints the layout of the sharded trie database, then
+iterates all the shards, reporting the number and size of the entries in each.`,
+			},
+			{
+				Action: shardStats,
+				Name:   "stats",
+				Usage:  "Print the database statistics of each shard",
+				Flags:  flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+			},
+		},
+	}
 	dbCheckStateContentCmd = &cli.Command{
 		Action:    checkStateContent,
 		Name:      "check-state-content",
@@ -164,6 +191,23 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command displays information about the freezer index.",
 	}
+	dbFreezerRecompressCmd = &cli.Command{
+		Action:    freezerRecompress,
+		Name:      "freezer-recompress",
+		Usage:     "Recompress a specific freezer table with a different codec",
+		ArgsUsage: "<freezer-type> <table-type> <codec>",
+		Flags: flags.Merge([]cli.Flag{
+			freezerDictionaryFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command rewrites all the items of a freezer table with the given
+codec (snappy or zstd), which is recorded in the table metadata. Tables are
+snappy compressed by default, so zstd is only used after opting in, either with
+this command or for the bodies and receipts of new ancient stores with
+--db.ancient.codec. For zstd a dictionary is trained from sampled table items, unless
+--dictionary=false is set. The node must not be running while the table is
+recompressed, and tables which were tail-truncated (e.g. pruned state history)
+can't be recompressed. Note, older geth versions can't read zstd tables.`,
+	}
 	dbImportCmd = &cli.Command{
 		Action:    importLDBdata,
 		Name:      "import",
@@ -364,6 +408,30 @@ func dbStats(ctx *cli.Context) error {
 	return nil
 }
 
+func inspectShards(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeShardedDatabase(ctx, stack, true)
+	defer db.Close()
+
+	if manifest := db.Manifest(); manifest != nil {
+		fmt.Printf("Engine: %s, layout: %s, version: %d\n", manifest.Engine, manifest.Layout, manifest.Version)
+	}
+	return rawdb.InspectShardedDatabase(db)
+}
+
+func shardStats(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeShardedDatabase(ctx, stack, true)
+	defer db.Close()
+
+	showLeveldbStats(db)
+	return nil
+}
+
 func dbCompact(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
@@ -563,6 +631,22 @@ func freezerInspect(ctx *cli.Context) error {
 	return rawdb.InspectFreezerTable(ancient, freezer, table, start, end)
 }
 
+func freezerRecompress(ctx *cli.Context) error {
+	if ctx.NArg() != 3 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	ancient := stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
+	stack.Close()
+
+	start := time.Now()
+	if err := rawdb.RecompressFreezerTable(ancient, ctx.Args().Get(0), ctx.Args().Get(1), ctx.Args().Get(2), ctx.Bool(freezerDictionaryFlag.Name)); err != nil {
+		return err
+	}
+	log.Info("Recompressed freezer table", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 func importLDBdata(ctx *cli.Context) error {
 	start := 0
 	switch ctx.NArg() {
@@ -0,0 +1,140 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/les"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	openrpcSourceFlag = &cli.StringSliceFlag{
+		Name:  "source",
+		Usage: "Go source directories to extract the method documentation from",
+	}
+	openrpcModulesFlag = &cli.StringFlag{
+		Name:  "modules",
+		Usage: "Comma separated list of API modules to describe (default: all)",
+	}
+	openrpcCommand = &cli.Command{
+		Action:    openrpc,
+		Name:      "openrpc",
+		Usage:     "Generate an OpenRPC document of the RPC APIs",
+		ArgsUsage: "[endpoint]",
+		Flags:     []cli.Flag{utils.HttpHeaderFlag, openrpcSourceFlag, openrpcModulesFlag},
+		Description: `
+The openrpc command prints an OpenRPC document describing the RPC methods and
+subscriptions served by a full node, without accessing any of its data.
+
+If an endpoint is given, the document is fetched from the running node behind it
+using rpc_discover instead.
+
+The method documentation is extracted from the doc comments of the Go sources
+in the --source directories, if given, e.g. a checkout of the go-ethereum
+repository.`,
+	}
+)
+
+// openrpc prints the OpenRPC document of a full node or a remote one.
+func openrpc(ctx *cli.Context) error {
+	var doc *rpc.OpenRPCDocument
+	if endpoint := ctx.Args().First(); endpoint != "" {
+		client, err := utils.DialRPCWithHeaders(endpoint, ctx.StringSlice(utils.HttpHeaderFlag.Name))
+		if err != nil {
+			return fmt.Errorf("unable to attach to remote geth: %v", err)
+		}
+		defer client.Close()
+
+		if err := client.Call(&doc, "rpc_discover"); err != nil {
+			return err
+		}
+	} else {
+		srv, err := openrpcServer(ctx)
+		if err != nil {
+			return err
+		}
+		doc = srv.OpenRPC()
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(out))
+	return err
+}
+
+// openrpcServer creates an RPC server with the APIs of a full node registered.
+func openrpcServer(ctx *cli.Context) (*rpc.Server, error) {
+	apis, err := openrpcAPIs()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		srv     = rpc.NewServer()
+		modules = make(map[string]bool)
+	)
+	if ctx.IsSet(openrpcModulesFlag.Name) {
+		for _, module := range utils.SplitAndTrim(ctx.String(openrpcModulesFlag.Name)) {
+			modules[module] = true
+		}
+	}
+	for _, api := range apis {
+		if len(modules) > 0 && !modules[api.Namespace] {
+			continue
+		}
+		if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
+			return nil, err
+		}
+	}
+	if dirs := ctx.StringSlice(openrpcSourceFlag.Name); len(dirs) > 0 {
+		docs, err := rpc.ParseMethodDocs(dirs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract method documentation: %v", err)
+		}
+		srv.SetMethodDocs(docs)
+	}
+	return srv, nil
+}
+
+// openrpcAPIs lists the RPC APIs of a full node. The services are only inspected
+// for their methods, so they are created without any backend instead of setting
+// up a node with its databases.
+func openrpcAPIs() ([]rpc.API, error) {
+	// The APIs of the node itself, without a data directory to lock
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		return nil, err
+	}
+	defer stack.Close()
+
+	apis := append(stack.RPCAPIs(), eth.APIs(nil)...)
+	apis = append(apis, filters.APIs(nil, false)...)
+	apis = append(apis, tracers.APIs(nil)...)
+	apis = append(apis, catalyst.APIs(nil)...)
+	return append(apis, les.ServerAPIs(nil)...), nil
+}
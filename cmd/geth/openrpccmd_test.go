@@ -0,0 +1,58 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the OpenRPC document describes the APIs of all the services of a
+// full node.
+func TestOpenRPCAPIs(t *testing.T) {
+	apis, err := openrpcAPIs()
+	if err != nil {
+		t.Fatalf("Failed to list the APIs: %v", err)
+	}
+	srv := rpc.NewServer()
+	for _, api := range apis {
+		if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
+			t.Fatalf("Failed to register %s API: %v", api.Namespace, err)
+		}
+	}
+	methods := make(map[string]bool)
+	for _, method := range srv.OpenRPC().Methods {
+		methods[method.Name] = true
+	}
+	for _, name := range []string{
+		"admin_nodeInfo",
+		"eth_getBlockByNumber",
+		"eth_sendBundle",
+		"eth_getLogs",
+		"eth_syncing",
+		"debug_traceTransaction",
+		"engine_forkchoiceUpdatedV1",
+		"les_serverInfo",
+		"net_version",
+		"personal_listAccounts",
+	} {
+		if !methods[name] {
+			t.Errorf("Method %s missing from the document", name)
+		}
+	}
+}
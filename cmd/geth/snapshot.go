@@ -39,6 +39,11 @@ import (
 )
 
 var (
+	snapshotChunkSizeFlag = &cli.IntFlag{
+		Name:  "chunksize",
+		Usage: "Uncompressed size in bytes of the exported snapshot chunks",
+		Value: snapshot.DefaultExportChunkSize,
+	}
 	snapshotCommand = &cli.Command{
 		Name:        "snapshot",
 		Usage:       "A set of commands based on the snapshot",
@@ -147,6 +152,35 @@ as the backend data source, making this command a lot faster.
 
 The argument is interpreted as block number or hash. If none is provided, the latest
 block is used.
+`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export the flat state snapshot into chunk files",
+				ArgsUsage: "<dir> [<root>]",
+				Action:    exportSnapshot,
+				Flags: flags.Merge([]cli.Flag{
+					snapshotChunkSizeFlag,
+				}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot export <dir> [<state-root>]
+will write the flat account and storage snapshot at the given root (the HEAD
+state by default) into the given directory, as snappy compressed chunks named
+after their Keccak256 hash, along with a manifest.json describing them.
+`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import a flat state snapshot from chunk files",
+				ArgsUsage: "<dir>",
+				Action:    importSnapshot,
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot import <dir>
+will verify the chunks exported by 'geth snapshot export' against their manifest,
+write the flat snapshot into the database and rebuild the state tries from it,
+checking the state root. It is meant to bootstrap nodes offline and must be run
+on a database without any state snapshot.
 `,
 			},
 		},
@@ -604,6 +638,73 @@ func dumpState(ctx *cli.Context) error {
 	return nil
 }
 
+// exportSnapshot writes the flat state snapshot at the given root into chunk
+// files with a manifest.
+func exportSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("need <dir> and optionally <root> arguments")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	headBlock := rawdb.ReadHeadBlock(chaindb)
+	if headBlock == nil {
+		log.Error("Failed to load head block")
+		return errors.New("no head block")
+	}
+	root := headBlock.Root()
+	if ctx.NArg() == 2 {
+		var err error
+		if root, err = parseRoot(ctx.Args().Get(1)); err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+	}
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true)
+	defer triedb.Close()
+
+	snapConfig := snapshot.Config{
+		CacheSize:  256,
+		Recovery:   false,
+		NoBuild:    true,
+		AsyncBuild: false,
+	}
+	snaptree, err := snapshot.New(snapConfig, chaindb, triedb, headBlock.Root())
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "err", err)
+		return err
+	}
+	_, err = snapshot.Export(snaptree, root, chaindb, ctx.Args().First(), ctx.Int(snapshotChunkSizeFlag.Name))
+	return err
+}
+
+// importSnapshot verifies and imports a flat state snapshot from chunk files,
+// rebuilding the state tries.
+func importSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("need <dir> argument")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	if root := rawdb.ReadSnapshotRoot(chaindb); root != (common.Hash{}) {
+		log.Error("Database already contains a state snapshot", "root", root)
+		return errors.New("snapshot already present")
+	}
+	scheme, err := rawdb.ParseStateScheme(ctx.String(utils.StateSchemeFlag.Name), chaindb)
+	if err != nil {
+		return err
+	}
+	_, err = snapshot.Import(ctx.Args().First(), chaindb, scheme)
+	return err
+}
+
 // checkAccount iterates the snap data layers, and looks up the given account
 // across all layers.
 func checkAccount(ctx *cli.Context) error {
@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -104,6 +105,11 @@ var (
 		Value:    node.DefaultConfig.DBEngine,
 		Category: flags.EthCategory,
 	}
+	DBAncientCodecFlag = &cli.StringFlag{
+		Name:     "db.ancient.codec",
+		Usage:    "Compression codec of the bodies and receipts in new ancient stores ('snappy' or 'zstd')",
+		Category: flags.EthCategory,
+	}
 	AncientFlag = &flags.DirectoryFlag{
 		Name:     "datadir.ancient",
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
@@ -295,12 +301,28 @@ var (
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	SnapshotHistoryFlag = &cli.Uint64Flag{
+		Name:     "history.snapshot",
+		Usage:    "Number of blocks beyond the snapshot diff layers to retain reverse diffs for, serving historical state queries (0 = disabled)",
+		Value:    ethconfig.Defaults.SnapshotHistory,
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
 		Value:    ethconfig.Defaults.TransactionHistory,
 		Category: flags.StateCategory,
 	}
+	StateShardedFlag = &cli.BoolFlag{
+		Name:     "state.sharded",
+		Usage:    "Store the trie nodes in a sharded database for the parallel trie backend, only relevant in state.scheme=hash",
+		Category: flags.StateCategory,
+	}
+	LogIndexFlag = &cli.BoolFlag{
+		Name:     "history.logindex",
+		Usage:    "Maintain an index of log addresses and topics, speeding up log filtering over large block ranges",
+		Category: flags.StateCategory,
+	}
 	// Light server and client settings
 	LightServeFlag = &cli.IntFlag{
 		Name:     "light.serve",
@@ -358,9 +380,14 @@ var (
 		Usage:    "Includes remote transactions in the journal",
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolTxLogFlag = &cli.StringFlag{
+		Name:     "txpool.txlog",
+		Usage:    "Append-only, checksummed log of all pool transactions to survive node restarts (disabled if empty)",
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolRejournalFlag = &cli.DurationFlag{
 		Name:     "txpool.rejournal",
-		Usage:    "Time interval to regenerate the local transaction journal",
+		Usage:    "Time interval to regenerate the local transaction journal and compact the transaction log",
 		Value:    ethconfig.Defaults.TxPool.Rejournal,
 		Category: flags.TxPoolCategory,
 	}
@@ -406,6 +433,12 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolValidationShardsFlag = &cli.IntFlag{
+		Name:     "txpool.validationshards",
+		Usage:    "Number of sender shards validating transactions concurrently (0 = serial validation)",
+		Value:    ethconfig.Defaults.TxPool.ValidationShards,
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -425,6 +458,25 @@ var (
 		Value:    ethconfig.Defaults.BlobPool.PriceBump,
 		Category: flags.BlobPoolCategory,
 	}
+	// Bundle transaction pool settings
+	BundlePoolGlobalBundlesFlag = &cli.IntFlag{
+		Name:     "bundlepool.globalbundles",
+		Usage:    "Maximum number of transaction bundles tracked by the pool",
+		Value:    ethconfig.Defaults.BundlePool.GlobalBundles,
+		Category: flags.BundlePoolCategory,
+	}
+	BundlePoolMaxTxsFlag = &cli.IntFlag{
+		Name:     "bundlepool.maxtxs",
+		Usage:    "Maximum number of transactions in a single bundle",
+		Value:    ethconfig.Defaults.BundlePool.MaxBundleTxs,
+		Category: flags.BundlePoolCategory,
+	}
+	BundlePoolHorizonFlag = &cli.Uint64Flag{
+		Name:     "bundlepool.horizon",
+		Usage:    "Maximum number of blocks ahead of the head a bundle may target",
+		Value:    ethconfig.Defaults.BundlePool.MaxHorizon,
+		Category: flags.BundlePoolCategory,
+	}
 	// Performance tuning settings
 	CacheFlag = &cli.IntFlag{
 		Name:     "cache",
@@ -524,6 +576,12 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerOrderingPolicyFlag = &cli.StringFlag{
+		Name:     "miner.ordering",
+		Usage:    "Transaction ordering policy used to fill blocks (" + strings.Join(miner.OrderingPolicies(), ", ") + ")",
+		Value:    miner.PriceAndTimeOrdering,
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -690,6 +748,23 @@ var (
 		Value:    strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
 		Category: flags.APICategory,
 	}
+	GraphQLMaxDepthFlag = &cli.IntFlag{
+		Name:     "graphql.maxdepth",
+		Usage:    "Maximum nesting depth of the fields selected by a GraphQL query (0 = unlimited)",
+		Value:    node.DefaultConfig.GraphQLMaxDepth,
+		Category: flags.APICategory,
+	}
+	GraphQLMaxCostFlag = &cli.Uint64Flag{
+		Name:     "graphql.maxcost",
+		Usage:    "Maximum static cost of a GraphQL query (0 = unlimited)",
+		Value:    node.DefaultConfig.GraphQLMaxCost,
+		Category: flags.APICategory,
+	}
+	GraphQLCostsFlag = &cli.StringFlag{
+		Name:     "graphql.costs",
+		Usage:    "Comma separated list of GraphQL field costs, e.g. Query.logs=1000,Account.storage=20",
+		Category: flags.APICategory,
+	}
 	WSEnabledFlag = &cli.BoolFlag{
 		Name:     "ws",
 		Usage:    "Enable the WS-RPC server",
@@ -752,6 +827,26 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Cost units replenished per second for each HTTP/WS client (0 = no rate limit)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Maximum cost units a HTTP/WS client can accumulate (default = rate)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitQuotaFlag = &cli.Uint64Flag{
+		Name:     "rpc.ratelimit.quota",
+		Usage:    "Maximum cost units a HTTP/WS client can spend per hour (0 = no quota)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitCostsFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.costs",
+		Usage:    "Comma separated list of method cost weights, e.g. eth_getLogs=20,debug_*=100",
+		Category: flags.APICategory,
+	}
 	EnablePersonal = &cli.BoolFlag{
 		Name:     "rpc.enabledeprecatedpersonal",
 		Usage:    "Enables the (deprecated) personal namespace",
@@ -1039,6 +1134,7 @@ var (
 		AncientFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
+		DBAncientCodecFlag,
 		StateSchemeFlag,
 		HttpHeaderFlag,
 	}
@@ -1253,6 +1349,41 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+	setRPCRateLimit(ctx, cfg)
+}
+
+// setRPCRateLimit creates the per-client rate limit configuration from the
+// command line flags, extending the one from the config file if any.
+func setRPCRateLimit(ctx *cli.Context, cfg *node.Config) {
+	if !ctx.IsSet(RPCRateLimitFlag.Name) && !ctx.IsSet(RPCRateLimitBurstFlag.Name) &&
+		!ctx.IsSet(RPCRateLimitQuotaFlag.Name) && !ctx.IsSet(RPCRateLimitCostsFlag.Name) {
+		return
+	}
+	if cfg.RPCRateLimit == nil {
+		cfg.RPCRateLimit = new(rpc.RateLimitConfig)
+	}
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCRateLimit.Rate = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.RPCRateLimit.Burst = ctx.Float64(RPCRateLimitBurstFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitQuotaFlag.Name) {
+		cfg.RPCRateLimit.Quota = ctx.Uint64(RPCRateLimitQuotaFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitCostsFlag.Name) {
+		if cfg.RPCRateLimit.MethodCosts == nil {
+			cfg.RPCRateLimit.MethodCosts = make(map[string]uint64)
+		}
+		for _, entry := range SplitAndTrim(ctx.String(RPCRateLimitCostsFlag.Name)) {
+			method, weight, ok := strings.Cut(entry, "=")
+			cost, err := strconv.ParseUint(weight, 10, 64)
+			if !ok || err != nil {
+				Fatalf("Invalid --%s entry %q, want <method>=<cost>", RPCRateLimitCostsFlag.Name, entry)
+			}
+			cfg.RPCRateLimit.MethodCosts[method] = cost
+		}
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1264,6 +1395,25 @@ func setGraphQL(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(GraphQLVirtualHostsFlag.Name) {
 		cfg.GraphQLVirtualHosts = SplitAndTrim(ctx.String(GraphQLVirtualHostsFlag.Name))
 	}
+	if ctx.IsSet(GraphQLMaxDepthFlag.Name) {
+		cfg.GraphQLMaxDepth = ctx.Int(GraphQLMaxDepthFlag.Name)
+	}
+	if ctx.IsSet(GraphQLMaxCostFlag.Name) {
+		cfg.GraphQLMaxCost = ctx.Uint64(GraphQLMaxCostFlag.Name)
+	}
+	if ctx.IsSet(GraphQLCostsFlag.Name) {
+		if cfg.GraphQLFieldCosts == nil {
+			cfg.GraphQLFieldCosts = make(map[string]uint64)
+		}
+		for _, entry := range SplitAndTrim(ctx.String(GraphQLCostsFlag.Name)) {
+			field, weight, ok := strings.Cut(entry, "=")
+			cost, err := strconv.ParseUint(weight, 10, 64)
+			if !ok || err != nil {
+				Fatalf("Invalid --%s entry %q, want <Type.field>=<cost>", GraphQLCostsFlag.Name, entry)
+			}
+			cfg.GraphQLFieldCosts[field] = cost
+		}
+	}
 }
 
 // setWS creates the WebSocket RPC listener interface string from the set
@@ -1539,6 +1689,13 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		log.Info(fmt.Sprintf("Using %s as db engine", dbEngine))
 		cfg.DBEngine = dbEngine
 	}
+	if ctx.IsSet(DBAncientCodecFlag.Name) {
+		codec := ctx.String(DBAncientCodecFlag.Name)
+		if codec != rawdb.FreezerCodecSnappy && codec != rawdb.FreezerCodecZstd {
+			Fatalf("Invalid choice for db.ancient.codec '%s', allowed '%s' or '%s'", codec, rawdb.FreezerCodecSnappy, rawdb.FreezerCodecZstd)
+		}
+		cfg.DBAncientCodec = codec
+	}
 }
 
 func setSmartCard(ctx *cli.Context, cfg *node.Config) {
@@ -1621,6 +1778,9 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolJournalRemotesFlag.Name) {
 		cfg.JournalRemote = ctx.Bool(TxPoolJournalRemotesFlag.Name)
 	}
+	if ctx.IsSet(TxPoolTxLogFlag.Name) {
+		cfg.TxLog = ctx.String(TxPoolTxLogFlag.Name)
+	}
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
@@ -1645,6 +1805,21 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.Duration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.IsSet(TxPoolValidationShardsFlag.Name) {
+		cfg.ValidationShards = ctx.Int(TxPoolValidationShardsFlag.Name)
+	}
+}
+
+func setBundlePool(ctx *cli.Context, cfg *bundlepool.Config) {
+	if ctx.IsSet(BundlePoolGlobalBundlesFlag.Name) {
+		cfg.GlobalBundles = ctx.Int(BundlePoolGlobalBundlesFlag.Name)
+	}
+	if ctx.IsSet(BundlePoolMaxTxsFlag.Name) {
+		cfg.MaxBundleTxs = ctx.Int(BundlePoolMaxTxsFlag.Name)
+	}
+	if ctx.IsSet(BundlePoolHorizonFlag.Name) {
+		cfg.MaxHorizon = ctx.Uint64(BundlePoolHorizonFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1666,6 +1841,13 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(RollupComputePendingBlock.Name) {
 		cfg.RollupComputePendingBlock = ctx.Bool(RollupComputePendingBlock.Name)
 	}
+	if ctx.IsSet(MinerOrderingPolicyFlag.Name) {
+		name := ctx.String(MinerOrderingPolicyFlag.Name)
+		if _, err := miner.LookupOrderingPolicy(name); err != nil {
+			Fatalf("Invalid miner ordering policy: %v", err)
+		}
+		cfg.OrderingPolicy = name
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1748,6 +1930,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO, ctx.String(SyncModeFlag.Name) == "light")
 	setTxPool(ctx, &cfg.TxPool)
+	setBundlePool(ctx, &cfg.BundlePool)
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 	setLes(ctx, cfg)
@@ -1806,9 +1989,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(SnapshotHistoryFlag.Name) {
+		cfg.SnapshotHistory = ctx.Uint64(SnapshotHistoryFlag.Name)
+	}
 	if ctx.IsSet(StateSchemeFlag.Name) {
 		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
 	}
+	if ctx.IsSet(StateShardedFlag.Name) {
+		cfg.ShardedTrieDatabase = ctx.Bool(StateShardedFlag.Name)
+	}
 	// Parse transaction history flag, if user is still using legacy config
 	// file with 'TxLookupLimit' configured, copy the value to 'TransactionHistory'.
 	if cfg.TransactionHistory == ethconfig.Defaults.TransactionHistory && cfg.TxLookupLimit != ethconfig.Defaults.TxLookupLimit {
@@ -1821,6 +2010,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Warn("The flag --txlookuplimit is deprecated and will be removed, please use --history.transactions")
 		cfg.TransactionHistory = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(LogIndexFlag.Name) {
+		cfg.LogIndex = ctx.Bool(LogIndexFlag.Name)
+	}
 	if ctx.String(GCModeFlag.Name) == "archive" && cfg.TransactionHistory != 0 {
 		cfg.TransactionHistory = 0
 		log.Warn("Disabled transaction unindexing for archive node")
@@ -2018,6 +2210,10 @@ func SetDNSDiscoveryDefaults(cfg *ethconfig.Config, genesis common.Hash) {
 	}
 }
 
+// traceRangeDir is the directory within the node's instance directory holding
+// the outputs of debug_traceBlockRange.
+const traceRangeDir = "tracerange"
+
 // RegisterEthService adds an Ethereum client to the stack.
 // The second return value is the full node instance, which may be nil if the
 // node is running as a light client.
@@ -2027,7 +2223,7 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 		if err != nil {
 			Fatalf("Failed to register the Ethereum service: %v", err)
 		}
-		stack.RegisterAPIs(tracers.APIs(backend.ApiBackend))
+		stack.RegisterAPIs(tracers.APIsWithRangeDir(backend.ApiBackend, stack.ResolvePath(traceRangeDir)))
 		return backend.ApiBackend, nil
 	}
 	backend, err := eth.New(stack, cfg)
@@ -2040,7 +2236,7 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 			Fatalf("Failed to create the LES server: %v", err)
 		}
 	}
-	stack.RegisterAPIs(tracers.APIs(backend.APIBackend))
+	stack.RegisterAPIs(tracers.APIsWithRangeDir(backend.APIBackend, stack.ResolvePath(traceRangeDir)))
 	return backend.APIBackend, backend
 }
 
@@ -2053,7 +2249,12 @@ func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url strin
 
 // RegisterGraphQLService adds the GraphQL API to the node.
 func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cfg *node.Config) {
-	err := graphql.New(stack, backend, filterSystem, cfg.GraphQLCors, cfg.GraphQLVirtualHosts)
+	limits := graphql.CostConfig{
+		MaxDepth:   cfg.GraphQLMaxDepth,
+		MaxCost:    cfg.GraphQLMaxCost,
+		FieldCosts: cfg.GraphQLFieldCosts,
+	}
+	err := graphql.New(stack, backend, filterSystem, cfg.GraphQLCors, cfg.GraphQLVirtualHosts, limits)
 	if err != nil {
 		Fatalf("Failed to register the GraphQL service: %v", err)
 	}
@@ -2065,10 +2266,7 @@ func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconf
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
 		LogCacheSize: ethcfg.FilterLogCacheSize,
 	})
-	stack.RegisterAPIs([]rpc.API{{
-		Namespace: "eth",
-		Service:   filters.NewFilterAPI(filterSystem, isLightClient),
-	}})
+	stack.RegisterAPIs(filters.APIs(filterSystem, isLightClient))
 	return filterSystem
 }
 
@@ -2183,6 +2381,20 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 	return chainDb
 }
 
+// MakeShardedDatabase opens the sharded trie database of the parallel trie
+// backend, in the same format NewParallelDatabase expects it.
+func MakeShardedDatabase(ctx *cli.Context, stack *node.Node, readonly bool) *rawdb.ShardedDatabase {
+	var (
+		cache   = ctx.Int(CacheFlag.Name) * ctx.Int(CacheDatabaseFlag.Name) / 100
+		handles = MakeDatabaseHandles(ctx.Int(FDLimitFlag.Name))
+	)
+	db, err := stack.OpenShardedDatabase("triedata", cache, handles, "eth/db/triedata/", readonly)
+	if err != nil {
+		Fatalf("Could not open sharded database: %v", err)
+	}
+	return db
+}
+
 // tryMakeReadOnlyDatabase try to open the chain database in read-only mode,
 // or fallback to write mode if the database is not initialized.
 func tryMakeReadOnlyDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
@@ -142,8 +142,11 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	SnapshotHistory     uint64        // Number of blocks beyond the snapshot diff layers whose reverse diffs are reserved.
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
 
+	TrieShards *rawdb.ShardedDatabase // Sharded store of the trie nodes for the parallel trie backend (hash scheme only)
+
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
@@ -268,7 +271,15 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		cacheConfig = defaultCacheConfig
 	}
 	// Open trie database with provided config
-	triedb := trie.NewDatabase(db, cacheConfig.triedbConfig())
+	var triedb *trie.Database
+	if cacheConfig.TrieShards != nil {
+		if cacheConfig.StateScheme != rawdb.HashScheme {
+			return nil, fmt.Errorf("sharded trie database unsupported by the %s scheme", cacheConfig.StateScheme)
+		}
+		triedb = trie.NewParallelDatabaseWithWAL(cacheConfig.TrieShards.Shards(), cacheConfig.TrieShards.WAL(), cacheConfig.triedbConfig())
+	} else {
+		triedb = trie.NewDatabase(db, cacheConfig.triedbConfig())
+	}
 
 	// Setup the genesis block, commit the provided genesis specification
 	// to database if the genesis block is not present yet, or load the
@@ -449,6 +460,12 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 			Recovery:   recover,
 			NoBuild:    bc.cacheConfig.SnapshotNoBuild,
 			AsyncBuild: !bc.cacheConfig.SnapshotWait,
+			History:    bc.cacheConfig.SnapshotHistory,
+		}
+		if snapconfig.History > 0 {
+			if ancient, err := bc.db.AncientDatadir(); err == nil {
+				snapconfig.HistoryDir = ancient
+			}
 		}
 		bc.snaps, _ = snapshot.New(snapconfig, bc.db, bc.triedb, head.Root)
 	}
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -337,6 +338,20 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, bc.snaps)
 }
 
+// HistoricStateAt returns a read-only state for the given root, reconstructed
+// from the snapshot history retained beyond the snapshot layers. It's meant to
+// serve queries against states whose tries are not available anymore.
+func (bc *BlockChain) HistoricStateAt(root common.Hash) (*state.StateDB, error) {
+	if bc.snaps == nil {
+		return nil, errors.New("snapshots disabled")
+	}
+	reader, err := bc.snaps.StateReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return state.NewHistoric(root, bc.stateCache, reader)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -4716,3 +4716,49 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// Tests that the trie nodes of a chain are stored in and read back from the
+// shards of a sharded trie database.
+func TestShardedTrieDatabase(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		shards = rawdb.NewMemoryShardedDatabase()
+		config = &CacheConfig{
+			TrieCleanLimit:    256,
+			TrieDirtyDisabled: true,
+			StateScheme:       rawdb.HashScheme,
+			TrieShards:        shards,
+		}
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, b *BlockGen) {})
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), config, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if has, _ := shards.Has(chain.CurrentBlock().Root.Bytes()); !has {
+		t.Fatal("state root missing from the shards")
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open state: %v", err)
+	}
+	if balance := statedb.GetBalance(address); balance.Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want %v", balance, params.Ether)
+	}
+	// Other schemes can't use the shards
+	config.StateScheme = rawdb.PathScheme
+	if _, err := NewBlockChain(rawdb.NewMemoryDatabase(), config, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil); err == nil {
+		t.Fatal("sharded trie database accepted by the path scheme")
+	}
+}
@@ -149,6 +149,13 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 	b.addTx(bc, vm.Config{}, tx)
 }
 
+// AddTxWithChainAndVMConfig adds a transaction to the generated block, querying
+// historical block hashes from the given chain and customizing the evm
+// interpreter with the provided vm config.
+func (b *BlockGen) AddTxWithChainAndVMConfig(bc *BlockChain, tx *types.Transaction, config vm.Config) {
+	b.addTx(bc, config, tx)
+}
+
 // AddTxWithVMConfig adds a transaction to the generated block. If no coinbase has
 // been set, the block's coinbase is set to the zero address.
 // The evm interpreter can be customized with the provided vm config.
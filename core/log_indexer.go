@@ -0,0 +1,83 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/logindex"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+const (
+	// logIndexThrottling is the time to wait between processing two consecutive
+	// log index sections, to avoid disk overload while catching up.
+	logIndexThrottling = 100 * time.Millisecond
+)
+
+// LogIndexer implements a core.ChainIndexer, building up the posting lists of the
+// addresses and topics of the logs for fast logs filtering.
+type LogIndexer struct {
+	db      ethdb.Database    // database instance to read logs from and write index data into
+	size    uint64            // section size to generate the posting lists for
+	builder *logindex.Builder // builder of the posting lists of the current section
+}
+
+// NewLogIndexer returns a chain indexer that generates the log index of the
+// canonical chain.
+func NewLogIndexer(db ethdb.Database, size, confirms uint64) *ChainIndexer {
+	backend := &LogIndexer{
+		db:   db,
+		size: size,
+	}
+	table := rawdb.NewTable(db, string(rawdb.LogIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, logIndexThrottling, "logindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new log index section.
+func (b *LogIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	b.builder = logindex.NewBuilder(section, b.size)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the logs of a block into
+// the index.
+func (b *LogIndexer) Process(ctx context.Context, header *types.Header) error {
+	number := header.Number.Uint64()
+	b.builder.AddBlock(number, rawdb.ReadLogs(b.db, header.Hash(), number))
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, replacing the posting lists of the
+// section. Any lists left over by a previous version of the section, indexed
+// before a reorg, are deleted in the same batch.
+func (b *LogIndexer) Commit() error {
+	batch := b.db.NewBatch()
+	rawdb.DeleteLogIndexSection(b.db, batch, b.builder.Section())
+	b.builder.Commit(batch)
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (b *LogIndexer) Prune(threshold uint64) error {
+	return nil
+}
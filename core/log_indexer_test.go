@@ -0,0 +1,76 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/logindex"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that reindexing a section after a reorg replaces its posting lists,
+// leaving nothing of the reorged blocks behind.
+func TestLogIndexerReorg(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		indexer = &LogIndexer{db: db, size: 4}
+		addr1   = common.Address{0x01}
+		addr2   = common.Address{0x02}
+	)
+	// index writes a version of section 1, with a log of addr in every block
+	index := func(addr common.Address, version byte) {
+		if err := indexer.Reset(context.Background(), 1, common.Hash{}); err != nil {
+			t.Fatal(err)
+		}
+		for number := uint64(4); number < 8; number++ {
+			header := &types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte{version}}
+			receipt := &types.Receipt{Logs: []*types.Log{{Address: addr}}}
+
+			rawdb.WriteHeader(db, header)
+			rawdb.WriteReceipts(db, header.Hash(), number, types.Receipts{receipt})
+			if err := indexer.Process(context.Background(), header); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := indexer.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := func(addr common.Address) int {
+		matches, err := logindex.Match(db, 1, 4, []common.Address{addr}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(matches)
+	}
+	index(addr1, 0)
+	if have := count(addr1); have != 4 {
+		t.Fatalf("indexed log count mismatch: have %d, want 4", have)
+	}
+	index(addr2, 1)
+	if have := count(addr1); have != 0 {
+		t.Fatalf("reorged logs still indexed: %d", have)
+	}
+	if have := count(addr2); have != 4 {
+		t.Fatalf("reindexed log count mismatch: have %d, want 4", have)
+	}
+}
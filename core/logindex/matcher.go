@@ -0,0 +1,157 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// maxTopics is the maximum number of topics of a log.
+const maxTopics = 4
+
+// Builder collects the posting lists of a log index section.
+type Builder struct {
+	section uint64
+	start   uint64
+	lists   map[string][]Position // Posting lists keyed by tag and value
+}
+
+// NewBuilder creates a builder for the posting lists of the given section.
+func NewBuilder(section, size uint64) *Builder {
+	return &Builder{
+		section: section,
+		start:   section * size,
+		lists:   make(map[string][]Position),
+	}
+}
+
+// AddBlock adds the logs of the next block of the section, grouped by their
+// transactions.
+func (b *Builder) AddBlock(number uint64, logs [][]*types.Log) {
+	var index uint32
+	for _, txLogs := range logs {
+		for _, log := range txLogs {
+			pos := Position{Block: number, Index: index}
+			b.add(rawdb.LogIndexAddressTag, log.Address.Bytes(), pos)
+			for i, topic := range log.Topics {
+				if i == maxTopics {
+					break
+				}
+				b.add(byte(i), topic.Bytes(), pos)
+			}
+			index++
+		}
+	}
+}
+
+func (b *Builder) add(tag byte, value []byte, pos Position) {
+	key := string(append([]byte{tag}, value...))
+	b.lists[key] = append(b.lists[key], pos)
+}
+
+// Section returns the section the builder collects the posting lists of.
+func (b *Builder) Section() uint64 {
+	return b.section
+}
+
+// Commit writes the posting lists of the section.
+func (b *Builder) Commit(w ethdb.KeyValueWriter) {
+	for key, list := range b.lists {
+		rawdb.WriteLogIndexPostings(w, b.section, key[0], []byte(key[1:]), EncodePostings(list, b.start))
+	}
+}
+
+// Indexable reports whether the index can narrow down the logs matching the
+// given filter criteria, i.e. whether there's any non-wildcard clause.
+func Indexable(addresses []common.Address, topics [][]common.Hash) bool {
+	if len(addresses) > 0 {
+		return true
+	}
+	for _, sub := range topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the positions of the logs in a section matching the filter
+// criteria. Wildcard topic clauses match any log, so the result may contain logs
+// with fewer topics than the criteria, which need to be filtered by the caller.
+func Match(db ethdb.KeyValueReader, section, size uint64, addresses []common.Address, topics [][]common.Hash) ([]Position, error) {
+	if len(topics) > maxTopics {
+		return nil, nil
+	}
+	var (
+		start   = section * size
+		matches []Position
+		first   = true
+	)
+	// clause returns the union of the posting lists of the alternative values
+	clause := func(tag byte, values [][]byte) ([]Position, error) {
+		var list []Position
+		for _, value := range values {
+			positions, err := DecodePostings(rawdb.ReadLogIndexPostings(db, section, tag, value), start)
+			if err != nil {
+				return nil, err
+			}
+			list = union(list, positions)
+		}
+		return list, nil
+	}
+	// narrow intersects the matches with a clause
+	narrow := func(tag byte, values [][]byte) error {
+		list, err := clause(tag, values)
+		if err != nil {
+			return err
+		}
+		if first {
+			matches, first = list, false
+		} else {
+			matches = intersect(matches, list)
+		}
+		return nil
+	}
+	if len(addresses) > 0 {
+		values := make([][]byte, len(addresses))
+		for i, addr := range addresses {
+			values[i] = addr.Bytes()
+		}
+		if err := narrow(rawdb.LogIndexAddressTag, values); err != nil {
+			return nil, err
+		}
+	}
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue
+		}
+		if !first && len(matches) == 0 {
+			break
+		}
+		values := make([][]byte, len(sub))
+		for j, topic := range sub {
+			values[j] = topic.Bytes()
+		}
+		if err := narrow(byte(i), values); err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
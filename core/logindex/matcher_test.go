@@ -0,0 +1,75 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the matches are narrowed down to the logs satisfying all clauses,
+// even if the blocks contain the values in different logs.
+func TestMatch(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		addr1  = common.Address{0x01}
+		addr2  = common.Address{0x02}
+		topic1 = common.Hash{0x01}
+		topic2 = common.Hash{0x02}
+	)
+	builder := NewBuilder(1, 10)
+	builder.AddBlock(10, [][]*types.Log{
+		{{Address: addr1, Topics: []common.Hash{topic1}}},
+		{{Address: addr2, Topics: []common.Hash{topic2}}, {Address: addr1, Topics: []common.Hash{topic2, topic1}}},
+	})
+	builder.AddBlock(12, [][]*types.Log{
+		{{Address: addr2, Topics: []common.Hash{topic1}}},
+	})
+	builder.Commit(db)
+
+	tests := []struct {
+		addresses []common.Address
+		topics    [][]common.Hash
+		want      []Position
+	}{
+		{[]common.Address{addr1}, nil, []Position{{10, 0}, {10, 2}}},
+		{[]common.Address{addr1, addr2}, nil, []Position{{10, 0}, {10, 1}, {10, 2}, {12, 0}}},
+		{[]common.Address{addr2}, [][]common.Hash{{topic1}}, []Position{{12, 0}}},
+		{[]common.Address{addr1}, [][]common.Hash{{topic2}}, []Position{{10, 2}}},
+		{nil, [][]common.Hash{nil, {topic1}}, []Position{{10, 2}}},
+		{nil, [][]common.Hash{{topic1, topic2}}, []Position{{10, 0}, {10, 1}, {10, 2}, {12, 0}}},
+		{[]common.Address{{0x03}}, [][]common.Hash{{topic1}}, nil},
+		{nil, [][]common.Hash{nil, nil, nil, nil, {topic1}}, nil},
+	}
+	for i, tt := range tests {
+		have, err := Match(db, 1, 10, tt.addresses, tt.topics)
+		if err != nil {
+			t.Fatalf("test %d: match failed: %v", i, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("test %d: matches mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+	// Other sections must not be affected
+	if have, _ := Match(db, 0, 10, []common.Address{addr1}, nil); len(have) != 0 {
+		t.Errorf("unexpected matches in other section: %v", have)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package logindex implements the posting lists of the log index, mapping the
+// addresses and topics of logs to their positions in the chain.
+//
+// The index is split into sections of consecutive blocks. For every address and
+// topic occurring in the logs of a section, a posting list holds the positions of
+// those logs, ordered by block number and log index within the block.
+package logindex
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errInvalidPostings = errors.New("invalid log index postings")
+
+// Position is the position of a log in the chain.
+type Position struct {
+	Block uint64 // Number of the block containing the log
+	Index uint32 // Index of the log within the block
+}
+
+// less reports whether p is ordered before o.
+func (p Position) less(o Position) bool {
+	return p.Block < o.Block || (p.Block == o.Block && p.Index < o.Index)
+}
+
+// EncodePostings encodes a sorted list of log positions within the section
+// starting at the given block.
+//
+// Every position is encoded as the block number delta to the previous one and
+// the log index, itself delta encoded within the same block, both as varints.
+func EncodePostings(positions []Position, start uint64) []byte {
+	var (
+		enc   = make([]byte, 0, 2*len(positions))
+		block = start
+		index uint32
+	)
+	for i, pos := range positions {
+		delta := pos.Block - block
+		enc = binary.AppendUvarint(enc, delta)
+		if i > 0 && delta == 0 {
+			enc = binary.AppendUvarint(enc, uint64(pos.Index-index))
+		} else {
+			enc = binary.AppendUvarint(enc, uint64(pos.Index))
+		}
+		block, index = pos.Block, pos.Index
+	}
+	return enc
+}
+
+// DecodePostings decodes a list of log positions within the section starting at
+// the given block.
+func DecodePostings(data []byte, start uint64) ([]Position, error) {
+	var (
+		positions []Position
+		block     = start
+		index     uint32
+	)
+	for len(data) > 0 {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errInvalidPostings
+		}
+		data = data[n:]
+		idx, n := binary.Uvarint(data)
+		if n <= 0 || idx > uint64(^uint32(0)) {
+			return nil, errInvalidPostings
+		}
+		data = data[n:]
+
+		if len(positions) > 0 && delta == 0 {
+			if idx == 0 {
+				return nil, errInvalidPostings // duplicate position
+			}
+			index += uint32(idx)
+		} else {
+			index = uint32(idx)
+		}
+		block += delta
+		positions = append(positions, Position{Block: block, Index: index})
+	}
+	return positions, nil
+}
+
+// union merges two sorted lists of positions.
+func union(a, b []Position) []Position {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	merged := make([]Position, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0].less(b[0]):
+			merged, a = append(merged, a[0]), a[1:]
+		case b[0].less(a[0]):
+			merged, b = append(merged, b[0]), b[1:]
+		default:
+			merged, a, b = append(merged, a[0]), a[1:], b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
+// intersect returns the positions contained in both sorted lists.
+func intersect(a, b []Position) []Position {
+	var common []Position
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0].less(b[0]):
+			a = a[1:]
+		case b[0].less(a[0]):
+			b = b[1:]
+		default:
+			common, a, b = append(common, a[0]), a[1:], b[1:]
+		}
+	}
+	return common
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logindex
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests that posting lists survive an encoding roundtrip.
+func TestPostingsRoundtrip(t *testing.T) {
+	tests := [][]Position{
+		nil,
+		{{Block: 4096, Index: 0}},
+		{{Block: 4096, Index: 3}, {Block: 4096, Index: 4}, {Block: 4100, Index: 0}, {Block: 8191, Index: 70000}},
+		{{Block: 5000, Index: 1}, {Block: 5001, Index: 1}, {Block: 5001, Index: 9}},
+	}
+	for i, positions := range tests {
+		enc := EncodePostings(positions, 4096)
+		dec, err := DecodePostings(enc, 4096)
+		if err != nil {
+			t.Fatalf("test %d: failed to decode: %v", i, err)
+		}
+		if !reflect.DeepEqual(dec, positions) {
+			t.Errorf("test %d: roundtrip mismatch: have %v, want %v", i, dec, positions)
+		}
+	}
+	// Truncated and duplicate entries must be rejected
+	for i, data := range [][]byte{{0x80}, {0x01}, {0x00, 0x01, 0x00, 0x00}} {
+		if _, err := DecodePostings(data, 0); err == nil {
+			t.Errorf("invalid postings %d: no error", i)
+		}
+	}
+}
+
+// Tests the merging of sorted posting lists.
+func TestPostingsMerge(t *testing.T) {
+	var (
+		a = []Position{{1, 0}, {1, 2}, {3, 0}, {7, 1}}
+		b = []Position{{1, 1}, {1, 2}, {7, 1}, {8, 0}}
+	)
+	if have, want := union(a, b), []Position{{1, 0}, {1, 1}, {1, 2}, {3, 0}, {7, 1}, {8, 0}}; !reflect.DeepEqual(have, want) {
+		t.Errorf("union mismatch: have %v, want %v", have, want)
+	}
+	if have, want := intersect(a, b), []Position{{1, 2}, {7, 1}}; !reflect.DeepEqual(have, want) {
+		t.Errorf("intersection mismatch: have %v, want %v", have, want)
+	}
+}
@@ -179,3 +179,39 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// LogIndexAddressTag tags the log index posting lists of log addresses. The
+// posting lists of log topics are tagged by the topic position, from 0 to 3.
+const LogIndexAddressTag byte = 0xff
+
+// ReadLogIndexPostings retrieves the encoded positions of the logs in a section
+// having the given address or topic, as identified by the tag.
+func ReadLogIndexPostings(db ethdb.KeyValueReader, section uint64, tag byte, value []byte) []byte {
+	data, _ := db.Get(logIndexPostingsKey(section, tag, value))
+	return data
+}
+
+// WriteLogIndexPostings stores the encoded positions of the logs in a section
+// having the given address or topic, as identified by the tag.
+func WriteLogIndexPostings(db ethdb.KeyValueWriter, section uint64, tag byte, value []byte, postings []byte) {
+	if err := db.Put(logIndexPostingsKey(section, tag, value), postings); err != nil {
+		log.Crit("Failed to store log index postings", "err", err)
+	}
+}
+
+// DeleteLogIndexSection removes all posting lists of a log index section, which
+// are read from db and deleted via w.
+func DeleteLogIndexSection(db ethdb.Iteratee, w ethdb.KeyValueWriter, section uint64) {
+	prefix := logIndexPostingsKey(section, 0, nil)[:len(logIndexPostingsPrefix)+8]
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if err := w.Delete(it.Key()); err != nil {
+			log.Crit("Failed to delete log index postings", "err", err)
+		}
+	}
+	if it.Error() != nil {
+		log.Crit("Failed to iterate log index postings", "err", it.Error())
+	}
+}
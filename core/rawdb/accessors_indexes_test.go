@@ -154,3 +154,25 @@ func TestDeleteBloomBits(t *testing.T) {
 	check(1, 1, params.MainnetGenesisHash, true)
 	check(1, 1, params.SepoliaGenesisHash, true)
 }
+
+// Tests that the log index postings are stored apart from the table of the log
+// indexer and are deleted per section.
+func TestLogIndexPostings(t *testing.T) {
+	db := NewMemoryDatabase()
+	for s := uint64(0); s < 2; s++ {
+		WriteLogIndexPostings(db, s, LogIndexAddressTag, common.Address{0x01}.Bytes(), []byte{0x01, 0x02})
+	}
+	it := NewTable(db, string(LogIndexPrefix)).NewIterator(nil, nil)
+	for it.Next() {
+		t.Errorf("Postings key %x within the log indexer table", it.Key())
+	}
+	it.Release()
+
+	DeleteLogIndexSection(db, db, 0)
+	if postings := ReadLogIndexPostings(db, 0, LogIndexAddressTag, common.Address{0x01}.Bytes()); len(postings) != 0 {
+		t.Errorf("Postings of deleted section remain: %x", postings)
+	}
+	if postings := ReadLogIndexPostings(db, 1, LogIndexAddressTag, common.Address{0x01}.Bytes()); !bytes.Equal(postings, []byte{0x01, 0x02}) {
+		t.Errorf("Postings mismatch: have %x, want %x", postings, []byte{0x01, 0x02})
+	}
+}
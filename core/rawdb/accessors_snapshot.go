@@ -208,3 +208,170 @@ func WriteSnapshotSyncStatus(db ethdb.KeyValueWriter, status []byte) {
 		log.Crit("Failed to store snapshot sync status", "err", err)
 	}
 }
+
+// ReadSnapshotHistoryID retrieves the id of the snapshot history whose state
+// transition ends in the provided state root.
+func ReadSnapshotHistoryID(db ethdb.KeyValueReader, root common.Hash) *uint64 {
+	data, err := db.Get(snapshotHistoryIDKey(root))
+	if err != nil || len(data) != 8 {
+		return nil
+	}
+	id := binary.BigEndian.Uint64(data)
+	return &id
+}
+
+// WriteSnapshotHistoryID stores the snapshot history lookup of a state root.
+func WriteSnapshotHistoryID(db ethdb.KeyValueWriter, root common.Hash, id uint64) {
+	if err := db.Put(snapshotHistoryIDKey(root), encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store snapshot history ID", "err", err)
+	}
+}
+
+// DeleteSnapshotHistoryID deletes the snapshot history lookup of a state root.
+func DeleteSnapshotHistoryID(db ethdb.KeyValueWriter, root common.Hash) {
+	if err := db.Delete(snapshotHistoryIDKey(root)); err != nil {
+		log.Crit("Failed to delete snapshot history ID", "err", err)
+	}
+}
+
+// ReadSnapshotHistoryIndexed retrieves the number of snapshot histories whose
+// modifications have been indexed.
+func ReadSnapshotHistoryIndexed(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(snapshotHistoryIndexedKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteSnapshotHistoryIndexed stores the number of snapshot histories whose
+// modifications have been indexed.
+func WriteSnapshotHistoryIndexed(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(snapshotHistoryIndexedKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store snapshot history index marker", "err", err)
+	}
+}
+
+// WriteSnapshotHistoryAccountIndex marks the account as modified by the given
+// snapshot history.
+func WriteSnapshotHistoryAccountIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Put(snapshotHistoryAccountKey(accountHash, id), nil); err != nil {
+		log.Crit("Failed to store snapshot history account index", "err", err)
+	}
+}
+
+// DeleteSnapshotHistoryAccountIndex removes the account modification marker of
+// the given snapshot history.
+func DeleteSnapshotHistoryAccountIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Delete(snapshotHistoryAccountKey(accountHash, id)); err != nil {
+		log.Crit("Failed to delete snapshot history account index", "err", err)
+	}
+}
+
+// WriteSnapshotHistoryStorageIndex marks the storage slot as modified by the
+// given snapshot history.
+func WriteSnapshotHistoryStorageIndex(db ethdb.KeyValueWriter, accountHash, storageHash common.Hash, id uint64) {
+	if err := db.Put(snapshotHistoryStorageKey(accountHash, storageHash, id), nil); err != nil {
+		log.Crit("Failed to store snapshot history storage index", "err", err)
+	}
+}
+
+// DeleteSnapshotHistoryStorageIndex removes the storage slot modification marker
+// of the given snapshot history.
+func DeleteSnapshotHistoryStorageIndex(db ethdb.KeyValueWriter, accountHash, storageHash common.Hash, id uint64) {
+	if err := db.Delete(snapshotHistoryStorageKey(accountHash, storageHash, id)); err != nil {
+		log.Crit("Failed to delete snapshot history storage index", "err", err)
+	}
+}
+
+// WriteSnapshotHistoryDestructIndex marks the account storage as wiped by the
+// given snapshot history.
+func WriteSnapshotHistoryDestructIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Put(snapshotHistoryDestructKey(accountHash, id), nil); err != nil {
+		log.Crit("Failed to store snapshot history destruct index", "err", err)
+	}
+}
+
+// DeleteSnapshotHistoryDestructIndex removes the storage wipe marker of the
+// given snapshot history.
+func DeleteSnapshotHistoryDestructIndex(db ethdb.KeyValueWriter, accountHash common.Hash, id uint64) {
+	if err := db.Delete(snapshotHistoryDestructKey(accountHash, id)); err != nil {
+		log.Crit("Failed to delete snapshot history destruct index", "err", err)
+	}
+}
+
+// seekSnapshotHistoryIndex returns the first history id not below from, which
+// is indexed under the given key prefix.
+func seekSnapshotHistoryIndex(db ethdb.Iteratee, prefix []byte, from uint64) (uint64, bool) {
+	it := db.NewIterator(prefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		if key := it.Key(); len(key) == len(prefix)+8 {
+			return binary.BigEndian.Uint64(key[len(prefix):]), true
+		}
+	}
+	return 0, false
+}
+
+// SeekSnapshotHistoryAccount returns the id of the first snapshot history not
+// below from, which modified the given account.
+func SeekSnapshotHistoryAccount(db ethdb.Iteratee, accountHash common.Hash, from uint64) (uint64, bool) {
+	prefix := append(common.CopyBytes(snapshotHistoryAccountPrefix), accountHash.Bytes()...)
+	return seekSnapshotHistoryIndex(db, prefix, from)
+}
+
+// SeekSnapshotHistoryStorage returns the id of the first snapshot history not
+// below from, which modified the given storage slot.
+func SeekSnapshotHistoryStorage(db ethdb.Iteratee, accountHash, storageHash common.Hash, from uint64) (uint64, bool) {
+	prefix := append(common.CopyBytes(snapshotHistoryStoragePrefix), accountHash.Bytes()...)
+	prefix = append(prefix, storageHash.Bytes()...)
+	return seekSnapshotHistoryIndex(db, prefix, from)
+}
+
+// SeekSnapshotHistoryDestruct returns the id of the first snapshot history not
+// below from, which wiped the storage of the given account.
+func SeekSnapshotHistoryDestruct(db ethdb.Iteratee, accountHash common.Hash, from uint64) (uint64, bool) {
+	prefix := append(common.CopyBytes(snapshotHistoryDestructPrefix), accountHash.Bytes()...)
+	return seekSnapshotHistoryIndex(db, prefix, from)
+}
+
+// DeleteSnapshotHistoryIndex removes all the snapshot history lookups and
+// modification markers from the database.
+func DeleteSnapshotHistoryIndex(db ethdb.KeyValueStore) error {
+	batch := db.NewBatch()
+	for _, prefix := range [][]byte{snapshotHistoryIDPrefix, snapshotHistoryAccountPrefix, snapshotHistoryStoragePrefix, snapshotHistoryDestructPrefix} {
+		it := db.NewIterator(prefix, nil)
+		for it.Next() {
+			batch.Delete(it.Key())
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+	}
+	batch.Delete(snapshotHistoryIndexedKey)
+	return batch.Write()
+}
+
+// ReadSnapshotHistory retrieves the snapshot history with the given id.
+func ReadSnapshotHistory(db ethdb.AncientReaderOp, id uint64) []byte {
+	blob, err := db.Ancient(SnapshotHistoryTable, id)
+	if err != nil {
+		return nil
+	}
+	return blob
+}
+
+// WriteSnapshotHistory appends the snapshot history with the given id into the
+// freezer.
+func WriteSnapshotHistory(db ethdb.AncientWriter, id uint64, blob []byte) error {
+	_, err := db.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		return op.AppendRaw(SnapshotHistoryTable, id, blob)
+	})
+	return err
+}
@@ -46,6 +46,25 @@ var chainFreezerNoSnappy = map[string]bool{
 	ChainFreezerDifficultyTable: true,
 }
 
+// chainFreezerCodecTables are the ancient-tables for which a codec deviating from
+// snappy can be opted into. Bodies and receipts make up the bulk of the chain
+// data and contain many repeated patterns, which zstd compresses considerably
+// better.
+var chainFreezerCodecTables = []string{ChainFreezerBodiesTable, ChainFreezerReceiptTable}
+
+// chainFreezerCodecs declares the given codec for the bulky chain tables. An
+// empty codec declares none, leaving them snappy compressed.
+func chainFreezerCodecs(codec string) map[string]string {
+	if codec == "" {
+		return nil
+	}
+	codecs := make(map[string]string)
+	for _, table := range chainFreezerCodecTables {
+		codecs[table] = codec
+	}
+	return codecs
+}
+
 const (
 	// stateHistoryTableSize defines the maximum size of freezer data files.
 	stateHistoryTableSize = 2 * 1000 * 1000 * 1000
@@ -66,16 +85,34 @@ var stateFreezerNoSnappy = map[string]bool{
 	stateHistoryStorageData:  false,
 }
 
+const (
+	// snapshotHistoryTableSize defines the maximum size of freezer data files.
+	snapshotHistoryTableSize = 2 * 1000 * 1000 * 1000
+
+	// SnapshotHistoryTable indicates the name of the freezer snapshot history table.
+	SnapshotHistoryTable = "history"
+)
+
+var snapshotFreezerNoSnappy = map[string]bool{
+	SnapshotHistoryTable: false,
+}
+
 // The list of identifiers of ancient stores.
 var (
-	chainFreezerName = "chain" // the folder name of chain segment ancient store.
-	stateFreezerName = "state" // the folder name of reverse diff ancient store.
+	chainFreezerName    = "chain"    // the folder name of chain segment ancient store.
+	stateFreezerName    = "state"    // the folder name of reverse diff ancient store.
+	snapshotFreezerName = "snapshot" // the folder name of snapshot reverse diff ancient store.
 )
 
 // freezers the collections of all builtin freezers.
-var freezers = []string{chainFreezerName, stateFreezerName}
+var freezers = []string{chainFreezerName, stateFreezerName, snapshotFreezerName}
 
 // NewStateFreezer initializes the freezer for state history.
 func NewStateFreezer(ancientDir string, readOnly bool) (*ResettableFreezer, error) {
 	return NewResettableFreezer(filepath.Join(ancientDir, stateFreezerName), "eth/db/state", readOnly, stateHistoryTableSize, stateFreezerNoSnappy)
 }
+
+// NewSnapshotFreezer initializes the freezer for snapshot history.
+func NewSnapshotFreezer(ancientDir string, readOnly bool) (*ResettableFreezer, error) {
+	return NewResettableFreezer(filepath.Join(ancientDir, snapshotFreezerName), "eth/db/snapshot", readOnly, snapshotHistoryTableSize, snapshotFreezerNoSnappy)
+}
@@ -18,6 +18,7 @@ package rawdb
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -108,6 +109,26 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 			}
 			infos = append(infos, info)
 
+		case snapshotFreezerName:
+			datadir, err := db.AncientDatadir()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := os.Stat(filepath.Join(datadir, snapshotFreezerName)); err != nil {
+				continue // Snapshot history retention never enabled
+			}
+			f, err := NewSnapshotFreezer(datadir, true)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+
+			info, err := inspect(snapshotFreezerName, snapshotFreezerNoSnappy, f)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+
 		default:
 			return nil, fmt.Errorf("unknown freezer, supported ones: %v", freezers)
 		}
@@ -129,6 +150,8 @@ func InspectFreezerTable(ancient string, freezerName string, tableName string, s
 		path, tables = resolveChainFreezerDir(ancient), chainFreezerNoSnappy
 	case stateFreezerName:
 		path, tables = filepath.Join(ancient, freezerName), stateFreezerNoSnappy
+	case snapshotFreezerName:
+		path, tables = filepath.Join(ancient, freezerName), snapshotFreezerNoSnappy
 	default:
 		return fmt.Errorf("unknown freezer, supported ones: %v", freezers)
 	}
@@ -147,3 +170,41 @@ func InspectFreezerTable(ancient string, freezerName string, tableName string, s
 	table.dumpIndexStdout(start, end)
 	return nil
 }
+
+// RecompressFreezerTable rewrites all the items of a freezer table with the
+// given codec, optionally building a dictionary for it. The freezer must not be
+// in use by any other process, and the table must not have been tail-truncated.
+func RecompressFreezerTable(ancient string, freezerName string, tableName string, codec string, dictionary bool) error {
+	var (
+		path    string
+		tables  map[string]bool
+		maxSize uint32
+	)
+	switch freezerName {
+	case chainFreezerName:
+		path, tables, maxSize = resolveChainFreezerDir(ancient), chainFreezerNoSnappy, freezerTableSize
+	case stateFreezerName:
+		path, tables, maxSize = filepath.Join(ancient, freezerName), stateFreezerNoSnappy, stateHistoryTableSize
+	case snapshotFreezerName:
+		path, tables, maxSize = filepath.Join(ancient, freezerName), snapshotFreezerNoSnappy, snapshotHistoryTableSize
+	default:
+		return fmt.Errorf("unknown freezer, supported ones: %v", freezers)
+	}
+	if _, exist := tables[tableName]; !exist {
+		var names []string
+		for name := range tables {
+			names = append(names, name)
+		}
+		return fmt.Errorf("unknown table, supported ones: %v", names)
+	}
+	if _, err := newFreezerCodec(codec, nil); err != nil {
+		return err
+	}
+	f, err := NewFreezer(path, "", false, maxSize, tables)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.RecompressTable(tableName, codec, dictionary)
+}
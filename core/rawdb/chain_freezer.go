@@ -51,9 +51,10 @@ type chainFreezer struct {
 	trigger chan chan struct{} // Manual blocking freeze trigger, test determinism
 }
 
-// newChainFreezer initializes the freezer for ancient chain data.
-func newChainFreezer(datadir string, namespace string, readonly bool) (*chainFreezer, error) {
-	freezer, err := NewChainFreezer(datadir, namespace, readonly)
+// newChainFreezer initializes the freezer for ancient chain data, declaring the
+// given codec for the bulky tables if it's not empty.
+func newChainFreezer(datadir string, namespace string, readonly bool, codec string) (*chainFreezer, error) {
+	freezer, err := NewChainFreezerWithCodec(datadir, namespace, readonly, codec)
 	if err != nil {
 		return nil, err
 	}
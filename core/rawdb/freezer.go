@@ -79,7 +79,14 @@ type Freezer struct {
 // NewChainFreezer is a small utility method around NewFreezer that sets the
 // default parameters for the chain storage.
 func NewChainFreezer(datadir string, namespace string, readonly bool) (*Freezer, error) {
-	return NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerNoSnappy)
+	return NewChainFreezerWithCodec(datadir, namespace, readonly, "")
+}
+
+// NewChainFreezerWithCodec creates a chain freezer like NewChainFreezer, with
+// the given codec declared for the bodies and receipts tables. An empty codec
+// keeps them snappy compressed.
+func NewChainFreezerWithCodec(datadir string, namespace string, readonly bool, codec string) (*Freezer, error) {
+	return NewFreezerWithCodecs(datadir, namespace, readonly, freezerTableSize, chainFreezerNoSnappy, chainFreezerCodecs(codec))
 }
 
 // NewFreezer creates a freezer instance for maintaining immutable ordered
@@ -88,6 +95,14 @@ func NewChainFreezer(datadir string, namespace string, readonly bool) (*Freezer,
 // The 'tables' argument defines the data tables. If the value of a map
 // entry is true, snappy compression is disabled for the table.
 func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool) (*Freezer, error) {
+	return NewFreezerWithCodecs(datadir, namespace, readonly, maxTableSize, tables, nil)
+}
+
+// NewFreezerWithCodecs creates a freezer instance like NewFreezer, additionally
+// declaring the codecs to compress the tables with. Tables missing from 'codecs'
+// use snappy, unless their compression is disabled. The declared codecs are only
+// applied to new tables, existing ones need to be recompressed.
+func NewFreezerWithCodecs(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool, codecs map[string]string) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -126,7 +141,7 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 
 	// Create the tables.
 	for name, disableSnappy := range tables {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly)
+		table, err := newTableWithCodec(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, codecs[name], readonly)
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
@@ -403,6 +418,51 @@ type convertLegacyFn = func([]byte) ([]byte, error)
 // MigrateTable processes the entries in a given table in sequence
 // converting them to a new format if they're of an old format.
 func (f *Freezer) MigrateTable(kind string, convert convertLegacyFn) error {
+	open := func(path string, table *freezerTable) (*freezerTable, error) {
+		return newFreezerTable(path, kind, table.noCompression, false)
+	}
+	return f.rewriteTable(kind, open, convert)
+}
+
+// RecompressTable rewrites all the items of a given table with a different codec.
+// For codecs supporting it, a dictionary is optionally trained from the items
+// first. The table instance is unusable after the rewrite, so the freezer needs
+// to be reopened, e.g. by running it offline.
+//
+// Like all table rewrites, recompression is limited to tables which were never
+// tail-truncated, so it's unavailable for pruned state and snapshot histories.
+func (f *Freezer) RecompressTable(kind string, codec string, dictionary bool) error {
+	open := func(path string, table *freezerTable) (*freezerTable, error) {
+		if table.noCompression {
+			return nil, errors.New("table compression is disabled")
+		}
+		newTable, err := newTableWithCodec(path, kind, metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, table.maxFileSize, false, codec, false)
+		if err != nil {
+			return nil, err
+		}
+		// Train the dictionary, unless resuming a previous attempt
+		if codec != FreezerCodecZstd || !dictionary || newTable.items.Load() != 0 {
+			return newTable, nil
+		}
+		dict := TrainFreezerDictionary(sampleTableItems(table, freezerDictionarySamples), freezerDictionarySize)
+		if dict == nil {
+			return newTable, nil
+		}
+		log.Info("Trained freezer table dictionary", "table", kind, "size", common.StorageSize(len(dict)))
+		if err := newTable.setDictionary(dict); err != nil {
+			newTable.Close()
+			return nil, err
+		}
+		return newTable, nil
+	}
+	convert := func(blob []byte) ([]byte, error) { return blob, nil }
+	return f.rewriteTable(kind, open, convert)
+}
+
+// rewriteTable processes the entries in a given table in sequence, converting
+// them into a new table created by open in the given directory, which then
+// replaces the original table files.
+func (f *Freezer) rewriteTable(kind string, open func(path string, table *freezerTable) (*freezerTable, error), convert convertLegacyFn) error {
 	if f.readonly {
 		return errReadOnly
 	}
@@ -448,7 +508,7 @@ func (f *Freezer) MigrateTable(kind string, convert convertLegacyFn) error {
 	// Set up new dir for the migrated table, the content of which
 	// we'll at the end move over to the ancients dir.
 	migrationPath := filepath.Join(ancientsPath, "migration")
-	newTable, err := newFreezerTable(migrationPath, kind, table.noCompression, false)
+	newTable, err := open(migrationPath, table)
 	if err != nil {
 		return err
 	}
@@ -486,6 +546,7 @@ func (f *Freezer) MigrateTable(kind string, convert convertLegacyFn) error {
 	// Release and delete old table files. Note this won't
 	// delete the index file.
 	table.releaseFilesAfter(0, true)
+	os.Remove(filepath.Join(ancientsPath, freezerDictionaryName(kind)))
 
 	if err := newTable.Close(); err != nil {
 		return err
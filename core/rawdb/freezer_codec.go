@@ -0,0 +1,302 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// FreezerCodecSnappy is the name of the snappy block codec. Compressed tables
+	// without a codec declared in their metadata use it implicitly.
+	FreezerCodecSnappy = "snappy"
+
+	// FreezerCodecZstd is the name of the zstd codec, optionally using a
+	// dictionary trained from the table's own items.
+	FreezerCodecZstd = "zstd"
+
+	// freezerDictionarySize is the maximum content size of the trained
+	// dictionaries, excluding their entropy tables.
+	freezerDictionarySize = 112 * 1024
+
+	// freezerDictionarySamples is the number of items to train dictionaries from.
+	freezerDictionarySamples = 1024
+)
+
+// FreezerCodec compresses the items of a freezer table. Every item is encoded on
+// its own, so they all remain randomly accessible through the table index.
+type FreezerCodec interface {
+	// Encode compresses the item, potentially reusing the dst buffer.
+	Encode(dst, item []byte) []byte
+
+	// Decode decompresses an item.
+	Decode(blob []byte) ([]byte, error)
+
+	// DecodedLen returns the decompressed size of an item.
+	DecodedLen(blob []byte) (int, error)
+}
+
+// FreezerCodecFactory creates a codec, with the dictionary trained for the table
+// or nil if there's none.
+type FreezerCodecFactory func(dict []byte) (FreezerCodec, error)
+
+var (
+	freezerCodecs     = make(map[string]FreezerCodecFactory)
+	freezerCodecsLock sync.RWMutex
+)
+
+func init() {
+	RegisterFreezerCodec(FreezerCodecSnappy, func(dict []byte) (FreezerCodec, error) {
+		if dict != nil {
+			return nil, errors.New("snappy doesn't support dictionaries")
+		}
+		return snappyCodec{}, nil
+	})
+	RegisterFreezerCodec(FreezerCodecZstd, newZstdCodec)
+}
+
+// RegisterFreezerCodec makes a codec available to the freezer tables under the
+// given name. It panics if a codec with the same name is already registered.
+func RegisterFreezerCodec(name string, factory FreezerCodecFactory) {
+	freezerCodecsLock.Lock()
+	defer freezerCodecsLock.Unlock()
+
+	if _, ok := freezerCodecs[name]; ok {
+		panic(fmt.Sprintf("freezer codec %q already registered", name))
+	}
+	freezerCodecs[name] = factory
+}
+
+// newFreezerCodec creates an instance of the named codec.
+func newFreezerCodec(name string, dict []byte) (FreezerCodec, error) {
+	freezerCodecsLock.RLock()
+	factory, ok := freezerCodecs[name]
+	freezerCodecsLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown freezer codec %q", name)
+	}
+	return factory(dict)
+}
+
+// snappyCodec is the legacy codec compressing items in snappy block format.
+type snappyCodec struct{}
+
+func (snappyCodec) Encode(dst, item []byte) []byte {
+	// The snappy library does not care what the capacity of the buffer is,
+	// but only checks the length. If the length is too small, it will
+	// allocate a brand new buffer.
+	// To avoid that, we check the required size here, and grow the size of the
+	// buffer to utilize the full capacity.
+	if n := snappy.MaxEncodedLen(len(item)); len(dst) < n {
+		if cap(dst) < n {
+			dst = make([]byte, n)
+		}
+		dst = dst[:n]
+	}
+	return snappy.Encode(dst, item)
+}
+
+func (snappyCodec) Decode(blob []byte) ([]byte, error) {
+	return snappy.Decode(nil, blob)
+}
+
+func (snappyCodec) DecodedLen(blob []byte) (int, error) {
+	return snappy.DecodedLen(blob)
+}
+
+// zstdCodec compresses items into individual zstd frames.
+type zstdCodec struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// newZstdCodec creates a zstd codec, using the given dictionary if it's non-nil.
+// Dictionaries in zstd format are used with their entropy tables, anything else
+// as raw content.
+func newZstdCodec(dict []byte) (FreezerCodec, error) {
+	var (
+		eopts = []zstd.EOption{zstd.WithEncoderConcurrency(1), zstd.WithEncoderCRC(false)}
+		dopts = []zstd.DOption{zstd.WithDecoderConcurrency(0)}
+	)
+	switch {
+	case dict == nil:
+	case isZstdDictionary(dict):
+		eopts = append(eopts, zstd.WithEncoderDict(dict))
+		dopts = append(dopts, zstd.WithDecoderDicts(dict))
+	default:
+		id := freezerDictionaryID(dict)
+		eopts = append(eopts, zstd.WithEncoderDictRaw(id, dict))
+		dopts = append(dopts, zstd.WithDecoderDictRaw(id, dict))
+	}
+	encoder, err := zstd.NewWriter(nil, eopts...)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil, dopts...)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCodec{encoder: encoder, decoder: decoder}, nil
+}
+
+func (c *zstdCodec) Encode(dst, item []byte) []byte {
+	return c.encoder.EncodeAll(item, dst[:0])
+}
+
+func (c *zstdCodec) Decode(blob []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(blob, nil)
+}
+
+func (c *zstdCodec) DecodedLen(blob []byte) (int, error) {
+	var header zstd.Header
+	if err := header.Decode(blob); err != nil {
+		return 0, err
+	}
+	if !header.HasFCS {
+		data, err := c.Decode(blob)
+		return len(data), err
+	}
+	return int(header.FrameContentSize), nil
+}
+
+// isZstdDictionary reports whether the dictionary is in zstd format, as opposed
+// to raw content.
+func isZstdDictionary(dict []byte) bool {
+	return len(dict) >= 8 && binary.LittleEndian.Uint32(dict) == zstdDictionaryMagic
+}
+
+// zstdDictionaryMagic is the magic number starting zstd format dictionaries.
+const zstdDictionaryMagic = 0xec30a437
+
+// freezerDictionaryID derives the identifier of a dictionary, recorded in the
+// table metadata and used for the compressed frames of raw dictionaries.
+func freezerDictionaryID(dict []byte) uint32 {
+	// Zero is reserved for frames without a dictionary
+	if id := crc32.ChecksumIEEE(dict); id != 0 {
+		return id
+	}
+	return 1
+}
+
+// freezerDictionaryName returns the name of the dictionary file of a table.
+func freezerDictionaryName(table string) string {
+	return fmt.Sprintf("%s.dict", table)
+}
+
+// readFreezerDictionary loads the dictionary of a table, ensuring it's the one
+// declared in the metadata.
+func readFreezerDictionary(path, table string, id uint32) ([]byte, error) {
+	dict, err := os.ReadFile(filepath.Join(path, freezerDictionaryName(table)))
+	if err != nil {
+		return nil, err
+	}
+	if have := freezerDictionaryID(dict); have != id {
+		return nil, fmt.Errorf("freezer dictionary mismatch: have %#x, want %#x", have, id)
+	}
+	return dict, nil
+}
+
+// TrainFreezerDictionary trains a zstd dictionary from sample items, with its
+// content limited to the given size. The content is assembled from half of the
+// samples, with the latter ones placed closer to the end where matches are
+// cheapest. The entropy tables are derived from compressing the other half
+// against it, since samples contained verbatim in the content match entirely.
+//
+// If the samples don't suffice to train entropy tables from, the content alone
+// is returned to be used as a raw dictionary. Nil is returned without samples.
+func TrainFreezerDictionary(samples [][]byte, size int) []byte {
+	var (
+		content = make([]byte, 0, size)
+		train   [][]byte
+	)
+	for i := len(samples) - 1; i >= 0; i-- {
+		if i%2 == 0 && len(samples) > 1 {
+			train = append(train, samples[i])
+			continue
+		}
+		if len(content) == size {
+			continue
+		}
+		sample := samples[i]
+		if room := size - len(content); len(sample) > room {
+			sample = sample[len(sample)-room:]
+		}
+		content = append(sample[:len(sample):len(sample)], content...)
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	dict, err := buildZstdDictionary(zstd.BuildDictOptions{
+		ID:       freezerDictionaryID(content),
+		Contents: train,
+		History:  content,
+		Offsets:  [3]int{1, 4, 8},
+		Level:    zstd.SpeedDefault,
+	})
+	if err != nil {
+		log.Debug("Failed to train freezer dictionary, using raw content", "err", err)
+		return content
+	}
+	return dict
+}
+
+// buildZstdDictionary builds a zstd dictionary, converting the panics of the
+// zstd library on degenerate samples (e.g. too few sequences) into errors.
+func buildZstdDictionary(opts zstd.BuildDictOptions) (dict []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			dict, err = nil, fmt.Errorf("zstd dictionary training failed: %v", r)
+		}
+	}()
+	return zstd.BuildDict(opts)
+}
+
+// sampleTableItems collects a limited number of items spread evenly across a
+// table, to build a dictionary from.
+func sampleTableItems(t *freezerTable, samples uint64) [][]byte {
+	var (
+		tail  = t.itemHidden.Load()
+		items = t.items.Load()
+	)
+	if items <= tail {
+		return nil
+	}
+	step := (items - tail) / samples
+	if step == 0 {
+		step = 1
+	}
+	var output [][]byte
+	for i := tail; i < items; i += step {
+		item, err := t.Retrieve(i)
+		if err != nil {
+			break
+		}
+		output = append(output, item)
+	}
+	return output
+}
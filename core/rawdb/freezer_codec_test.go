@@ -0,0 +1,344 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// codecTestItem generates a compressible item, resembling chain data with its
+// recurring structure and some random content.
+func codecTestItem(i int) []byte {
+	var (
+		rng  = rand.New(rand.NewSource(int64(i)))
+		item []byte
+	)
+	for j := 0; j < 8; j++ {
+		item = append(item, []byte("receipt-log-topic-address-data")...)
+		item = binary.BigEndian.AppendUint64(item, uint64(i))
+		item = binary.BigEndian.AppendUint32(item, rng.Uint32())
+	}
+	return item
+}
+
+// openCodecTestTable opens a compressed table declaring the given codec.
+func openCodecTestTable(t testing.TB, path string, codec string) *freezerTable {
+	t.Helper()
+
+	table, err := newTableWithCodec(path, "test", metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, 2048, false, codec, false)
+	if err != nil {
+		t.Fatalf("Failed to open table: %v", err)
+	}
+	return table
+}
+
+func checkCodecTestItems(t *testing.T, table *freezerTable, from, to int) {
+	t.Helper()
+
+	for i := from; i < to; i++ {
+		blob, err := table.Retrieve(uint64(i))
+		if err != nil {
+			t.Fatalf("Failed to retrieve item %d: %v", i, err)
+		}
+		if !bytes.Equal(blob, codecTestItem(i)) {
+			t.Fatalf("Item %d mismatch: have %x, want %x", i, blob, codecTestItem(i))
+		}
+	}
+}
+
+// Tests that the codec declared for a new table is recorded in its metadata and
+// used across restarts, regardless of the declaration afterwards.
+func TestFreezerTableCodec(t *testing.T) {
+	path := t.TempDir()
+
+	table := openCodecTestTable(t, path, FreezerCodecZstd)
+	batch := table.newBatch()
+	for i := 0; i < 64; i++ {
+		if err := batch.AppendRaw(uint64(i), codecTestItem(i)); err != nil {
+			t.Fatalf("Failed to append item %d: %v", i, err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("Failed to commit batch: %v", err)
+	}
+	checkCodecTestItems(t, table, 0, 64)
+	table.Close()
+
+	// Reopen with the legacy declaration, the items must remain zstd
+	table = openCodecTestTable(t, path, "")
+	defer table.Close()
+
+	if table.codecName != FreezerCodecZstd {
+		t.Fatalf("Codec mismatch: have %q, want %q", table.codecName, FreezerCodecZstd)
+	}
+	checkCodecTestItems(t, table, 0, 64)
+
+	// Ensure the codec survives tail truncations rewriting the metadata
+	if err := table.truncateTail(16); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	meta, err := readMetadata(table.meta)
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if meta.Codec != FreezerCodecZstd || meta.VirtualTail != 16 {
+		t.Fatalf("Metadata mismatch: codec %q, tail %d", meta.Codec, meta.VirtualTail)
+	}
+	checkCodecTestItems(t, table, 16, 64)
+}
+
+// Tests that metadata without the codec fields, as written by earlier versions,
+// is still decoded, defaulting to snappy.
+func TestFreezerTableLegacyCodec(t *testing.T) {
+	path := t.TempDir()
+
+	table := openCodecTestTable(t, path, "")
+	batch := table.newBatch()
+	for i := 0; i < 16; i++ {
+		batch.AppendRaw(uint64(i), codecTestItem(i))
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatalf("Failed to commit batch: %v", err)
+	}
+	table.Close()
+
+	blob, err := os.ReadFile(filepath.Join(path, "test.meta"))
+	if err != nil {
+		t.Fatalf("Failed to read metadata: %v", err)
+	}
+	if legacy := []byte{0xc2, 0x01, 0x80}; !bytes.Equal(blob, legacy) {
+		t.Fatalf("Legacy metadata mismatch: have %x, want %x", blob, legacy)
+	}
+	// Declaring a new codec for a populated table must not change it
+	table = openCodecTestTable(t, path, FreezerCodecZstd)
+	defer table.Close()
+
+	if table.codecName != "" {
+		t.Fatalf("Populated table codec changed to %q", table.codecName)
+	}
+	checkCodecTestItems(t, table, 0, 16)
+}
+
+// Tests that a freezer table can be recompressed with a trained dictionary and
+// that the items remain accessible afterwards.
+func TestFreezerRecompressTable(t *testing.T) {
+	dir := t.TempDir()
+	tables := map[string]bool{"test": false}
+
+	f, err := NewFreezer(dir, "", false, 2048, tables)
+	if err != nil {
+		t.Fatalf("Failed to open freezer: %v", err)
+	}
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := 0; i < 256; i++ {
+			if err := op.AppendRaw("test", uint64(i), codecTestItem(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to append items: %v", err)
+	}
+	snappySize, _ := f.tables["test"].size()
+
+	if err := f.RecompressTable("test", FreezerCodecZstd, true); err != nil {
+		t.Fatalf("Failed to recompress table: %v", err)
+	}
+	f.Close()
+
+	f, err = NewFreezer(dir, "", true, 2048, tables)
+	if err != nil {
+		t.Fatalf("Failed to reopen freezer: %v", err)
+	}
+	defer f.Close()
+
+	table := f.tables["test"]
+	if table.codecName != FreezerCodecZstd || table.dictionary == 0 {
+		t.Fatalf("Recompressed codec mismatch: codec %q, dictionary %#x", table.codecName, table.dictionary)
+	}
+	if zstdSize, _ := table.size(); zstdSize >= snappySize {
+		t.Errorf("Recompressed table not smaller: zstd %d, snappy %d", zstdSize, snappySize)
+	}
+	checkCodecTestItems(t, table, 0, 256)
+
+	dict, err := os.ReadFile(filepath.Join(dir, freezerDictionaryName("test")))
+	if err != nil {
+		t.Fatalf("Failed to read dictionary: %v", err)
+	}
+	if !isZstdDictionary(dict) {
+		t.Errorf("Dictionary not trained")
+	}
+	items, err := f.AncientRange("test", 100, 50, 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve range: %v", err)
+	}
+	for i, item := range items {
+		if !bytes.Equal(item, codecTestItem(100+i)) {
+			t.Fatalf("Range item %d mismatch", 100+i)
+		}
+	}
+}
+
+// Tests that the chain freezer declares the opted-in codec for the bodies and
+// receipts of new tables only.
+func TestChainFreezerCodec(t *testing.T) {
+	dir := t.TempDir()
+
+	f, err := NewChainFreezer(filepath.Join(dir, "snappy"), "", false)
+	if err != nil {
+		t.Fatalf("Failed to open freezer: %v", err)
+	}
+	for name, table := range f.tables {
+		if table.codecName != "" {
+			t.Errorf("Table %s codec mismatch: have %q, want legacy snappy", name, table.codecName)
+		}
+	}
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for name := range chainFreezerNoSnappy {
+			if err := op.AppendRaw(name, 0, []byte{0x01}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to append items: %v", err)
+	}
+	f.Close()
+
+	f, err = NewChainFreezerWithCodec(filepath.Join(dir, "zstd"), "", false, FreezerCodecZstd)
+	if err != nil {
+		t.Fatalf("Failed to open freezer: %v", err)
+	}
+	want := map[string]string{
+		ChainFreezerHeaderTable:  "",
+		ChainFreezerBodiesTable:  FreezerCodecZstd,
+		ChainFreezerReceiptTable: FreezerCodecZstd,
+	}
+	for name, codec := range want {
+		if have := f.tables[name].codecName; have != codec {
+			t.Errorf("Table %s codec mismatch: have %q, want %q", name, have, codec)
+		}
+	}
+	f.Close()
+
+	// Existing tables keep their codec until recompressed
+	f, err = NewChainFreezerWithCodec(filepath.Join(dir, "snappy"), "", false, FreezerCodecZstd)
+	if err != nil {
+		t.Fatalf("Failed to reopen freezer: %v", err)
+	}
+	defer f.Close()
+
+	if have := f.tables[ChainFreezerBodiesTable].codecName; have != "" {
+		t.Errorf("Existing table codec changed: have %q, want legacy snappy", have)
+	}
+}
+
+// Tests that a table refuses to open if its dictionary is missing or altered.
+func TestFreezerDictionaryMismatch(t *testing.T) {
+	path := t.TempDir()
+
+	table := openCodecTestTable(t, path, FreezerCodecZstd)
+	if err := table.setDictionary(codecTestItem(0)); err != nil {
+		t.Fatalf("Failed to set dictionary: %v", err)
+	}
+	table.Close()
+
+	if err := os.WriteFile(filepath.Join(path, freezerDictionaryName("test")), codecTestItem(1), 0644); err != nil {
+		t.Fatalf("Failed to alter dictionary: %v", err)
+	}
+	if _, err := newTableWithCodec(path, "test", metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, 2048, false, FreezerCodecZstd, false); err == nil {
+		t.Fatal("Opened table with altered dictionary")
+	}
+}
+
+func BenchmarkFreezerAncient(b *testing.B) {
+	for _, codec := range []string{FreezerCodecSnappy, FreezerCodecZstd, FreezerCodecZstd + "+dict"} {
+		b.Run(codec, func(b *testing.B) {
+			table := newCodecBenchTable(b, codec)
+			defer table.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := table.Retrieve(uint64(i % 4096)); err != nil {
+					b.Fatal(err)
+				}
+			}
+			size, _ := table.size()
+			b.ReportMetric(float64(size)/4096, "bytes/item")
+		})
+	}
+}
+
+func BenchmarkFreezerAncientRange(b *testing.B) {
+	for _, codec := range []string{FreezerCodecSnappy, FreezerCodecZstd, FreezerCodecZstd + "+dict"} {
+		b.Run(codec, func(b *testing.B) {
+			table := newCodecBenchTable(b, codec)
+			defer table.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := table.RetrieveItems(uint64(i%4000), 64, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+			size, _ := table.size()
+			b.ReportMetric(float64(size)/4096, "bytes/item")
+		})
+	}
+}
+
+// newCodecBenchTable creates a table of 4096 items compressed with the codec,
+// optionally with a trained dictionary if suffixed by "+dict".
+func newCodecBenchTable(b *testing.B, codec string) *freezerTable {
+	var dict bool
+	if codec == FreezerCodecZstd+"+dict" {
+		codec, dict = FreezerCodecZstd, true
+	}
+	table, err := newTableWithCodec(b.TempDir(), "test", metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, freezerTableSize, false, codec, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if dict {
+		var samples [][]byte
+		for i := 0; i < 4096; i += 4 {
+			samples = append(samples, codecTestItem(i))
+		}
+		if err := table.setDictionary(TrainFreezerDictionary(samples, freezerDictionarySize)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	batch := table.newBatch()
+	for i := 0; i < 4096; i++ {
+		if err := batch.AppendRaw(uint64(i), codecTestItem(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		b.Fatal(err)
+	}
+	return table
+}
@@ -36,6 +36,14 @@ type freezerTableMeta struct {
 	// plus the number of items hidden in the table, so it should never
 	// be lower than the "actual tail".
 	VirtualTail uint64
+
+	// Codec is the name of the codec compressing the items of the table. It's
+	// empty for raw tables and legacy snappy compressed ones.
+	Codec string `rlp:"optional"`
+
+	// Dictionary is the identifier of the dictionary used by the codec, zero
+	// if there's none.
+	Dictionary uint32 `rlp:"optional"`
 }
 
 // newMetadata initializes the metadata object with the given virtual tail.
@@ -30,7 +30,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
-	"github.com/golang/snappy"
 )
 
 var (
@@ -98,7 +97,10 @@ type freezerTable struct {
 	// should never be lower than itemOffset.
 	itemHidden atomic.Uint64
 
-	noCompression bool // if true, disables snappy compression. Note: does not work retroactively
+	noCompression bool         // if true, disables compression. Note: does not work retroactively
+	codec         FreezerCodec // Codec compressing the items, nil if compression is disabled
+	codecName     string       // Name of the codec recorded in the metadata, empty for legacy snappy
+	dictionary    uint32       // Identifier of the codec dictionary, zero if there's none
 	readonly      bool
 	maxFileSize   uint32 // Max file size for data-files
 	name          string
@@ -129,6 +131,14 @@ func newFreezerTable(path, name string, disableSnappy, readonly bool) (*freezerT
 // non-existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
 func newTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeGauge metrics.Gauge, maxFilesize uint32, noCompression, readonly bool) (*freezerTable, error) {
+	return newTableWithCodec(path, name, readMeter, writeMeter, sizeGauge, maxFilesize, noCompression, "", readonly)
+}
+
+// newTableWithCodec opens a freezer table like newTable, additionally declaring
+// the codec to compress the items with. The declaration only takes effect for
+// tables without any items yet, existing ones keep the codec recorded in their
+// metadata until recompressed. An empty codec means legacy snappy.
+func newTableWithCodec(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeGauge metrics.Gauge, maxFilesize uint32, noCompression bool, codec string, readonly bool) (*freezerTable, error) {
 	// Ensure the containing directory exists and open the indexEntry file
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
@@ -183,6 +193,12 @@ func newTable(path string, name string, readMeter metrics.Meter, writeMeter metr
 		tab.Close()
 		return nil, err
 	}
+	if !noCompression {
+		if err := tab.setupCodec(codec); err != nil {
+			tab.Close()
+			return nil, err
+		}
+	}
 	// Initialize the starting size counter
 	size, err := tab.sizeNolock()
 	if err != nil {
@@ -194,6 +210,64 @@ func newTable(path string, name string, readMeter metrics.Meter, writeMeter metr
 	return tab, nil
 }
 
+// setupCodec records the declared codec in the metadata if the table is still
+// empty, then instantiates the codec the table was written with.
+func (t *freezerTable) setupCodec(declared string) error {
+	meta, err := readMetadata(t.meta)
+	if err != nil {
+		return err
+	}
+	if meta.Codec != declared && t.items.Load() == 0 && !t.readonly {
+		meta.Codec, meta.Dictionary = declared, 0
+		if err := writeMetadata(t.meta, meta); err != nil {
+			return err
+		}
+	}
+	if declared != "" && meta.Codec != declared {
+		t.logger.Info("Freezer table codec differs from declaration, recompress to switch", "have", meta.Codec, "want", declared)
+	}
+	var dict []byte
+	if meta.Dictionary != 0 {
+		if dict, err = readFreezerDictionary(t.path, t.name, meta.Dictionary); err != nil {
+			return err
+		}
+	}
+	name := meta.Codec
+	if name == "" {
+		name = FreezerCodecSnappy
+	}
+	if t.codec, err = newFreezerCodec(name, dict); err != nil {
+		return err
+	}
+	t.codecName, t.dictionary = meta.Codec, meta.Dictionary
+	return nil
+}
+
+// setDictionary persists the dictionary of an empty table and switches its codec
+// over to using it.
+func (t *freezerTable) setDictionary(dict []byte) error {
+	if t.items.Load() != 0 {
+		return errors.New("dictionary of non-empty table")
+	}
+	codec, err := newFreezerCodec(t.codecName, dict)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(t.path, freezerDictionaryName(t.name)), dict, 0644); err != nil {
+		return err
+	}
+	meta, err := readMetadata(t.meta)
+	if err != nil {
+		return err
+	}
+	meta.Dictionary = freezerDictionaryID(dict)
+	if err := writeMetadata(t.meta, meta); err != nil {
+		return err
+	}
+	t.codec, t.dictionary = codec, meta.Dictionary
+	return nil
+}
+
 // repair cross-checks the head and the index file and truncates them to
 // be in sync with each other after a potential crash / data loss.
 func (t *freezerTable) repair() error {
@@ -497,7 +571,9 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	}
 	// Update the virtual tail marker and hidden these entries in table.
 	t.itemHidden.Store(items)
-	if err := writeMetadata(t.meta, newMetadata(items)); err != nil {
+	meta := newMetadata(items)
+	meta.Codec, meta.Dictionary = t.codecName, t.dictionary
+	if err := writeMetadata(t.meta, meta); err != nil {
 		return err
 	}
 	// Hidden items still fall in the current tail file, no data file
@@ -737,14 +813,14 @@ func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, e
 		item := diskData[offset : offset+diskSize]
 		offset += diskSize
 		decompressedSize := diskSize
-		if !t.noCompression {
-			decompressedSize, _ = snappy.DecodedLen(item)
+		if t.codec != nil {
+			decompressedSize, _ = t.codec.DecodedLen(item)
 		}
 		if i > 0 && maxBytes != 0 && uint64(outputSize+decompressedSize) > maxBytes {
 			break
 		}
-		if !t.noCompression {
-			data, err := snappy.Decode(nil, item)
+		if t.codec != nil {
+			data, err := t.codec.Decode(item)
 			if err != nil {
 				return nil, err
 			}
@@ -950,6 +1026,9 @@ func (t *freezerTable) dumpIndex(w io.Writer, start, stop int64) {
 	}
 	fmt.Fprintf(w, "Version %d count %d, deleted %d, hidden %d\n", meta.Version,
 		t.items.Load(), t.itemOffset.Load(), t.itemHidden.Load())
+	if meta.Codec != "" {
+		fmt.Fprintf(w, "Codec %s dictionary %#x\n", meta.Codec, meta.Dictionary)
+	}
 
 	buf := make([]byte, indexEntrySize)
 
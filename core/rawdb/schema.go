@@ -70,6 +70,9 @@ var (
 	// snapshotSyncStatusKey tracks the snapshot sync status across restarts.
 	snapshotSyncStatusKey = []byte("SnapshotSyncStatus")
 
+	// snapshotHistoryIndexedKey tracks the number of snapshot histories indexed.
+	snapshotHistoryIndexedKey = []byte("SnapshotHistoryIndexed")
+
 	// skeletonSyncStatusKey tracks the skeleton sync status across restarts.
 	skeletonSyncStatusKey = []byte("SkeletonSyncStatus")
 
@@ -115,6 +118,12 @@ var (
 	trieNodeStoragePrefix = []byte("O") // trieNodeStoragePrefix + accountHash + hexPath -> trie node
 	stateIDPrefix         = []byte("L") // stateIDPrefix + state root -> state id
 
+	// Reverse diff histories of the state snapshot.
+	snapshotHistoryIDPrefix       = []byte("sh") // snapshotHistoryIDPrefix + state root -> history id
+	snapshotHistoryAccountPrefix  = []byte("sa") // snapshotHistoryAccountPrefix + account hash + id (uint64 big endian) -> nil
+	snapshotHistoryStoragePrefix  = []byte("so") // snapshotHistoryStoragePrefix + account hash + storage hash + id (uint64 big endian) -> nil
+	snapshotHistoryDestructPrefix = []byte("sd") // snapshotHistoryDestructPrefix + account hash + id (uint64 big endian) -> nil
+
 	PreimagePrefix = []byte("secure-key-")       // PreimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-")  // config prefix for the db
 	genesisPrefix  = []byte("ethereum-genesis-") // genesis state prefix for the db
@@ -122,6 +131,13 @@ var (
 	// BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 	BloomBitsIndexPrefix = []byte("iB")
 
+	// LogIndexPrefix is the data table of the log indexer to track its progress
+	LogIndexPrefix = []byte("iL")
+
+	// logIndexPostingsPrefix holds the posting lists of the log index sections. It's
+	// kept apart from LogIndexPrefix, whose keys are owned by the chain indexer.
+	logIndexPostingsPrefix = []byte("iP") // logIndexPostingsPrefix + section (uint64 big endian) + tag + address or topic -> log positions
+
 	ChtPrefix           = []byte("chtRootV2-") // ChtPrefix + chtNum (uint64 big endian) -> trie root hash
 	ChtTablePrefix      = []byte("cht-")
 	ChtIndexTablePrefix = []byte("chtIndexV2-")
@@ -220,6 +236,16 @@ func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	return key
 }
 
+// logIndexPostingsKey = logIndexPostingsPrefix + section (uint64 big endian) + tag + value
+func logIndexPostingsKey(section uint64, tag byte, value []byte) []byte {
+	key := make([]byte, len(logIndexPostingsPrefix)+9+len(value))
+	copy(key, logIndexPostingsPrefix)
+	binary.BigEndian.PutUint64(key[len(logIndexPostingsPrefix):], section)
+	key[len(logIndexPostingsPrefix)+8] = tag
+	copy(key[len(logIndexPostingsPrefix)+9:], value)
+	return key
+}
+
 // skeletonHeaderKey = skeletonHeaderPrefix + num (uint64 big endian)
 func skeletonHeaderKey(number uint64) []byte {
 	return append(skeletonHeaderPrefix, encodeBlockNumber(number)...)
@@ -259,6 +285,39 @@ func stateIDKey(root common.Hash) []byte {
 	return append(stateIDPrefix, root.Bytes()...)
 }
 
+// snapshotHistoryIDKey = snapshotHistoryIDPrefix + root (32 bytes)
+func snapshotHistoryIDKey(root common.Hash) []byte {
+	return append(snapshotHistoryIDPrefix, root.Bytes()...)
+}
+
+// snapshotHistoryAccountKey = snapshotHistoryAccountPrefix + account hash + id
+func snapshotHistoryAccountKey(accountHash common.Hash, id uint64) []byte {
+	buf := make([]byte, len(snapshotHistoryAccountPrefix)+common.HashLength+8)
+	n := copy(buf, snapshotHistoryAccountPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], id)
+	return buf
+}
+
+// snapshotHistoryStorageKey = snapshotHistoryStoragePrefix + account hash + storage hash + id
+func snapshotHistoryStorageKey(accountHash, storageHash common.Hash, id uint64) []byte {
+	buf := make([]byte, len(snapshotHistoryStoragePrefix)+2*common.HashLength+8)
+	n := copy(buf, snapshotHistoryStoragePrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	n += copy(buf[n:], storageHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], id)
+	return buf
+}
+
+// snapshotHistoryDestructKey = snapshotHistoryDestructPrefix + account hash + id
+func snapshotHistoryDestructKey(accountHash common.Hash, id uint64) []byte {
+	buf := make([]byte, len(snapshotHistoryDestructPrefix)+common.HashLength+8)
+	n := copy(buf, snapshotHistoryDestructPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], id)
+	return buf
+}
+
 // accountTrieNodeKey = trieNodeAccountPrefix + nodePath.
 func accountTrieNodeKey(path []byte) []byte {
 	return append(trieNodeAccountPrefix, path...)
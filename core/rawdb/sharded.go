@@ -0,0 +1,315 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/ethdb/walbatch"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// ShardCount is the number of key-value stores in a sharded database, one
+	// for each leading nibble of the trie paths, as the parallel trie expects.
+	ShardCount = 16
+
+	// ShardManifestName is the name of the manifest file of a sharded database.
+	ShardManifestName = "SHARDS.json"
+
+	// shardManifestVersion is the current version of the sharded database layout.
+	shardManifestVersion = 1
+
+	// shardLayoutTriePath indicates that trie nodes are routed to the shards by
+	// the first nibble of their path, root nodes by the first nibble of their hash.
+	shardLayoutTriePath = "trie-path"
+)
+
+// ShardManifest records the layout of a sharded database, ensuring it's always
+// reopened the same way it was created.
+type ShardManifest struct {
+	Version int      `json:"version"` // Version of the sharded database layout
+	Engine  string   `json:"engine"`  // Backing key-value store of the shards
+	Layout  string   `json:"layout"`  // Scheme used to route the trie nodes to the shards
+	Shards  []string `json:"shards"`  // Directories of the shards, relative to the manifest
+}
+
+// newShardManifest creates the manifest of a new sharded database.
+func newShardManifest(engine string) *ShardManifest {
+	manifest := &ShardManifest{
+		Version: shardManifestVersion,
+		Engine:  engine,
+		Layout:  shardLayoutTriePath,
+	}
+	for i := 0; i < ShardCount; i++ {
+		manifest.Shards = append(manifest.Shards, fmt.Sprintf("shard-%02x", i))
+	}
+	return manifest
+}
+
+// verify checks that the manifest describes a layout this version supports.
+func (m *ShardManifest) verify() error {
+	if m.Version != shardManifestVersion {
+		return fmt.Errorf("unsupported sharded database version %d", m.Version)
+	}
+	if m.Engine != dbLeveldb && m.Engine != dbPebble {
+		return fmt.Errorf("unknown sharded database engine %q", m.Engine)
+	}
+	if m.Layout != shardLayoutTriePath {
+		return fmt.Errorf("unknown sharded database layout %q", m.Layout)
+	}
+	if len(m.Shards) != ShardCount {
+		return fmt.Errorf("invalid shard count: have %d, want %d", len(m.Shards), ShardCount)
+	}
+	seen := make(map[string]bool)
+	for _, shard := range m.Shards {
+		if shard == "" || filepath.IsAbs(shard) || strings.Contains(shard, "..") || seen[shard] {
+			return fmt.Errorf("invalid shard directory %q", shard)
+		}
+		seen[shard] = true
+	}
+	return nil
+}
+
+// ReadShardManifest reads the manifest of the sharded database in the given
+// directory, returning os.ErrNotExist if there's none.
+func ReadShardManifest(dir string) (*ShardManifest, error) {
+	blob, err := os.ReadFile(filepath.Join(dir, ShardManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(ShardManifest)
+	if err := json.Unmarshal(blob, manifest); err != nil {
+		return nil, fmt.Errorf("invalid shard manifest: %v", err)
+	}
+	if err := manifest.verify(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeShardManifest atomically writes the manifest of a sharded database.
+func writeShardManifest(dir string, manifest *ShardManifest) error {
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, ShardManifestName+".tmp")
+	if err := os.WriteFile(tmp, blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, ShardManifestName))
+}
+
+// ShardedDatabase is a set of key-value databases, each storing the trie nodes
+// with a specific leading path nibble. The shards are handed to the parallel trie
+// database which routes the nodes, while the set itself is an ethdb.Database
+// (without a freezer) aggregating the content, maintenance and stats of all the
+// shards.
+//
+// Writes spanning multiple shards go through a write-ahead log kept in the first
+// shard, so that a crash never leaves a subset of them persisted. The shards may
+// still be written directly, as replaying content addressed trie nodes can't
+// override newer data.
+type ShardedDatabase struct {
+	ethdb.Database // Aggregate view of the shards
+
+	manifest *ShardManifest
+	shards   [ShardCount]ethdb.Database
+	wal      *walbatch.Database
+}
+
+// NewShardedDatabase assembles a sharded database from already opened shards,
+// taking ownership of them. Any cross-shard batch interrupted by a crash is
+// replayed before returning.
+func NewShardedDatabase(manifest *ShardManifest, shards [ShardCount]ethdb.Database) (*ShardedDatabase, error) {
+	stores := make([]ethdb.KeyValueStore, ShardCount)
+	for i, shard := range shards {
+		stores[i] = shard
+	}
+	wal, err := walbatch.New(shards[0], stores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sharded database: %v", err)
+	}
+	return &ShardedDatabase{
+		Database: NewDatabase(&shardedStore{shards: shards}),
+		manifest: manifest,
+		shards:   shards,
+		wal:      wal,
+	}, nil
+}
+
+// NewMemoryShardedDatabase creates an ephemeral in-memory sharded database.
+func NewMemoryShardedDatabase() *ShardedDatabase {
+	var shards [ShardCount]ethdb.Database
+	for i := range shards {
+		shards[i] = NewDatabase(memorydb.New())
+	}
+	db, err := NewShardedDatabase(nil, shards)
+	if err != nil {
+		panic(err) // Empty stores have nothing to recover
+	}
+	return db
+}
+
+// OpenSharded opens the sharded database in the given directory, or creates a
+// new one using the requested engine (pebble by default) if none exists. The
+// file handles are split evenly across the shards. Pebble shards share a block
+// cache sized to half of the cache allowance, the other half is split across
+// their memory tables. LevelDB can't share caches between databases, so each of
+// its shards gets an even slice of the allowance instead. Freezers are not
+// supported. Batches interrupted by a crash are replayed, which fails if the
+// database is opened read-only.
+func OpenSharded(o OpenOptions) (*ShardedDatabase, error) {
+	if len(o.AncientsDirectory) != 0 {
+		return nil, errors.New("sharded databases don't support freezers")
+	}
+	manifest, err := ReadShardManifest(o.Directory)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if o.ReadOnly {
+			return nil, fmt.Errorf("no sharded database in %s", o.Directory)
+		}
+		engine := o.Type
+		if engine == "" {
+			engine = dbPebble
+		}
+		manifest = newShardManifest(engine)
+		if err := manifest.verify(); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(o.Directory, 0755); err != nil {
+			return nil, err
+		}
+		if err := writeShardManifest(o.Directory, manifest); err != nil {
+			return nil, err
+		}
+		log.Info("Created sharded database", "dir", o.Directory, "engine", engine, "shards", ShardCount)
+
+	case err != nil:
+		return nil, err
+
+	case len(o.Type) != 0 && o.Type != manifest.Engine:
+		return nil, fmt.Errorf("db.engine choice was %v but found pre-existing %v sharded database", o.Type, manifest.Engine)
+	}
+	var (
+		cache   = o.Cache / ShardCount
+		handles = o.Handles / ShardCount
+		shared  *pebble.Cache
+		shards  [ShardCount]ethdb.Database
+	)
+	if manifest.Engine == dbPebble {
+		shared = pebble.NewCache(o.Cache / 2)
+		defer shared.Release() // The shards hold their own references
+
+		cache = o.Cache / 2 / ShardCount // Memory table allowance of each shard
+	}
+	for i, dir := range manifest.Shards {
+		var (
+			path      = filepath.Join(o.Directory, dir)
+			namespace = fmt.Sprintf("%sshard%02x/", o.Namespace, i)
+			kvdb      ethdb.KeyValueStore
+			err       error
+		)
+		if manifest.Engine == dbPebble {
+			kvdb, err = pebble.NewWithCache(path, shared, cache, handles, namespace, o.ReadOnly, o.Ephemeral)
+		} else {
+			kvdb, err = leveldb.New(path, cache, handles, namespace, o.ReadOnly)
+		}
+		if err != nil {
+			for _, shard := range shards[:i] {
+				shard.Close()
+			}
+			return nil, fmt.Errorf("failed to open shard %d: %v", i, err)
+		}
+		shards[i] = NewDatabase(kvdb)
+	}
+	db, err := NewShardedDatabase(manifest, shards)
+	if err != nil {
+		for _, shard := range shards {
+			shard.Close()
+		}
+		return nil, err
+	}
+	log.Info("Opened sharded database", "dir", o.Directory, "engine", manifest.Engine, "cache", common.StorageSize(o.Cache*1024*1024), "handles", o.Handles)
+	return db, nil
+}
+
+// Manifest returns the layout of the sharded database, nil if it's ephemeral.
+func (db *ShardedDatabase) Manifest() *ShardManifest {
+	return db.manifest
+}
+
+// Shards returns the key-value stores of the sharded database, in the format
+// the parallel trie database expects them.
+func (db *ShardedDatabase) Shards() [ShardCount]ethdb.Database {
+	return db.shards
+}
+
+// WAL returns the write-ahead log updating multiple shards atomically.
+func (db *ShardedDatabase) WAL() *walbatch.Database {
+	return db.wal
+}
+
+// InspectShardedDatabase traverses all the shards of the database and reports
+// the number and size of the entries stored in each of them.
+func InspectShardedDatabase(db *ShardedDatabase) error {
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		stats  [ShardCount]stat
+		total  stat
+	)
+	for i, shard := range db.shards {
+		it := shard.NewIterator(nil, nil)
+		for it.Next() {
+			size := common.StorageSize(len(it.Key()) + len(it.Value()))
+			stats[i].Add(size)
+			total.Add(size)
+
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Inspecting sharded database", "shard", i, "count", total.count, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Shard", "Size", "Items"})
+	table.SetFooter([]string{"Total", total.Size(), total.Count()})
+	for i := range stats {
+		table.Append([]string{fmt.Sprintf("%02x", i), stats[i].Size(), stats[i].Count()})
+	}
+	table.Render()
+	return nil
+}
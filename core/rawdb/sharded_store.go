@@ -0,0 +1,334 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// errShardedNotFound is returned if a key is missing from all the shards.
+var errShardedNotFound = errors.New("not found")
+
+// shardedStore is the aggregate key-value view of the shards of a sharded
+// database. The parallel trie database routes the trie nodes by their path,
+// which is unknown here, so reads look the keys up in every shard, starting
+// with the first one. New keys are stored in the first shard, next to the
+// preimages the parallel trie database keeps there, and deletions are applied
+// to all the shards.
+type shardedStore struct {
+	shards [ShardCount]ethdb.Database
+}
+
+// readers returns the shards as key-value readers, in lookup order.
+func (s *shardedStore) readers() []ethdb.KeyValueReader {
+	readers := make([]ethdb.KeyValueReader, len(s.shards))
+	for i, shard := range s.shards {
+		readers[i] = shard
+	}
+	return readers
+}
+
+// shardedHas reports whether any of the readers holds the key.
+func shardedHas(readers []ethdb.KeyValueReader, key []byte) (bool, error) {
+	for _, reader := range readers {
+		if has, err := reader.Has(key); err != nil || has {
+			return has, err
+		}
+	}
+	return false, nil
+}
+
+// shardedGet retrieves the key from the first of the readers holding it.
+func shardedGet(readers []ethdb.KeyValueReader, key []byte) ([]byte, error) {
+	for _, reader := range readers {
+		has, err := reader.Has(key)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			return reader.Get(key)
+		}
+	}
+	return nil, errShardedNotFound
+}
+
+// Has retrieves if a key is present in any of the shards.
+func (s *shardedStore) Has(key []byte) (bool, error) {
+	return shardedHas(s.readers(), key)
+}
+
+// Get retrieves the given key from the first shard holding it.
+func (s *shardedStore) Get(key []byte) ([]byte, error) {
+	return shardedGet(s.readers(), key)
+}
+
+// Put inserts the given value into the first shard.
+func (s *shardedStore) Put(key []byte, value []byte) error {
+	return s.shards[0].Put(key, value)
+}
+
+// Delete removes the key from all the shards.
+func (s *shardedStore) Delete(key []byte) error {
+	for i, shard := range s.shards {
+		if err := shard.Delete(key); err != nil {
+			return fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Stat returns a particular internal stat of all the shards, each prefixed
+// with the shard's index.
+func (s *shardedStore) Stat(property string) (string, error) {
+	var out strings.Builder
+	for i, shard := range s.shards {
+		stat, err := shard.Stat(property)
+		if err != nil {
+			return "", fmt.Errorf("shard %d: %v", i, err)
+		}
+		fmt.Fprintf(&out, "Shard %02x:\n%s\n", i, strings.TrimRight(stat, "\n"))
+	}
+	return out.String(), nil
+}
+
+// Compact flattens the given key range in all the shards.
+func (s *shardedStore) Compact(start []byte, limit []byte) error {
+	for i, shard := range s.shards {
+		if err := shard.Compact(start, limit); err != nil {
+			return fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Close closes all the shards, returning the first failure.
+func (s *shardedStore) Close() error {
+	var errs []error
+	for _, shard := range s.shards {
+		if err := shard.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewIterator creates an iterator over the merged content of all the shards.
+// If a key is present in multiple shards, the value of the first one is used.
+func (s *shardedStore) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	it := &shardedIterator{cur: -1}
+	for _, shard := range s.shards {
+		iter := shard.NewIterator(prefix, start)
+		it.iters = append(it.iters, iter)
+		it.valid = append(it.valid, iter.Next())
+	}
+	return it
+}
+
+// NewBatch creates a write-only batch spanning all the shards.
+func (s *shardedStore) NewBatch() ethdb.Batch {
+	b := new(shardedBatch)
+	for i, shard := range s.shards {
+		b.batches[i] = shard.NewBatch()
+	}
+	return b
+}
+
+// NewBatchWithSize creates a write-only batch spanning all the shards, with
+// the first one pre-allocated to the given size.
+func (s *shardedStore) NewBatchWithSize(size int) ethdb.Batch {
+	b := new(shardedBatch)
+	for i, shard := range s.shards {
+		if i == 0 {
+			b.batches[i] = shard.NewBatchWithSize(size)
+		} else {
+			b.batches[i] = shard.NewBatch()
+		}
+	}
+	return b
+}
+
+// NewSnapshot creates a snapshot of all the shards. The shards are snapshotted
+// one after the other, so the result is only consistent if there are no writes
+// meanwhile.
+func (s *shardedStore) NewSnapshot() (ethdb.Snapshot, error) {
+	snap := new(shardedSnapshot)
+	for i, shard := range s.shards {
+		var err error
+		if snap.snaps[i], err = shard.NewSnapshot(); err != nil {
+			for _, done := range snap.snaps[:i] {
+				done.Release()
+			}
+			return nil, fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	return snap, nil
+}
+
+// shardedBatch is a batch spanning all the shards of a sharded database. The
+// shards are written one after the other, so a write is not atomic across them.
+type shardedBatch struct {
+	batches [ShardCount]ethdb.Batch
+	size    int
+}
+
+// Put inserts the given value into the batch of the first shard.
+func (b *shardedBatch) Put(key, value []byte) error {
+	b.size += len(key) + len(value)
+	return b.batches[0].Put(key, value)
+}
+
+// Delete inserts a key removal into the batches of all the shards.
+func (b *shardedBatch) Delete(key []byte) error {
+	b.size += len(key)
+	for _, batch := range b.batches {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *shardedBatch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes the batches of all the shards.
+func (b *shardedBatch) Write() error {
+	for i, batch := range b.batches {
+		if err := batch.Write(); err != nil {
+			return fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Reset resets the batches of all the shards for reuse.
+func (b *shardedBatch) Reset() {
+	for _, batch := range b.batches {
+		batch.Reset()
+	}
+	b.size = 0
+}
+
+// Replay replays the batch contents. The batch of the first shard holds all
+// the operations, the others only the deletions.
+func (b *shardedBatch) Replay(w ethdb.KeyValueWriter) error {
+	return b.batches[0].Replay(w)
+}
+
+// shardedSnapshot is a snapshot of all the shards of a sharded database.
+type shardedSnapshot struct {
+	snaps [ShardCount]ethdb.Snapshot
+}
+
+// Has retrieves if a key is present in any of the shard snapshots.
+func (s *shardedSnapshot) Has(key []byte) (bool, error) {
+	return shardedHas(s.readers(), key)
+}
+
+// Get retrieves the given key from the first shard snapshot holding it.
+func (s *shardedSnapshot) Get(key []byte) ([]byte, error) {
+	return shardedGet(s.readers(), key)
+}
+
+// Release releases the snapshots of all the shards.
+func (s *shardedSnapshot) Release() {
+	for _, snap := range s.snaps {
+		snap.Release()
+	}
+}
+
+// readers returns the shard snapshots as key-value readers, in lookup order.
+func (s *shardedSnapshot) readers() []ethdb.KeyValueReader {
+	readers := make([]ethdb.KeyValueReader, len(s.snaps))
+	for i, snap := range s.snaps {
+		readers[i] = snap
+	}
+	return readers
+}
+
+// shardedIterator merges the iterators of all the shards into a single one
+// traversing the keys in ascending order.
+type shardedIterator struct {
+	iters []ethdb.Iterator
+	valid []bool // Whether the iterator is positioned on an unconsumed entry
+	cur   int    // Iterator positioned on the current entry, -1 if none
+}
+
+// Next moves the iterator to the next key/value pair, skipping the duplicates
+// of the current key in the other shards.
+func (it *shardedIterator) Next() bool {
+	if it.cur >= 0 {
+		key := common.CopyBytes(it.iters[it.cur].Key())
+		for i, iter := range it.iters {
+			if it.valid[i] && bytes.Equal(iter.Key(), key) {
+				it.valid[i] = iter.Next()
+			}
+		}
+	}
+	it.cur = -1
+	for i, iter := range it.iters {
+		if !it.valid[i] {
+			continue
+		}
+		if it.cur < 0 || bytes.Compare(iter.Key(), it.iters[it.cur].Key()) < 0 {
+			it.cur = i
+		}
+	}
+	return it.cur >= 0
+}
+
+// Error returns any accumulated error of the shard iterators.
+func (it *shardedIterator) Error() error {
+	for i, iter := range it.iters {
+		if err := iter.Error(); err != nil {
+			return fmt.Errorf("shard %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *shardedIterator) Key() []byte {
+	if it.cur < 0 {
+		return nil
+	}
+	return it.iters[it.cur].Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *shardedIterator) Value() []byte {
+	if it.cur < 0 {
+		return nil
+	}
+	return it.iters[it.cur].Value()
+}
+
+// Release releases the iterators of all the shards.
+func (it *shardedIterator) Release() {
+	for _, iter := range it.iters {
+		iter.Release()
+	}
+	it.cur = -1
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/ethdb/walbatch"
+)
+
+// Tests that a sharded database can be created, filled and reopened, with each
+// shard retaining its own data.
+func TestShardedDatabaseReopen(t *testing.T) {
+	for _, engine := range []string{dbPebble, dbLeveldb} {
+		t.Run(engine, func(t *testing.T) {
+			dir := t.TempDir()
+			db, err := OpenSharded(OpenOptions{Type: engine, Directory: dir, Cache: 16, Handles: 64})
+			if err != nil {
+				t.Fatalf("Failed to create sharded database: %v", err)
+			}
+			for i, shard := range db.Shards() {
+				if err := shard.Put([]byte("key"), []byte{byte(i)}); err != nil {
+					t.Fatalf("Failed to write shard %d: %v", i, err)
+				}
+			}
+			if err := db.Close(); err != nil {
+				t.Fatalf("Failed to close sharded database: %v", err)
+			}
+			// Reopen without an explicit engine, the manifest should be used
+			db, err = OpenSharded(OpenOptions{Directory: dir, Cache: 16, Handles: 64, ReadOnly: true})
+			if err != nil {
+				t.Fatalf("Failed to reopen sharded database: %v", err)
+			}
+			defer db.Close()
+
+			if have := db.Manifest().Engine; have != engine {
+				t.Fatalf("Engine mismatch: have %s, want %s", have, engine)
+			}
+			for i, shard := range db.Shards() {
+				blob, err := shard.Get([]byte("key"))
+				if err != nil {
+					t.Fatalf("Failed to read shard %d: %v", i, err)
+				}
+				if !bytes.Equal(blob, []byte{byte(i)}) {
+					t.Fatalf("Shard %d content mismatch: have %x, want %x", i, blob, []byte{byte(i)})
+				}
+			}
+		})
+	}
+}
+
+// failingStore is a key-value store whose batches fail to write.
+type failingStore struct {
+	ethdb.KeyValueStore
+}
+
+func (s failingStore) NewBatch() ethdb.Batch {
+	return failingBatch{s.KeyValueStore.NewBatch()}
+}
+
+type failingBatch struct {
+	ethdb.Batch
+}
+
+func (b failingBatch) Write() error {
+	return errors.New("write failed")
+}
+
+// Tests that a cross-shard batch interrupted after being logged is completed when
+// the sharded database is reopened.
+func TestShardedDatabaseRecovery(t *testing.T) {
+	dir := t.TempDir()
+	db, err := OpenSharded(OpenOptions{Type: dbLeveldb, Directory: dir, Cache: 16, Handles: 64})
+	if err != nil {
+		t.Fatalf("Failed to create sharded database: %v", err)
+	}
+	// Log a batch which only reaches the first shard
+	stores := make([]ethdb.KeyValueStore, ShardCount)
+	for i, shard := range db.Shards() {
+		stores[i] = shard
+	}
+	stores[5] = failingStore{stores[5]}
+
+	wal, err := walbatch.New(stores[0], stores)
+	if err != nil {
+		t.Fatalf("Failed to open write-ahead log: %v", err)
+	}
+	batch := wal.NewBatch()
+	batch.Store(0).Put([]byte("key"), []byte{0})
+	batch.Store(5).Put([]byte("key"), []byte{5})
+	if err := batch.Write(); err == nil {
+		t.Fatal("Batch written to failing shard")
+	}
+	if has, _ := db.Shards()[5].Has([]byte("key")); has {
+		t.Fatal("Failing shard written")
+	}
+	db.Close()
+
+	// Reopen the database and ensure the batch is completed
+	db, err = OpenSharded(OpenOptions{Directory: dir, Cache: 16, Handles: 64})
+	if err != nil {
+		t.Fatalf("Failed to reopen sharded database: %v", err)
+	}
+	defer db.Close()
+
+	for _, i := range []int{0, 5} {
+		if blob, err := db.Shards()[i].Get([]byte("key")); err != nil || !bytes.Equal(blob, []byte{byte(i)}) {
+			t.Fatalf("Shard %d content mismatch: have %x, %v", i, blob, err)
+		}
+	}
+}
+
+// Tests that a sharded database is rejected if opened with a different engine
+// or with an invalid manifest.
+func TestShardedDatabaseManifest(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenSharded(OpenOptions{Directory: dir, ReadOnly: true}); err == nil {
+		t.Fatal("Opened missing sharded database in read-only mode")
+	}
+	db, err := OpenSharded(OpenOptions{Type: dbLeveldb, Directory: dir, Cache: 16, Handles: 64})
+	if err != nil {
+		t.Fatalf("Failed to create sharded database: %v", err)
+	}
+	db.Close()
+
+	if _, err := OpenSharded(OpenOptions{Type: dbPebble, Directory: dir, Cache: 16, Handles: 64}); err == nil {
+		t.Fatal("Opened leveldb sharded database as pebble")
+	}
+	manifest, err := ReadShardManifest(dir)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	manifest.Shards = manifest.Shards[:8]
+	if err := writeShardManifest(dir, manifest); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	if _, err := OpenSharded(OpenOptions{Directory: dir, Cache: 16, Handles: 64}); err == nil || !strings.Contains(err.Error(), "shard count") {
+		t.Fatalf("Opened sharded database with truncated manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ShardManifestName), []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt manifest: %v", err)
+	}
+	if _, err := OpenSharded(OpenOptions{Directory: dir, Cache: 16, Handles: 64}); err == nil {
+		t.Fatal("Opened sharded database with corrupted manifest")
+	}
+}
+
+// Tests that the stats of a sharded database aggregate all the shards.
+func TestShardedDatabaseStats(t *testing.T) {
+	db, err := OpenSharded(OpenOptions{Type: dbLeveldb, Directory: t.TempDir(), Cache: 16, Handles: 64})
+	if err != nil {
+		t.Fatalf("Failed to create sharded database: %v", err)
+	}
+	defer db.Close()
+
+	stats, err := db.Stat("leveldb.stats")
+	if err != nil {
+		t.Fatalf("Failed to retrieve stats: %v", err)
+	}
+	for i := 0; i < ShardCount; i++ {
+		if !strings.Contains(stats, fmt.Sprintf("Shard %02x:", i)) {
+			t.Errorf("Stats of shard %d missing", i)
+		}
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("Failed to compact sharded database: %v", err)
+	}
+}
+
+// Tests that the aggregate view of the shards behaves as a key-value store.
+func TestShardedDatabaseSuite(t *testing.T) {
+	dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+		var shards [ShardCount]ethdb.Database
+		for i := range shards {
+			shards[i] = NewDatabase(memorydb.New())
+		}
+		return &shardedStore{shards: shards}
+	})
+}
+
+// Tests that the aggregate view finds, iterates and deletes the entries of all
+// the shards.
+func TestShardedDatabaseAggregate(t *testing.T) {
+	var db ethdb.Database = NewMemoryShardedDatabase()
+	shards := db.(*ShardedDatabase).Shards()
+
+	for i, shard := range shards {
+		shard.Put([]byte{byte(ShardCount - i)}, []byte{byte(i)})
+		shard.Put([]byte("dup"), []byte{byte(i)})
+	}
+	if blob, err := db.Get([]byte{1}); err != nil || !bytes.Equal(blob, []byte{ShardCount - 1}) {
+		t.Fatalf("Failed to read shard entry: %x, %v", blob, err)
+	}
+	if blob, err := db.Get([]byte("dup")); err != nil || !bytes.Equal(blob, []byte{0}) {
+		t.Fatalf("Duplicate not read from first shard: %x, %v", blob, err)
+	}
+	// Iteration must visit every key once, in order
+	it := db.NewIterator(nil, nil)
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, common.CopyBytes(it.Key()))
+	}
+	it.Release()
+	if len(keys) != ShardCount+1 {
+		t.Fatalf("Iterated key count mismatch: have %d, want %d", len(keys), ShardCount+1)
+	}
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+			t.Fatalf("Keys not ascending at %d: %x, %x", i, keys[i-1], keys[i])
+		}
+	}
+	// Deletions must reach every shard, insertions only the first
+	batch := db.NewBatch()
+	batch.Delete([]byte("dup"))
+	batch.Put([]byte("new"), []byte{1})
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	for i, shard := range shards {
+		if has, _ := shard.Has([]byte("dup")); has {
+			t.Errorf("Shard %d still holds deleted key", i)
+		}
+		if has, _ := shard.Has([]byte("new")); has != (i == 0) {
+			t.Errorf("Shard %d new key presence mismatch: have %v", i, has)
+		}
+	}
+}
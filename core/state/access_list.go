@@ -17,7 +17,11 @@
 package state
 
 import (
+	"bytes"
+	"slices"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type accessList struct {
@@ -71,6 +75,24 @@ func (a *accessList) Copy() *accessList {
 	return cp
 }
 
+// accessTuples converts the access list into its EIP-2930 representation, with
+// the addresses and slots sorted for deterministic output.
+func (al *accessList) accessTuples() types.AccessList {
+	list := make(types.AccessList, 0, len(al.addresses))
+	for addr, idx := range al.addresses {
+		tuple := types.AccessTuple{Address: addr, StorageKeys: []common.Hash{}}
+		if idx >= 0 {
+			for slot := range al.slots[idx] {
+				tuple.StorageKeys = append(tuple.StorageKeys, slot)
+			}
+			slices.SortFunc(tuple.StorageKeys, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+		}
+		list = append(list, tuple)
+	}
+	slices.SortFunc(list, func(a, b types.AccessTuple) int { return bytes.Compare(a.Address[:], b.Address[:]) })
+	return list
+}
+
 // AddAddress adds an address to the access list, and returns 'true' if the operation
 // caused a change (addr was not previously in the list).
 func (al *accessList) AddAddress(address common.Address) bool {
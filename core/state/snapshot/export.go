@@ -0,0 +1,445 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/golang/snappy"
+)
+
+const (
+	// ExportVersion is the version of the snapshot export format.
+	ExportVersion = 1
+
+	// ExportManifestName is the file name of the manifest of an export.
+	ExportManifestName = "manifest.json"
+
+	// DefaultExportChunkSize is the default uncompressed size of an export chunk.
+	DefaultExportChunkSize = 64 * 1024 * 1024
+
+	// exportSlotBatch is the maximum number of storage slots in an export entry.
+	exportSlotBatch = 1024
+)
+
+// ExportManifest describes a flat state snapshot exported into a set of chunk
+// files. Chunks are content addressed: their file name is the hash of their
+// contents, which allows verifying them independently of where they came from.
+type ExportManifest struct {
+	Version  uint64        `json:"version"`
+	Root     common.Hash   `json:"root"`
+	Accounts uint64        `json:"accounts"`
+	Slots    uint64        `json:"slots"`
+	Chunks   []ExportChunk `json:"chunks"`
+}
+
+// ExportChunk describes a single chunk of an export, holding a contiguous range
+// of the accounts along with their storage and code.
+type ExportChunk struct {
+	Hash     common.Hash `json:"hash"`     // Keccak256 hash of the compressed chunk
+	First    common.Hash `json:"first"`    // Hash of the first account in the chunk
+	Last     common.Hash `json:"last"`     // Hash of the last account in the chunk
+	Accounts uint64      `json:"accounts"` // Number of accounts starting in the chunk
+	Slots    uint64      `json:"slots"`    // Number of storage slots in the chunk
+	Size     uint64      `json:"size"`     // Size of the compressed chunk
+}
+
+// ExportChunkName returns the file name of the chunk with the given hash.
+func ExportChunkName(hash common.Hash) string {
+	return fmt.Sprintf("%x.snap", hash)
+}
+
+// exportSlot is a single storage slot in an export chunk.
+type exportSlot struct {
+	Hash  common.Hash
+	Value []byte
+}
+
+// exportEntry is a single record in an export chunk. Chunks are snappy encoded
+// RLP streams of entries: every account entry is followed by the storage entries
+// of the account, which may continue in subsequent chunks.
+type exportEntry struct {
+	Account common.Hash  // Hash of the account the entry belongs to
+	Data    []byte       // Slim account RLP for account entries, empty for storage ones
+	Code    []byte       // Contract code for accounts with code
+	Slots   []exportSlot // Storage slots for storage entries
+}
+
+// exportWriter accumulates export entries into chunks and writes them to disk.
+type exportWriter struct {
+	dir      string
+	size     int
+	manifest *ExportManifest
+
+	buf   bytes.Buffer // Uncompressed content of the chunk being assembled
+	chunk ExportChunk  // Metadata of the chunk being assembled
+}
+
+// add appends an entry to the current chunk, flushing it if it's full.
+func (w *exportWriter) add(entry *exportEntry) error {
+	if w.buf.Len() == 0 {
+		w.chunk.First = entry.Account
+	}
+	w.chunk.Last = entry.Account
+	if len(entry.Data) > 0 {
+		w.chunk.Accounts++
+	}
+	w.chunk.Slots += uint64(len(entry.Slots))
+
+	if err := rlp.Encode(&w.buf, entry); err != nil {
+		return err
+	}
+	if w.buf.Len() >= w.size {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush compresses the current chunk and writes it to disk under its hash.
+func (w *exportWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	blob := snappy.Encode(nil, w.buf.Bytes())
+
+	w.chunk.Hash = crypto.Keccak256Hash(blob)
+	w.chunk.Size = uint64(len(blob))
+	if err := writeFileAtomic(filepath.Join(w.dir, ExportChunkName(w.chunk.Hash)), blob); err != nil {
+		return err
+	}
+	w.manifest.Chunks = append(w.manifest.Chunks, w.chunk)
+	w.manifest.Accounts += w.chunk.Accounts
+	w.manifest.Slots += w.chunk.Slots
+
+	w.buf.Reset()
+	w.chunk = ExportChunk{}
+	return nil
+}
+
+// writeFileAtomic writes a file through a temporary one, so that a crash never
+// leaves a partial file behind.
+func writeFileAtomic(path string, blob []byte) error {
+	if err := os.WriteFile(path+".tmp", blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Export writes the flat account and storage snapshot at the given root into
+// the given directory, as chunks of roughly chunkSize uncompressed bytes and a
+// manifest describing them. Contract codes are read from the given database.
+func Export(snaptree *Tree, root common.Hash, codes ethdb.KeyValueReader, dir string, chunkSize int) (*ExportManifest, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultExportChunkSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	accIt, err := snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return nil, err
+	}
+	defer accIt.Release()
+
+	var (
+		w = &exportWriter{
+			dir:      dir,
+			size:     chunkSize,
+			manifest: &ExportManifest{Version: ExportVersion, Root: root},
+		}
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for accIt.Next() {
+		hash, data := accIt.Hash(), common.CopyBytes(accIt.Account())
+
+		account, err := types.FullAccount(data)
+		if err != nil {
+			return nil, err
+		}
+		entry := &exportEntry{Account: hash, Data: data}
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			if entry.Code = rawdb.ReadCode(codes, codeHash); len(entry.Code) == 0 {
+				return nil, fmt.Errorf("missing code %x of account %x", codeHash, hash)
+			}
+		}
+		if err := w.add(entry); err != nil {
+			return nil, err
+		}
+		if account.Root != types.EmptyRootHash {
+			stIt, err := snaptree.StorageIterator(root, hash, common.Hash{})
+			if err != nil {
+				return nil, err
+			}
+			entry := &exportEntry{Account: hash}
+			for stIt.Next() {
+				entry.Slots = append(entry.Slots, exportSlot{Hash: stIt.Hash(), Value: common.CopyBytes(stIt.Slot())})
+				if len(entry.Slots) == exportSlotBatch {
+					if err := w.add(entry); err != nil {
+						stIt.Release()
+						return nil, err
+					}
+					entry = &exportEntry{Account: hash}
+				}
+			}
+			err = stIt.Error()
+			stIt.Release()
+			if err != nil {
+				return nil, err
+			}
+			if len(entry.Slots) > 0 {
+				if err := w.add(entry); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting snapshot", "at", hash, "accounts", w.manifest.Accounts+w.chunk.Accounts,
+				"chunks", len(w.manifest.Chunks), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return nil, err
+	}
+	if err := w.flush(); err != nil {
+		return nil, err
+	}
+	blob, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, ExportManifestName), blob); err != nil {
+		return nil, err
+	}
+	log.Info("Exported snapshot", "root", root, "accounts", w.manifest.Accounts, "slots", w.manifest.Slots,
+		"chunks", len(w.manifest.Chunks), "elapsed", common.PrettyDuration(time.Since(start)))
+	return w.manifest, nil
+}
+
+// ReadExportManifest reads the manifest of the export in the given directory.
+func ReadExportManifest(dir string) (*ExportManifest, error) {
+	blob, err := os.ReadFile(filepath.Join(dir, ExportManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(ExportManifest)
+	if err := json.Unmarshal(blob, manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+// importer rebuilds the snapshot and the tries from the entries of an export.
+type importer struct {
+	batch  ethdb.Batch
+	scheme string
+
+	accTrie *trie.StackTrie // Account trie being rebuilt
+
+	account  common.Hash     // Hash of the account being imported
+	started  bool            // Whether any account was imported yet
+	root     common.Hash     // Storage root of the account being imported
+	stTrie   *trie.StackTrie // Storage trie of the account being imported
+	lastSlot *common.Hash    // Hash of the last imported slot of the account
+	accounts uint64          // Number of accounts imported
+	slots    uint64          // Number of slots imported
+}
+
+// newStackTrie creates a stack trie writing its nodes into the import batch.
+func (imp *importer) newStackTrie(owner common.Hash) *trie.StackTrie {
+	return trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(imp.batch, owner, path, hash, blob, imp.scheme)
+	}))
+}
+
+// finishAccount checks the storage root of the account being imported.
+func (imp *importer) finishAccount() error {
+	if !imp.started {
+		return nil
+	}
+	if have := imp.stTrie.Commit(); have != imp.root {
+		return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", imp.account, have, imp.root)
+	}
+	return nil
+}
+
+// process imports a single export entry.
+func (imp *importer) process(entry *exportEntry) error {
+	// Storage entries extend the account being imported
+	if len(entry.Data) == 0 {
+		if !imp.started || entry.Account != imp.account {
+			return fmt.Errorf("storage of account %x out of order", entry.Account)
+		}
+		for _, slot := range entry.Slots {
+			if imp.lastSlot != nil && bytes.Compare(slot.Hash[:], imp.lastSlot[:]) <= 0 {
+				return fmt.Errorf("slot %x of account %x out of order", slot.Hash, entry.Account)
+			}
+			hash := slot.Hash
+			imp.lastSlot = &hash
+
+			rawdb.WriteStorageSnapshot(imp.batch, entry.Account, slot.Hash, slot.Value)
+			if err := imp.stTrie.Update(slot.Hash[:], slot.Value); err != nil {
+				return err
+			}
+		}
+		imp.slots += uint64(len(entry.Slots))
+		return nil
+	}
+	// Account entry, wrap up the previous account and start on the new one
+	if imp.started && bytes.Compare(entry.Account[:], imp.account[:]) <= 0 {
+		return fmt.Errorf("account %x out of order", entry.Account)
+	}
+	if err := imp.finishAccount(); err != nil {
+		return err
+	}
+	account, err := types.FullAccount(entry.Data)
+	if err != nil {
+		return fmt.Errorf("invalid account %x: %v", entry.Account, err)
+	}
+	if len(entry.Code) > 0 {
+		if hash := crypto.Keccak256Hash(entry.Code); !bytes.Equal(hash[:], account.CodeHash) {
+			return fmt.Errorf("code hash mismatch for account %x: have %x, want %x", entry.Account, hash, account.CodeHash)
+		}
+		rawdb.WriteCode(imp.batch, common.BytesToHash(account.CodeHash), entry.Code)
+	} else if !bytes.Equal(account.CodeHash, types.EmptyCodeHash[:]) {
+		return fmt.Errorf("missing code for account %x", entry.Account)
+	}
+	rawdb.WriteAccountSnapshot(imp.batch, entry.Account, entry.Data)
+
+	full, err := types.FullAccountRLP(entry.Data)
+	if err != nil {
+		return err
+	}
+	if err := imp.accTrie.Update(entry.Account[:], full); err != nil {
+		return err
+	}
+	imp.account, imp.root, imp.started, imp.lastSlot = entry.Account, account.Root, true, nil
+	imp.stTrie = imp.newStackTrie(entry.Account)
+	imp.accounts++
+	return nil
+}
+
+// Import reads the export in the given directory, verifying every chunk against
+// the manifest, and writes the flat snapshot, the contract codes and the trie
+// nodes (in the given state scheme) into the database. The tries are rebuilt
+// with stack tries and all roots are checked along the way, the snapshot is only
+// marked complete if the account trie root matches the manifest.
+//
+// A failed import leaves partial data behind, it should be run on an empty
+// database.
+func Import(dir string, db ethdb.KeyValueStore, scheme string) (*ExportManifest, error) {
+	manifest, err := ReadExportManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	imp := &importer{batch: db.NewBatch(), scheme: scheme}
+	imp.accTrie = imp.newStackTrie(common.Hash{})
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for i, chunk := range manifest.Chunks {
+		blob, err := os.ReadFile(filepath.Join(dir, ExportChunkName(chunk.Hash)))
+		if err != nil {
+			return nil, err
+		}
+		if hash := crypto.Keccak256Hash(blob); hash != chunk.Hash {
+			return nil, fmt.Errorf("chunk %d hash mismatch: have %x, want %x", i, hash, chunk.Hash)
+		}
+		data, err := snappy.Decode(nil, blob)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %v", i, err)
+		}
+		var (
+			stream      = rlp.NewStream(bytes.NewReader(data), uint64(len(data)))
+			accounts    = imp.accounts
+			slots       = imp.slots
+			first, last common.Hash
+			entries     int
+		)
+		for {
+			entry := new(exportEntry)
+			if err := stream.Decode(entry); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("chunk %d: %v", i, err)
+			}
+			if entries == 0 {
+				first = entry.Account
+			}
+			last = entry.Account
+			entries++
+
+			if err := imp.process(entry); err != nil {
+				return nil, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			if imp.batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := imp.batch.Write(); err != nil {
+					return nil, err
+				}
+				imp.batch.Reset()
+			}
+		}
+		if first != chunk.First || last != chunk.Last || imp.accounts-accounts != chunk.Accounts || imp.slots-slots != chunk.Slots {
+			return nil, fmt.Errorf("chunk %d content mismatches manifest", i)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing snapshot", "chunks", i+1, "total", len(manifest.Chunks), "accounts", imp.accounts,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := imp.finishAccount(); err != nil {
+		return nil, err
+	}
+	if imp.accounts != manifest.Accounts || imp.slots != manifest.Slots {
+		return nil, fmt.Errorf("import count mismatch: have %d accounts, %d slots, want %d, %d", imp.accounts, imp.slots, manifest.Accounts, manifest.Slots)
+	}
+	if root := imp.accTrie.Commit(); root != manifest.Root {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, manifest.Root)
+	}
+	// All verified, mark the snapshot complete at the imported root
+	rawdb.WriteSnapshotRoot(imp.batch, manifest.Root)
+	journalProgress(imp.batch, nil, nil)
+	if err := imp.batch.Write(); err != nil {
+		return nil, err
+	}
+	log.Info("Imported snapshot", "root", manifest.Root, "accounts", imp.accounts, "slots", imp.slots,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+)
+
+// Tests that a snapshot exported into chunks can be imported into an empty
+// database, rebuilding the flat state and the tries.
+func TestExportImport(t *testing.T) {
+	testExportImport(t, rawdb.HashScheme)
+	testExportImport(t, rawdb.PathScheme)
+}
+
+func testExportImport(t *testing.T, scheme string) {
+	helper := newHelper(scheme)
+
+	// Plain account, contract with code and contract with lots of storage
+	helper.addAccount("acc-1", &types.StateAccount{Balance: big.NewInt(1), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+
+	code := []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	rawdb.WriteCode(helper.diskdb, crypto.Keccak256Hash(code), code)
+	helper.addAccount("acc-2", &types.StateAccount{Balance: big.NewInt(2), Root: types.EmptyRootHash, CodeHash: crypto.Keccak256(code)})
+
+	var keys, vals []string
+	for i := 0; i < 2500; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+		vals = append(vals, fmt.Sprintf("val-%d", i))
+	}
+	stRoot := helper.makeStorageTrie(hashData([]byte("acc-3")), keys, vals, true)
+	helper.addAccount("acc-3", &types.StateAccount{Balance: big.NewInt(3), Root: stRoot, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addSnapStorage("acc-3", keys, vals)
+
+	root, snap := helper.CommitAndGenerate()
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatal("snapshot generation failed")
+	}
+	defer func() {
+		stop := make(chan *generatorStats)
+		snap.genAbort <- stop
+		<-stop
+	}()
+	snaps := &Tree{layers: map[common.Hash]snapshot{root: snap}}
+
+	// Export in small chunks so the storage spans several of them
+	dir := t.TempDir()
+	manifest, err := Export(snaps, root, helper.diskdb, dir, 16*1024)
+	if err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+	if manifest.Accounts != 3 || manifest.Slots != 2500 || len(manifest.Chunks) < 2 {
+		t.Fatalf("manifest mismatch: %d accounts, %d slots, %d chunks", manifest.Accounts, manifest.Slots, len(manifest.Chunks))
+	}
+	// Import into an empty database and check the tries are complete
+	db := rawdb.NewMemoryDatabase()
+	if _, err := Import(dir, db, scheme); err != nil {
+		t.Fatalf("failed to import snapshot: %v", err)
+	}
+	if have := rawdb.ReadSnapshotRoot(db); have != root {
+		t.Fatalf("snapshot root mismatch: have %x, want %x", have, root)
+	}
+	if have := rawdb.ReadCode(db, crypto.Keccak256Hash(code)); string(have) != string(code) {
+		t.Fatalf("code mismatch: have %x, want %x", have, code)
+	}
+	config := &trie.Config{HashDB: &hashdb.Config{}}
+	if scheme == rawdb.PathScheme {
+		config = &trie.Config{PathDB: &pathdb.Config{}}
+	}
+	triedb := trie.NewDatabase(db, config)
+	accTrie, err := trie.NewStateTrie(trie.StateTrieID(root), triedb)
+	if err != nil {
+		t.Fatalf("failed to open account trie: %v", err)
+	}
+	if blob := accTrie.MustGet([]byte("acc-3")); len(blob) == 0 {
+		t.Fatal("account missing from imported trie")
+	}
+	stTrie, err := trie.NewStateTrie(trie.StorageTrieID(root, hashData([]byte("acc-3")), stRoot), triedb)
+	if err != nil {
+		t.Fatalf("failed to open storage trie: %v", err)
+	}
+	if have := stTrie.MustGet([]byte("key-1234")); string(have) != "val-1234" {
+		t.Fatalf("storage mismatch: have %q, want %q", have, "val-1234")
+	}
+	// Tamper with a chunk and ensure the import is rejected
+	path := filepath.Join(dir, ExportChunkName(manifest.Chunks[1].Hash))
+	blob, _ := os.ReadFile(path)
+	blob[len(blob)/2] ^= 0xff
+	os.WriteFile(path, blob, 0644)
+
+	if _, err := Import(dir, rawdb.NewMemoryDatabase(), scheme); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Fatalf("tampered import error mismatch: have %v", err)
+	}
+}
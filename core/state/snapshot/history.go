@@ -0,0 +1,562 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// historyCacheSize is the number of decoded snapshot histories to keep around
+// for serving historical state reads.
+const historyCacheSize = 128
+
+// errHistoryUnavailable is returned if a historical state is requested which
+// is not retained in the snapshot history (anymore).
+var errHistoryUnavailable = errors.New("historical state unavailable")
+
+// historyRecord is the reverse diff of a single block's state transition, as
+// stored in the snapshot history freezer. It holds the values the accounts and
+// storage slots modified by the block had in the parent state, nil meaning the
+// entry did not exist.
+//
+// For accounts destructed by the block, all the storage slots the account had
+// in the parent state are listed, any slot missing being empty.
+type historyRecord struct {
+	Root      common.Hash      // State root after the transition
+	Parent    common.Hash      // State root before the transition
+	Destructs []common.Hash    // Accounts whose storage was wiped by the transition
+	Accounts  []journalAccount // Parent values of the modified accounts
+	Storage   []journalStorage // Parent values of the modified storage slots
+}
+
+// historyDiff is a decoded snapshot history, indexed for direct retrieval.
+type historyDiff struct {
+	root      common.Hash
+	parent    common.Hash
+	destructs map[common.Hash]struct{}
+	accounts  map[common.Hash][]byte
+	storage   map[common.Hash]map[common.Hash][]byte
+}
+
+// newHistoryRecord assembles the reverse diff of the state transition done by a
+// single-block diff layer, reading the original values from its parent.
+func newHistoryRecord(layer *diffLayer) (*historyRecord, error) {
+	var (
+		parent = layer.parent
+		record = &historyRecord{
+			Root:   layer.root,
+			Parent: parent.Root(),
+		}
+		accounts = make(map[common.Hash]struct{})
+		storage  = make(map[common.Hash]map[common.Hash][]byte)
+	)
+	layer.lock.RLock()
+	defer layer.lock.RUnlock()
+
+	for hash := range layer.destructSet {
+		accounts[hash] = struct{}{}
+		record.Destructs = append(record.Destructs, hash)
+
+		// Collect all the slots the account had before getting destructed
+		var it StorageIterator
+		switch parent := parent.(type) {
+		case *diffLayer:
+			it = parent.newBinaryStorageIterator(hash)
+		case *diskLayer:
+			it, _ = parent.StorageIterator(hash, common.Hash{})
+		default:
+			panic(fmt.Sprintf("unknown data layer: %T", parent))
+		}
+		slots := make(map[common.Hash][]byte)
+		for it.Next() {
+			if slot := it.Slot(); len(slot) > 0 {
+				slots[it.Hash()] = common.CopyBytes(slot)
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return nil, err
+		}
+		storage[hash] = slots
+	}
+	for hash := range layer.accountData {
+		accounts[hash] = struct{}{}
+	}
+	for hash := range accounts {
+		blob, err := parent.AccountRLP(hash)
+		if err != nil {
+			return nil, err
+		}
+		record.Accounts = append(record.Accounts, journalAccount{Hash: hash, Blob: common.CopyBytes(blob)})
+	}
+	for accountHash, slots := range layer.storageData {
+		if _, ok := storage[accountHash]; !ok {
+			storage[accountHash] = make(map[common.Hash][]byte)
+		}
+		for storageHash := range slots {
+			if _, ok := storage[accountHash][storageHash]; ok {
+				continue
+			}
+			blob, err := parent.Storage(accountHash, storageHash)
+			if err != nil {
+				return nil, err
+			}
+			storage[accountHash][storageHash] = common.CopyBytes(blob)
+		}
+	}
+	for accountHash, slots := range storage {
+		entry := journalStorage{Hash: accountHash}
+		for storageHash, blob := range slots {
+			entry.Keys = append(entry.Keys, storageHash)
+			entry.Vals = append(entry.Vals, blob)
+		}
+		record.Storage = append(record.Storage, entry)
+	}
+	// Sort everything to keep the encoding deterministic
+	sort.Slice(record.Destructs, func(i, j int) bool {
+		return record.Destructs[i].Cmp(record.Destructs[j]) < 0
+	})
+	sort.Slice(record.Accounts, func(i, j int) bool {
+		return record.Accounts[i].Hash.Cmp(record.Accounts[j].Hash) < 0
+	})
+	sort.Slice(record.Storage, func(i, j int) bool {
+		return record.Storage[i].Hash.Cmp(record.Storage[j].Hash) < 0
+	})
+	for _, entry := range record.Storage {
+		sort.Sort(storageEntry(entry))
+	}
+	return record, nil
+}
+
+// storageEntry implements sort.Interface to order the slots of a storage
+// history entry by hash.
+type storageEntry journalStorage
+
+func (e storageEntry) Len() int           { return len(e.Keys) }
+func (e storageEntry) Less(i, j int) bool { return e.Keys[i].Cmp(e.Keys[j]) < 0 }
+func (e storageEntry) Swap(i, j int) {
+	e.Keys[i], e.Keys[j] = e.Keys[j], e.Keys[i]
+	e.Vals[i], e.Vals[j] = e.Vals[j], e.Vals[i]
+}
+
+// decodeHistory decodes a snapshot history from its freezer representation.
+func decodeHistory(blob []byte) (*historyDiff, error) {
+	var record historyRecord
+	if err := rlp.DecodeBytes(blob, &record); err != nil {
+		return nil, err
+	}
+	diff := &historyDiff{
+		root:      record.Root,
+		parent:    record.Parent,
+		destructs: make(map[common.Hash]struct{}, len(record.Destructs)),
+		accounts:  make(map[common.Hash][]byte, len(record.Accounts)),
+		storage:   make(map[common.Hash]map[common.Hash][]byte, len(record.Storage)),
+	}
+	for _, hash := range record.Destructs {
+		diff.destructs[hash] = struct{}{}
+	}
+	for _, entry := range record.Accounts {
+		diff.accounts[entry.Hash] = entry.Blob
+	}
+	for _, entry := range record.Storage {
+		if len(entry.Keys) != len(entry.Vals) {
+			return nil, errors.New("invalid storage history entry")
+		}
+		slots := make(map[common.Hash][]byte, len(entry.Keys))
+		for i, key := range entry.Keys {
+			slots[key] = entry.Vals[i]
+		}
+		diff.storage[entry.Hash] = slots
+	}
+	return diff, nil
+}
+
+// snapHistory maintains the reverse diffs of the state transitions flattened
+// out of the snapshot diff layers, along with an index of the modifications
+// they contain, allowing historical states to be reconstructed on top of the
+// bottom-most snapshot layer.
+//
+// The histories are numbered sequentially by their position in the freezer,
+// the head one always being the transition into the bottom-most layer. All the
+// methods are expected to be called with the snapshot tree lock held, write
+// lock for the mutating ones.
+type snapHistory struct {
+	diskdb  ethdb.KeyValueStore
+	freezer *rawdb.ResettableFreezer
+	limit   uint64 // Number of histories to retain, zero meaning all
+
+	headRoot common.Hash                      // State root of the head history, zero if none
+	diffs    *lru.Cache[uint64, *historyDiff] // Cache of recently accessed histories
+}
+
+// newSnapHistory opens the snapshot history freezer in the given ancient store
+// directory, discarding any histories not covered by the index.
+func newSnapHistory(diskdb ethdb.KeyValueStore, ancient string, limit uint64) (*snapHistory, error) {
+	freezer, err := rawdb.NewSnapshotFreezer(ancient, false)
+	if err != nil {
+		return nil, err
+	}
+	h := &snapHistory{
+		diskdb:  diskdb,
+		freezer: freezer,
+		limit:   limit,
+		diffs:   lru.NewCache[uint64, *historyDiff](historyCacheSize),
+	}
+	items, err := freezer.Ancients()
+	if err != nil {
+		freezer.Close()
+		return nil, err
+	}
+	switch indexed := rawdb.ReadSnapshotHistoryIndexed(diskdb); {
+	case items > indexed:
+		// Crashed after storing a history but before indexing it, drop it
+		if _, err := freezer.TruncateHead(indexed); err != nil {
+			freezer.Close()
+			return nil, err
+		}
+	case items < indexed:
+		// Freezer lost some histories, the index cannot be trusted anymore
+		log.Warn("Snapshot history index ahead of freezer, resetting", "items", items, "indexed", indexed)
+		if err := h.reset(); err != nil {
+			freezer.Close()
+			return nil, err
+		}
+	}
+	if id, ok := h.head(); ok {
+		diff, err := h.read(id)
+		if err != nil {
+			freezer.Close()
+			return nil, err
+		}
+		h.headRoot = diff.root
+	}
+	return h, nil
+}
+
+// head returns the id of the latest snapshot history, if any is retained.
+func (h *snapHistory) head() (uint64, bool) {
+	items, err := h.freezer.Ancients()
+	if err != nil {
+		return 0, false
+	}
+	tail, err := h.freezer.Tail()
+	if err != nil || items <= tail {
+		return 0, false
+	}
+	return items - 1, true
+}
+
+// read retrieves and decodes the snapshot history with the given id.
+func (h *snapHistory) read(id uint64) (*historyDiff, error) {
+	if diff, ok := h.diffs.Get(id); ok {
+		return diff, nil
+	}
+	blob := rawdb.ReadSnapshotHistory(h.freezer, id)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("snapshot history %d missing", id)
+	}
+	diff, err := decodeHistory(blob)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot history %d corrupted: %v", id, err)
+	}
+	h.diffs.Add(id, diff)
+	return diff, nil
+}
+
+// retain stores the reverse diff of the given single-block diff layer, which is
+// about to be flattened into the bottom of the snapshot tree. If the layer does
+// not continue the retained histories, these are discarded first.
+func (h *snapHistory) retain(layer *diffLayer) error {
+	if h.headRoot == layer.root {
+		return nil // Already retained, e.g. the accumulator after a restart
+	}
+	if h.headRoot != (common.Hash{}) && h.headRoot != layer.parent.Root() {
+		log.Warn("Snapshot history discontinued, resetting", "head", h.headRoot, "parent", layer.parent.Root())
+		if err := h.reset(); err != nil {
+			return err
+		}
+	}
+	start := time.Now()
+	record, err := newHistoryRecord(layer)
+	if err != nil {
+		return err
+	}
+	blob, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return err
+	}
+	id, err := h.freezer.Ancients()
+	if err != nil {
+		return err
+	}
+	if err := rawdb.WriteSnapshotHistory(h.freezer, id, blob); err != nil {
+		return err
+	}
+	// Index all the modifications of the history, marking it retained
+	batch := h.diskdb.NewBatch()
+	rawdb.WriteSnapshotHistoryID(batch, record.Root, id)
+	for _, hash := range record.Destructs {
+		rawdb.WriteSnapshotHistoryDestructIndex(batch, hash, id)
+	}
+	for _, entry := range record.Accounts {
+		rawdb.WriteSnapshotHistoryAccountIndex(batch, entry.Hash, id)
+	}
+	for _, entry := range record.Storage {
+		for _, key := range entry.Keys {
+			rawdb.WriteSnapshotHistoryStorageIndex(batch, entry.Hash, key, id)
+		}
+	}
+	rawdb.WriteSnapshotHistoryIndexed(batch, id+1)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	h.headRoot = record.Root
+
+	snapshotHistoryWriteTimer.UpdateSince(start)
+	snapshotHistorySizeMeter.Mark(int64(len(blob)))
+
+	// Prune the histories beyond the retention limit
+	if h.limit == 0 || id+1 <= h.limit {
+		return nil
+	}
+	return h.truncateTail(id + 1 - h.limit)
+}
+
+// unindex removes the index entries of the given snapshot history.
+func (h *snapHistory) unindex(batch ethdb.KeyValueWriter, id uint64) error {
+	diff, err := h.read(id)
+	if err != nil {
+		return err
+	}
+	if stored := rawdb.ReadSnapshotHistoryID(h.diskdb, diff.root); stored != nil && *stored == id {
+		rawdb.DeleteSnapshotHistoryID(batch, diff.root)
+	}
+	for hash := range diff.destructs {
+		rawdb.DeleteSnapshotHistoryDestructIndex(batch, hash, id)
+	}
+	for hash := range diff.accounts {
+		rawdb.DeleteSnapshotHistoryAccountIndex(batch, hash, id)
+	}
+	for accountHash, slots := range diff.storage {
+		for storageHash := range slots {
+			rawdb.DeleteSnapshotHistoryStorageIndex(batch, accountHash, storageHash, id)
+		}
+	}
+	h.diffs.Remove(id)
+	return nil
+}
+
+// truncateTail discards all the snapshot histories below the given id.
+func (h *snapHistory) truncateTail(tail uint64) error {
+	oldTail, err := h.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	batch := h.diskdb.NewBatch()
+	for id := oldTail; id < tail; id++ {
+		if err := h.unindex(batch, id); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	_, err = h.freezer.TruncateTail(tail)
+	return err
+}
+
+// truncateHead discards all the snapshot histories from the given id onwards.
+func (h *snapHistory) truncateHead(items uint64) error {
+	head, ok := h.head()
+	if !ok || head < items {
+		return nil
+	}
+	tail, err := h.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	batch := h.diskdb.NewBatch()
+	for id := head; id >= items && id >= tail; id-- {
+		if err := h.unindex(batch, id); err != nil {
+			return err
+		}
+		if id == 0 {
+			break
+		}
+	}
+	rawdb.WriteSnapshotHistoryIndexed(batch, items)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if _, err := h.freezer.TruncateHead(items); err != nil {
+		return err
+	}
+	h.headRoot = common.Hash{}
+	if id, ok := h.head(); ok {
+		diff, err := h.read(id)
+		if err != nil {
+			return err
+		}
+		h.headRoot = diff.root
+	}
+	return nil
+}
+
+// align discards the snapshot histories above the bottom-most snapshot layer,
+// identified by any of the given roots, which happens if the snapshot layers
+// were lost without being journalled.
+func (h *snapHistory) align(roots ...common.Hash) error {
+	for h.headRoot != (common.Hash{}) {
+		for _, root := range roots {
+			if h.headRoot == root {
+				return nil
+			}
+		}
+		head, _ := h.head()
+		if err := h.truncateHead(head); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reset discards all the snapshot histories.
+func (h *snapHistory) reset() error {
+	if err := rawdb.DeleteSnapshotHistoryIndex(h.diskdb); err != nil {
+		return err
+	}
+	if err := h.freezer.Reset(); err != nil {
+		return err
+	}
+	h.headRoot = common.Hash{}
+	h.diffs.Purge()
+	return nil
+}
+
+// historicLayer is a read-only view of a historical state retained in the
+// snapshot history. Its reads resolve the earliest reverse diff after the state
+// touching the requested entry, falling back to the bottom-most snapshot layer
+// if it was not modified since.
+type historicLayer struct {
+	tree *Tree
+	root common.Hash
+}
+
+// Root returns the root hash of the historical state.
+func (hl *historicLayer) Root() common.Hash {
+	return hl.root
+}
+
+// resolve returns the history id of the state and the id of the head history
+// along with the layer it transitions into.
+func (hl *historicLayer) resolve() (uint64, uint64, snapshot, error) {
+	h := hl.tree.history
+	if h == nil {
+		return 0, 0, nil, errHistoryUnavailable
+	}
+	id := rawdb.ReadSnapshotHistoryID(h.diskdb, hl.root)
+	if id == nil {
+		return 0, 0, nil, errHistoryUnavailable
+	}
+	head, ok := h.head()
+	if !ok || head < *id {
+		return 0, 0, nil, errHistoryUnavailable
+	}
+	base := hl.tree.layers[h.headRoot]
+	if base == nil {
+		return 0, 0, nil, errHistoryUnavailable
+	}
+	return *id, head, base, nil
+}
+
+// Account directly retrieves the account associated with a particular hash in
+// the snapshot slim data format.
+func (hl *historicLayer) Account(hash common.Hash) (*types.SlimAccount, error) {
+	data, err := hl.AccountRLP(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 { // can be both nil and []byte{}
+		return nil, nil
+	}
+	account := new(types.SlimAccount)
+	if err := rlp.DecodeBytes(data, account); err != nil {
+		panic(err)
+	}
+	return account, nil
+}
+
+// AccountRLP directly retrieves the account RLP associated with a particular
+// hash in the snapshot slim data format.
+func (hl *historicLayer) AccountRLP(hash common.Hash) ([]byte, error) {
+	hl.tree.lock.RLock()
+	defer hl.tree.lock.RUnlock()
+
+	id, head, base, err := hl.resolve()
+	if err != nil {
+		return nil, err
+	}
+	snapshotHistoryAccountReadMeter.Mark(1)
+	if next, ok := rawdb.SeekSnapshotHistoryAccount(hl.tree.diskdb, hash, id+1); ok && next <= head {
+		diff, err := hl.tree.history.read(next)
+		if err != nil {
+			return nil, err
+		}
+		return diff.accounts[hash], nil
+	}
+	return base.AccountRLP(hash)
+}
+
+// Storage directly retrieves the storage data associated with a particular hash,
+// within a particular account.
+func (hl *historicLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	hl.tree.lock.RLock()
+	defer hl.tree.lock.RUnlock()
+
+	id, head, base, err := hl.resolve()
+	if err != nil {
+		return nil, err
+	}
+	snapshotHistoryStorageReadMeter.Mark(1)
+
+	// The slot was either last modified by a history, or wiped along with its
+	// account (the history then listing all the pre-existing slots).
+	next, ok := rawdb.SeekSnapshotHistoryStorage(hl.tree.diskdb, accountHash, storageHash, id+1)
+	if wiped, destructed := rawdb.SeekSnapshotHistoryDestruct(hl.tree.diskdb, accountHash, id+1); destructed && (!ok || wiped < next) {
+		next, ok = wiped, true
+	}
+	if ok && next <= head {
+		diff, err := hl.tree.history.read(next)
+		if err != nil {
+			return nil, err
+		}
+		return diff.storage[accountHash][storageHash], nil
+	}
+	return base.Storage(accountHash, storageHash)
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// historyTestState is a flat state model to verify historical reads against.
+type historyTestState struct {
+	accounts map[common.Hash][]byte
+	storage  map[common.Hash]map[common.Hash][]byte
+}
+
+func (s *historyTestState) copy() *historyTestState {
+	cpy := &historyTestState{
+		accounts: make(map[common.Hash][]byte),
+		storage:  make(map[common.Hash]map[common.Hash][]byte),
+	}
+	for hash, blob := range s.accounts {
+		cpy.accounts[hash] = blob
+	}
+	for hash, slots := range s.storage {
+		cpy.storage[hash] = make(map[common.Hash][]byte)
+		for key, blob := range slots {
+			cpy.storage[hash][key] = blob
+		}
+	}
+	return cpy
+}
+
+// historyTester drives a snapshot tree with history retention through random
+// state transitions, tracking the expected state of every block.
+type historyTester struct {
+	diskdb ethdb.KeyValueStore
+	dir    string
+	tree   *Tree
+	rand   *rand.Rand
+
+	accounts []common.Hash
+	slots    []common.Hash
+	roots    []common.Hash
+	states   map[common.Hash]*historyTestState
+}
+
+func newHistoryTester(t *testing.T, limit uint64) *historyTester {
+	tester := &historyTester{
+		diskdb: rawdb.NewMemoryDatabase(),
+		dir:    t.TempDir(),
+		rand:   rand.New(rand.NewSource(1)),
+		states: make(map[common.Hash]*historyTestState),
+	}
+	for i := 0; i < 8; i++ {
+		tester.accounts = append(tester.accounts, common.BigToHash(big.NewInt(int64(0x100+i))))
+	}
+	for i := 0; i < 6; i++ {
+		tester.slots = append(tester.slots, common.BigToHash(big.NewInt(int64(0x200+i))))
+	}
+	root := common.HexToHash("0x01")
+	rawdb.WriteSnapshotRoot(tester.diskdb, root)
+
+	tester.roots = append(tester.roots, root)
+	tester.states[root] = &historyTestState{
+		accounts: make(map[common.Hash][]byte),
+		storage:  make(map[common.Hash]map[common.Hash][]byte),
+	}
+	tester.tree = tester.open(t, root, limit)
+	return tester
+}
+
+// open creates a snapshot tree on top of the persisted disk layer.
+func (tester *historyTester) open(t *testing.T, root common.Hash, limit uint64) *Tree {
+	base := &diskLayer{
+		diskdb: tester.diskdb,
+		root:   root,
+		cache:  fastcache.New(500 * 1024),
+	}
+	tree := &Tree{
+		config: Config{History: limit, HistoryDir: tester.dir},
+		diskdb: tester.diskdb,
+		layers: map[common.Hash]snapshot{base.root: base},
+	}
+	if err := tree.openHistory(); err != nil {
+		t.Fatalf("Failed to open snapshot history: %v", err)
+	}
+	return tree
+}
+
+// value returns a random non-empty blob.
+func (tester *historyTester) value() []byte {
+	blob := make([]byte, 1+tester.rand.Intn(8))
+	tester.rand.Read(blob)
+	return blob
+}
+
+// commit applies a random state transition on top of the last block, capping
+// the tree to the given number of diff layers.
+func (tester *historyTester) commit(t *testing.T, layers int) {
+	var (
+		parent    = tester.roots[len(tester.roots)-1]
+		root      = common.BigToHash(big.NewInt(int64(len(tester.roots) + 1)))
+		state     = tester.states[parent].copy()
+		destructs = make(map[common.Hash]struct{})
+		accounts  = make(map[common.Hash][]byte)
+		storage   = make(map[common.Hash]map[common.Hash][]byte)
+	)
+	if tester.rand.Intn(4) == 0 {
+		hash := tester.accounts[tester.rand.Intn(len(tester.accounts))]
+		destructs[hash] = struct{}{}
+		delete(state.accounts, hash)
+		delete(state.storage, hash)
+	}
+	for i := 0; i < 3; i++ {
+		hash := tester.accounts[tester.rand.Intn(len(tester.accounts))]
+		accounts[hash] = tester.value()
+		state.accounts[hash] = accounts[hash]
+
+		if storage[hash] == nil {
+			storage[hash] = make(map[common.Hash][]byte)
+		}
+		if state.storage[hash] == nil {
+			state.storage[hash] = make(map[common.Hash][]byte)
+		}
+		for j := 0; j < 2; j++ {
+			key := tester.slots[tester.rand.Intn(len(tester.slots))]
+			if tester.rand.Intn(5) == 0 {
+				storage[hash][key] = nil
+				delete(state.storage[hash], key)
+			} else {
+				storage[hash][key] = tester.value()
+				state.storage[hash][key] = storage[hash][key]
+			}
+		}
+	}
+	if err := tester.tree.Update(root, parent, destructs, accounts, storage); err != nil {
+		t.Fatalf("Failed to update snapshot tree: %v", err)
+	}
+	if err := tester.tree.Cap(root, layers); err != nil {
+		t.Fatalf("Failed to cap snapshot tree: %v", err)
+	}
+	tester.roots = append(tester.roots, root)
+	tester.states[root] = state
+}
+
+// verify checks that the historical reader of the given root reproduces the
+// state of the associated block.
+func (tester *historyTester) verify(t *testing.T, root common.Hash) {
+	t.Helper()
+
+	reader, err := tester.tree.StateReader(root)
+	if err != nil {
+		t.Fatalf("Failed to open state reader of %x: %v", root, err)
+	}
+	state := tester.states[root]
+	for _, hash := range tester.accounts {
+		blob, err := reader.AccountRLP(hash)
+		if err != nil {
+			t.Fatalf("Failed to read account %x at %x: %v", hash, root, err)
+		}
+		if !bytes.Equal(blob, state.accounts[hash]) {
+			t.Errorf("Account %x at %x mismatch: have %x, want %x", hash, root, blob, state.accounts[hash])
+		}
+		for _, key := range tester.slots {
+			blob, err := reader.Storage(hash, key)
+			if err != nil {
+				t.Fatalf("Failed to read slot %x/%x at %x: %v", hash, key, root, err)
+			}
+			if want := state.storage[hash][key]; !bytes.Equal(blob, want) {
+				t.Errorf("Slot %x/%x at %x mismatch: have %x, want %x", hash, key, root, blob, want)
+			}
+		}
+	}
+}
+
+// Tests that the states of all the blocks flattened out of the snapshot diff
+// layers can be reconstructed from the retained snapshot history, both from an
+// in-memory accumulator and from the disk layer.
+func TestSnapshotHistory(t *testing.T) {
+	tester := newHistoryTester(t, 1024)
+	for i := 0; i < 48; i++ {
+		tester.commit(t, 4)
+	}
+	for _, root := range tester.roots[1:] {
+		tester.verify(t, root)
+	}
+	// Persist everything into the disk layer and verify again
+	tester.tree.Cap(tester.roots[len(tester.roots)-1], 0)
+	for _, root := range tester.roots[1:] {
+		tester.verify(t, root)
+	}
+	// Reopen the tree and ensure the histories survive
+	tester.tree.Release()
+	tester.tree = tester.open(t, tester.roots[len(tester.roots)-1], 1024)
+	for _, root := range tester.roots[1:] {
+		tester.verify(t, root)
+	}
+	// Continue on top of the persisted histories
+	for i := 0; i < 16; i++ {
+		tester.commit(t, 4)
+	}
+	for _, root := range tester.roots[1:] {
+		tester.verify(t, root)
+	}
+	tester.tree.Release()
+}
+
+// Tests that the snapshot histories beyond the retention limit are pruned.
+func TestSnapshotHistoryPruning(t *testing.T) {
+	tester := newHistoryTester(t, 16)
+	for i := 0; i < 64; i++ {
+		tester.commit(t, 4)
+	}
+	defer tester.tree.Release()
+
+	// The last 4 blocks are live layers, the 16 before retained histories
+	live := len(tester.roots) - 4
+	for _, root := range tester.roots[live-16:] {
+		tester.verify(t, root)
+	}
+	for _, root := range tester.roots[1 : live-16] {
+		if _, err := tester.tree.StateReader(root); !errors.Is(err, errHistoryUnavailable) {
+			t.Errorf("Pruned state %x: error mismatch: have %v, want %v", root, err, errHistoryUnavailable)
+		}
+	}
+}
+
+// Tests that snapshot histories not continuing into the disk layer are dropped
+// on startup, e.g. after the diff layers were lost in a crash.
+func TestSnapshotHistoryAlignment(t *testing.T) {
+	tester := newHistoryTester(t, 1024)
+	for i := 0; i < 32; i++ {
+		tester.commit(t, 4)
+	}
+	// Histories were retained up to the accumulator, but the disk layer is the
+	// genesis one, nothing should be kept.
+	tester.tree.Release()
+	tester.tree = tester.open(t, tester.roots[0], 1024)
+	defer tester.tree.Release()
+
+	if root := tester.tree.history.headRoot; root != (common.Hash{}) {
+		t.Fatalf("Misaligned history retained: head %x", root)
+	}
+	if indexed := rawdb.ReadSnapshotHistoryIndexed(tester.diskdb); indexed != 0 {
+		t.Fatalf("Misaligned history index retained: %d items", indexed)
+	}
+	for _, root := range tester.roots[1:] {
+		if _, err := tester.tree.StateReader(root); !errors.Is(err, errHistoryUnavailable) {
+			t.Errorf("Dropped state %x: error mismatch: have %v, want %v", root, err, errHistoryUnavailable)
+		}
+	}
+}
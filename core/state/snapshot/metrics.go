@@ -50,4 +50,13 @@ var (
 	snapStorageWriteCounter = metrics.NewRegisteredCounter("state/snapshot/generation/duration/storage/write", nil)
 	// snapStorageCleanCounter measures time spent on deleting storages
 	snapStorageCleanCounter = metrics.NewRegisteredCounter("state/snapshot/generation/duration/storage/clean", nil)
+
+	// snapshotHistoryWriteTimer measures time spent on storing the reverse diffs
+	snapshotHistoryWriteTimer = metrics.NewRegisteredTimer("state/snapshot/history/write", nil)
+	// snapshotHistorySizeMeter measures the size of the stored reverse diffs
+	snapshotHistorySizeMeter = metrics.NewRegisteredMeter("state/snapshot/history/size", nil)
+	// snapshotHistoryAccountReadMeter measures the number of historical account reads
+	snapshotHistoryAccountReadMeter = metrics.NewRegisteredMeter("state/snapshot/history/read/account", nil)
+	// snapshotHistoryStorageReadMeter measures the number of historical storage reads
+	snapshotHistoryStorageReadMeter = metrics.NewRegisteredMeter("state/snapshot/history/read/storage", nil)
 )
@@ -154,6 +154,9 @@ type Config struct {
 	Recovery   bool // Indicator that the snapshots is in the recovery mode
 	NoBuild    bool // Indicator that the snapshots generation is disallowed
 	AsyncBuild bool // The snapshot generation is allowed to be constructed asynchronously
+
+	History    uint64 // Number of blocks to retain reverse diffs for beyond the diff layers (0 = disabled)
+	HistoryDir string // Ancient store directory to keep the reverse diffs in
 }
 
 // Tree is an Ethereum state snapshot tree. It consists of one persistent base
@@ -172,6 +175,8 @@ type Tree struct {
 	layers map[common.Hash]snapshot // Collection of all known layers
 	lock   sync.RWMutex
 
+	history *snapHistory // Reverse diffs of the flattened layers, nil if not retained
+
 	// Test hooks
 	onFlatten func() // Hook invoked when the bottom most diff layers are flattened
 }
@@ -214,6 +219,9 @@ func New(config Config, diskdb ethdb.KeyValueStore, triedb *trie.Database, root
 		log.Warn("Failed to load snapshot", "err", err)
 		if !config.NoBuild {
 			snap.Rebuild(root)
+			if err := snap.openHistory(); err != nil {
+				log.Error("Failed to open snapshot history", "err", err)
+			}
 			return snap, nil
 		}
 		return nil, err // Bail out the error, don't rebuild automatically.
@@ -223,9 +231,65 @@ func New(config Config, diskdb ethdb.KeyValueStore, triedb *trie.Database, root
 		snap.layers[head.Root()] = head
 		head = head.Parent()
 	}
+	if err := snap.openHistory(); err != nil {
+		log.Error("Failed to open snapshot history", "err", err)
+	}
 	return snap, nil
 }
 
+// openHistory opens the snapshot history if retention is enabled, discarding
+// any reverse diffs not continuing into the bottom-most snapshot layer.
+func (t *Tree) openHistory() error {
+	if t.config.History == 0 {
+		return nil
+	}
+	if t.config.HistoryDir == "" {
+		log.Warn("Snapshot history retention requires an ancient store")
+		return nil
+	}
+	history, err := newSnapHistory(t.diskdb, t.config.HistoryDir, t.config.History)
+	if err != nil {
+		return err
+	}
+	roots := []common.Hash{t.diskRoot()}
+	for root, layer := range t.layers {
+		if diff, ok := layer.(*diffLayer); ok {
+			if _, ok := diff.parent.(*diskLayer); ok {
+				roots = append(roots, root)
+			}
+		}
+	}
+	if err := history.align(roots...); err != nil {
+		history.freezer.Close()
+		return err
+	}
+	t.history = history
+	return nil
+}
+
+// retain stores the reverse diff of a single-block diff layer about to be
+// flattened into the bottom of the tree. Failures are not fatal, but discard
+// the snapshot history as it's not contiguous anymore.
+func (t *Tree) retain(layer *diffLayer) {
+	if t.history == nil {
+		return
+	}
+	if err := t.history.retain(layer); err != nil {
+		log.Warn("Failed to retain snapshot history", "root", layer.root, "err", err)
+		t.resetHistory()
+	}
+}
+
+// resetHistory discards all the retained snapshot history.
+func (t *Tree) resetHistory() {
+	if t.history == nil {
+		return
+	}
+	if err := t.history.reset(); err != nil {
+		log.Error("Failed to reset snapshot history", "err", err)
+	}
+}
+
 // waitBuild blocks until the snapshot finishes rebuilding. This method is meant
 // to be used by tests to ensure we're testing what we believe we are.
 func (t *Tree) waitBuild() {
@@ -280,6 +344,7 @@ func (t *Tree) Disable() {
 		}
 	}
 	t.layers = map[common.Hash]snapshot{}
+	t.resetHistory()
 
 	// Delete all snapshot liveness information from the database
 	batch := t.diskdb.NewBatch()
@@ -305,6 +370,26 @@ func (t *Tree) Snapshot(blockRoot common.Hash) Snapshot {
 	return t.layers[blockRoot]
 }
 
+// StateReader retrieves a read-only view of the state belonging to the given
+// root. Besides the layers maintained by the tree, states older than the disk
+// layer are served from the snapshot history, if retained.
+func (t *Tree) StateReader(root common.Hash) (Snapshot, error) {
+	if snap := t.Snapshot(root); snap != nil {
+		return snap, nil
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.history == nil {
+		return nil, errHistoryUnavailable
+	}
+	layer := &historicLayer{tree: t, root: root}
+	if _, _, _, err := layer.resolve(); err != nil {
+		return nil, err
+	}
+	return layer, nil
+}
+
 // Snapshots returns all visited layers from the topmost layer with specific
 // root and traverses downward. The layer amount is limited by the given number.
 // If nodisk is set, then disk layer is excluded.
@@ -399,7 +484,20 @@ func (t *Tree) Cap(root common.Hash, layers int) error {
 	// no child to rewire to the grandparent. In that case we can fake a temporary
 	// child for the capping and then remove it.
 	if layers == 0 {
-		// If full commit was requested, flatten the diffs and merge onto disk
+		// If full commit was requested, retain the diffs bottom-up, then
+		// flatten them and merge onto disk
+		var chain []*diffLayer
+		for layer := snapshot(diff); ; {
+			child, ok := layer.(*diffLayer)
+			if !ok {
+				break
+			}
+			chain = append(chain, child)
+			layer = child.parent
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			t.retain(chain[i])
+		}
 		diff.lock.RLock()
 		base := diffToDisk(diff.flatten().(*diffLayer))
 		diff.lock.RUnlock()
@@ -482,6 +580,9 @@ func (t *Tree) cap(diff *diffLayer, layers int) *diskLayer {
 		diff.lock.Lock()
 		defer diff.lock.Unlock()
 
+		// Retain the reverse diff of the parent before it loses its identity.
+		t.retain(parent)
+
 		// Flatten the parent into the grandparent. The flattening internally obtains a
 		// write lock on grandparent.
 		flattened := parent.flatten().(*diffLayer)
@@ -661,6 +762,13 @@ func (t *Tree) Release() {
 	if dl := t.disklayer(); dl != nil {
 		dl.Release()
 	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.history != nil {
+		t.history.freezer.Close()
+		t.history = nil
+	}
 }
 
 // Journal commits an entire diff hierarchy to disk into a single journal entry.
@@ -743,6 +851,7 @@ func (t *Tree) Rebuild(root common.Hash) {
 	// Start generating a new snapshot from scratch on a background thread. The
 	// generator will run a wiper first if there's not one running right now.
 	log.Info("Rebuilding state snapshot")
+	t.resetHistory()
 	t.layers = map[common.Hash]snapshot{
 		root: generateSnapshot(t.diskdb, t.triedb, t.config.CacheSize, root),
 	}
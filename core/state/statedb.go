@@ -172,6 +172,45 @@ func New(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error)
 	return sdb, nil
 }
 
+// NewHistoric creates a read-only state on top of a snapshot reader, typically
+// a historical state reconstructed from the retained snapshot history, whose
+// tries might not be available anymore. The state can be queried and executed
+// against, but hashing, proving or committing it is not supported.
+func NewHistoric(root common.Hash, db Database, reader snapshot.Snapshot) (*StateDB, error) {
+	sdb, err := New(types.EmptyRootHash, db, nil)
+	if err != nil {
+		return nil, err
+	}
+	sdb.originalRoot = root
+	sdb.snap = &historicReader{Snapshot: reader, db: sdb}
+	return sdb, nil
+}
+
+// historicReader wraps the snapshot reader of a historic state, surfacing its
+// failures as state errors, as there are no tries to fall back to.
+type historicReader struct {
+	snapshot.Snapshot
+	db *StateDB
+}
+
+// Account implements snapshot.Snapshot, recording any retrieval failure.
+func (r *historicReader) Account(hash common.Hash) (*types.SlimAccount, error) {
+	account, err := r.Snapshot.Account(hash)
+	if err != nil {
+		r.db.setError(err)
+	}
+	return account, err
+}
+
+// Storage implements snapshot.Snapshot, recording any retrieval failure.
+func (r *historicReader) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	blob, err := r.Snapshot.Storage(accountHash, storageHash)
+	if err != nil {
+		r.db.setError(err)
+	}
+	return blob, err
+}
+
 // StartPrefetcher initializes a new trie prefetcher to pull in nodes from the
 // state trie concurrently while the state is mutated so that when we reach the
 // commit phase, most of the needed data is already hot.
@@ -1382,6 +1421,12 @@ func (s *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addre
 	return s.accessList.Contains(addr, slot)
 }
 
+// AccessList returns the addresses and slots accessed by the current transaction
+// so far, including the ones warmed up before execution.
+func (s *StateDB) AccessList() types.AccessList {
+	return s.accessList.accessTuples()
+}
+
 // convertAccountSet converts a provided account set from address keyed to hash keyed.
 func (s *StateDB) convertAccountSet(set map[common.Address]*types.StateAccount) map[common.Hash]struct{} {
 	ret := make(map[common.Hash]struct{}, len(set))
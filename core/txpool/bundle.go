@@ -0,0 +1,89 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bundle is an ordered group of transactions which must be included into a
+// block together, consecutively and in order, or not at all.
+type Bundle struct {
+	Txs types.Transactions // Transactions to include, in order
+
+	BlockNumber  uint64 // Last block number the bundle may be included in
+	MinTimestamp uint64 // Earliest block timestamp the bundle may be included at (0 = unbounded)
+	MaxTimestamp uint64 // Latest block timestamp the bundle may be included at (0 = unbounded)
+
+	RevertingTxHashes []common.Hash // Transactions allowed to revert without invalidating the bundle
+}
+
+// Hash returns the identifier of the bundle, the hash of its ordered
+// transaction hashes followed by its target block number and timestamp bounds.
+// Resubmitting the same transactions for a different block or time window thus
+// yields a distinct bundle.
+func (b *Bundle) Hash() common.Hash {
+	blob := make([]byte, 0, len(b.Txs)*common.HashLength+24)
+	for _, tx := range b.Txs {
+		hash := tx.Hash()
+		blob = append(blob, hash[:]...)
+	}
+	blob = binary.BigEndian.AppendUint64(blob, b.BlockNumber)
+	blob = binary.BigEndian.AppendUint64(blob, b.MinTimestamp)
+	blob = binary.BigEndian.AppendUint64(blob, b.MaxTimestamp)
+	return crypto.Keccak256Hash(blob)
+}
+
+// CanRevert reports whether the transaction with the given hash is allowed to
+// revert without invalidating the bundle.
+func (b *Bundle) CanRevert(hash common.Hash) bool {
+	for _, allowed := range b.RevertingTxHashes {
+		if allowed == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// Includable reports whether the bundle may be included into a block with the
+// given number and timestamp.
+func (b *Bundle) Includable(number uint64, time uint64) bool {
+	if number > b.BlockNumber {
+		return false
+	}
+	if time < b.MinTimestamp {
+		return false
+	}
+	return b.MaxTimestamp == 0 || time <= b.MaxTimestamp
+}
+
+// BundleSubPool is a subpool which, on top of individual transactions, tracks
+// bundles of them to be included atomically.
+type BundleSubPool interface {
+	SubPool
+
+	// AddBundle enqueues a bundle into the pool if it is valid.
+	AddBundle(bundle *Bundle) error
+
+	// Bundles retrieves the bundles includable into a block with the given
+	// number and timestamp, ordered by their hash.
+	Bundles(number uint64, time uint64) []*Bundle
+}
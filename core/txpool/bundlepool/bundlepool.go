@@ -0,0 +1,346 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bundlepool implements the transaction pool for atomic groups of
+// transactions.
+package bundlepool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// txMaxSize is the maximum size a single transaction of a bundle can have,
+// matching the limit of the legacy pool.
+const txMaxSize = 4 * 32 * 1024
+
+var (
+	// ErrEmptyBundle is returned if a bundle contains no transactions.
+	ErrEmptyBundle = errors.New("empty bundle")
+
+	// ErrBundleTooLarge is returned if a bundle contains more transactions than
+	// the pool allows.
+	ErrBundleTooLarge = errors.New("bundle too large")
+
+	// ErrBundleExpired is returned if a bundle cannot be included into any block
+	// after the current head.
+	ErrBundleExpired = errors.New("bundle expired")
+
+	// ErrBundleTooFar is returned if a bundle targets a block too far ahead of
+	// the current head.
+	ErrBundleTooFar = errors.New("bundle target too far in the future")
+
+	// ErrBundlePoolFull is returned if the pool reached its bundle capacity.
+	ErrBundlePoolFull = errors.New("bundle pool full")
+
+	// ErrDuplicateBundleTx is returned if a bundle contains a transaction twice.
+	ErrDuplicateBundleTx = errors.New("duplicate transaction in bundle")
+
+	// ErrUnknownRevertingTx is returned if a bundle allows a transaction to revert
+	// which is not part of it.
+	ErrUnknownRevertingTx = errors.New("reverting transaction not in bundle")
+)
+
+// BundlePool is the transaction pool dedicated to bundles: ordered groups of
+// transactions which must be included together, consecutively, or not at all.
+//
+// Bundles are private to the local block producer: their transactions are
+// neither announced to nor retrievable by peers, and they do not appear among
+// the pending transactions. They leave the pool when the head moves past the
+// last block they target or when any of their transactions becomes stale.
+type BundlePool struct {
+	config Config     // Pool configuration
+	chain  BlockChain // Chain to validate bundles against
+
+	signer types.Signer   // Transaction signer of the current fork rules
+	head   *types.Header  // Current head of the chain
+	state  *state.StateDB // Current state at the head of the chain
+
+	bundles map[common.Hash]*txpool.Bundle // Bundles tracked by the pool, keyed by hash
+
+	txFeed event.Feed // Transaction feed, bundles are never announced
+	lock   sync.RWMutex
+}
+
+// New creates a new bundle pool to gather and expire transaction bundles.
+func New(config Config, chain BlockChain) *BundlePool {
+	config = (&config).sanitize()
+
+	return &BundlePool{
+		config:  config,
+		chain:   chain,
+		bundles: make(map[common.Hash]*txpool.Bundle),
+	}
+}
+
+// Filter returns whether the given transaction can be consumed by the bundle
+// pool. Bundle transactions only enter through AddBundle, so it accepts none.
+func (p *BundlePool) Filter(tx *types.Transaction) bool {
+	return false
+}
+
+// Init sets the base parameters of the subpool.
+func (p *BundlePool) Init(gasTip *big.Int, head *types.Header, reserve txpool.AddressReserver) error {
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		statedb, err = p.chain.StateAt(types.EmptyRootHash)
+	}
+	if err != nil {
+		return err
+	}
+	p.head, p.state = head, statedb
+	p.signer = types.LatestSigner(p.chain.Config())
+	return nil
+}
+
+// Close terminates any background processing threads and releases any held
+// resources.
+func (p *BundlePool) Close() error {
+	return nil
+}
+
+// Reset drops the bundles which cannot be included after the new head anymore:
+// the ones targeting past blocks or timestamps, and the ones containing a
+// transaction with a nonce already used on chain.
+func (p *BundlePool) Reset(oldHead, newHead *types.Header) {
+	statedb, err := p.chain.StateAt(newHead.Root)
+	if err != nil {
+		log.Error("Failed to reset bundlepool state", "err", err)
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.head, p.state = newHead, statedb
+	p.signer = types.LatestSigner(p.chain.Config())
+
+	for hash, bundle := range p.bundles {
+		if expired(bundle, newHead) {
+			log.Trace("Dropping expired bundle", "hash", hash, "block", bundle.BlockNumber)
+			delete(p.bundles, hash)
+			expiredMeter.Mark(1)
+			continue
+		}
+		if err := p.validateNonces(bundle); err != nil {
+			log.Trace("Dropping stale bundle", "hash", hash, "err", err)
+			delete(p.bundles, hash)
+			staleMeter.Mark(1)
+		}
+	}
+	bundlesGauge.Update(int64(len(p.bundles)))
+}
+
+// expired reports whether a bundle cannot be included into any block after the
+// given head.
+func expired(bundle *txpool.Bundle, head *types.Header) bool {
+	if bundle.BlockNumber <= head.Number.Uint64() {
+		return true
+	}
+	return bundle.MaxTimestamp != 0 && bundle.MaxTimestamp <= head.Time
+}
+
+// SetGasTip is a no-op: bundles are exempt from the minimum gas tip as they
+// usually pay the block producer directly.
+func (p *BundlePool) SetGasTip(tip *big.Int) {}
+
+// Has always returns false, bundle transactions are not shared with peers.
+func (p *BundlePool) Has(hash common.Hash) bool {
+	return false
+}
+
+// Get always returns nil, bundle transactions are not shared with peers.
+func (p *BundlePool) Get(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+// Add rejects all transactions, bundle transactions only enter the pool
+// through AddBundle.
+func (p *BundlePool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	errs := make([]error, len(txs))
+	for i := range txs {
+		errs[i] = core.ErrTxTypeNotSupported
+	}
+	return errs
+}
+
+// AddBundle validates a bundle against the current head and enqueues it if it
+// is includable into an upcoming block.
+func (p *BundlePool) AddBundle(bundle *txpool.Bundle) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err := p.validateBundle(bundle); err != nil {
+		log.Trace("Bundle rejected", "err", err)
+		invalidMeter.Mark(1)
+		return err
+	}
+	hash := bundle.Hash()
+	if _, ok := p.bundles[hash]; ok {
+		return txpool.ErrAlreadyKnown
+	}
+	if len(p.bundles) >= p.config.GlobalBundles {
+		return ErrBundlePoolFull
+	}
+	p.bundles[hash] = bundle
+
+	addedMeter.Mark(1)
+	bundlesGauge.Update(int64(len(p.bundles)))
+	log.Debug("Bundle added", "hash", hash, "txs", len(bundle.Txs), "block", bundle.BlockNumber)
+	return nil
+}
+
+// validateBundle checks whether a bundle is valid against the current head.
+// The balances are not checked, as a transaction may be funded by an earlier
+// one of the same bundle; the miner simulates bundles before including them.
+func (p *BundlePool) validateBundle(bundle *txpool.Bundle) error {
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if len(bundle.Txs) > p.config.MaxBundleTxs {
+		return fmt.Errorf("%w: %d transactions, limit %d", ErrBundleTooLarge, len(bundle.Txs), p.config.MaxBundleTxs)
+	}
+	if expired(bundle, p.head) {
+		return fmt.Errorf("%w: head %d, bundle block %d", ErrBundleExpired, p.head.Number, bundle.BlockNumber)
+	}
+	if bundle.MaxTimestamp != 0 && bundle.MaxTimestamp < bundle.MinTimestamp {
+		return fmt.Errorf("%w: max timestamp %d before min timestamp %d", ErrBundleExpired, bundle.MaxTimestamp, bundle.MinTimestamp)
+	}
+	if limit := p.head.Number.Uint64() + p.config.MaxHorizon; bundle.BlockNumber > limit {
+		return fmt.Errorf("%w: bundle block %d, limit %d", ErrBundleTooFar, bundle.BlockNumber, limit)
+	}
+	var (
+		opts = &txpool.ValidationOptions{
+			Config: p.chain.Config(),
+			Accept: 0 |
+				1<<types.LegacyTxType |
+				1<<types.AccessListTxType |
+				1<<types.DynamicFeeTxType,
+			MaxSize: txMaxSize,
+			MinTip:  new(big.Int),
+		}
+		hashes = make(map[common.Hash]struct{}, len(bundle.Txs))
+		gas    uint64
+	)
+	for i, tx := range bundle.Txs {
+		if err := txpool.ValidateTransaction(tx, p.head, p.signer, opts); err != nil {
+			return fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		if _, ok := hashes[tx.Hash()]; ok {
+			return fmt.Errorf("%w: %x", ErrDuplicateBundleTx, tx.Hash())
+		}
+		hashes[tx.Hash()] = struct{}{}
+		gas += tx.Gas()
+	}
+	if gas > p.head.GasLimit {
+		return fmt.Errorf("%w: bundle gas %d, block gas limit %d", txpool.ErrGasLimit, gas, p.head.GasLimit)
+	}
+	for _, hash := range bundle.RevertingTxHashes {
+		if _, ok := hashes[hash]; !ok {
+			return fmt.Errorf("%w: %x", ErrUnknownRevertingTx, hash)
+		}
+	}
+	return p.validateNonces(bundle)
+}
+
+// validateNonces checks that no transaction of a bundle uses a nonce already
+// used in the head state.
+func (p *BundlePool) validateNonces(bundle *txpool.Bundle) error {
+	for i, tx := range bundle.Txs {
+		from, _ := types.Sender(p.signer, tx) // already validated
+		if next := p.state.GetNonce(from); next > tx.Nonce() {
+			return fmt.Errorf("bundle tx %d: %w: next nonce %v, tx nonce %v", i, core.ErrNonceTooLow, next, tx.Nonce())
+		}
+	}
+	return nil
+}
+
+// Bundles retrieves the bundles includable into a block with the given number
+// and timestamp, ordered by their hash.
+func (p *BundlePool) Bundles(number uint64, time uint64) []*txpool.Bundle {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	var (
+		hashes  []common.Hash
+		bundles []*txpool.Bundle
+	)
+	for hash, bundle := range p.bundles {
+		if bundle.Includable(number, time) {
+			hashes = append(hashes, hash)
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+	for _, hash := range hashes {
+		bundles = append(bundles, p.bundles[hash])
+	}
+	return bundles
+}
+
+// Pending returns no transactions, bundles are retrieved through Bundles.
+func (p *BundlePool) Pending(enforceTips bool) map[common.Address][]*txpool.LazyTransaction {
+	return make(map[common.Address][]*txpool.LazyTransaction)
+}
+
+// SubscribeTransactions registers a subscription for new transaction events.
+// Bundle transactions are never announced, so no events are ever delivered.
+func (p *BundlePool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// Nonce returns zero, bundles do not affect the nonces handed out by the pool.
+func (p *BundlePool) Nonce(addr common.Address) uint64 {
+	return 0
+}
+
+// Stats returns zero counts, bundle transactions are not tracked individually.
+func (p *BundlePool) Stats() (int, int) {
+	return 0, 0
+}
+
+// Content returns empty sets, bundle transactions are not tracked individually.
+func (p *BundlePool) Content() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	return make(map[common.Address][]*types.Transaction), make(map[common.Address][]*types.Transaction)
+}
+
+// ContentFrom returns empty sets, bundle transactions are not tracked individually.
+func (p *BundlePool) ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction) {
+	return []*types.Transaction{}, []*types.Transaction{}
+}
+
+// Locals returns no accounts, the bundle pool has no notion of locality.
+func (p *BundlePool) Locals() []common.Address {
+	return []common.Address{}
+}
+
+// Status returns unknown for all transactions, bundle transactions are not
+// shared with peers.
+func (p *BundlePool) Status(hash common.Hash) txpool.TxStatus {
+	return txpool.TxStatusUnknown
+}
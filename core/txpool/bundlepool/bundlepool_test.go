@@ -0,0 +1,186 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testBlockChain is a mock of the live chain for testing the pool.
+type testBlockChain struct {
+	config *params.ChainConfig
+	head   *types.Header
+	db     state.Database
+}
+
+func (bc *testBlockChain) Config() *params.ChainConfig { return bc.config }
+func (bc *testBlockChain) CurrentBlock() *types.Header { return bc.head }
+
+func (bc *testBlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, bc.db, nil)
+}
+
+// newTestPool creates a bundle pool on top of a chain at the given head number
+// where the given key has the given nonce.
+func newTestPool(t *testing.T, config Config, number uint64, key *ecdsa.PrivateKey, nonce uint64) (*BundlePool, *testBlockChain) {
+	t.Helper()
+
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(types.EmptyRootHash, db, nil)
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+	statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), nonce)
+	root, err := statedb.Commit(number, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	chain := &testBlockChain{
+		config: params.TestChainConfig,
+		head: &types.Header{
+			Number:   new(big.Int).SetUint64(number),
+			Time:     number * 12,
+			GasLimit: 30_000_000,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Root:     root,
+		},
+		db: db,
+	}
+	pool := New(config, chain)
+	if err := pool.Init(new(big.Int), chain.head, nil); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	return pool, chain
+}
+
+func makeTx(key *ecdsa.PrivateKey, nonce uint64) *types.Transaction {
+	return types.MustSignNewTx(key, types.LatestSigner(params.TestChainConfig), &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: new(big.Int),
+		GasFeeCap: big.NewInt(2 * params.InitialBaseFee),
+		Gas:       params.TxGas,
+		To:        &common.Address{0xaa},
+	})
+}
+
+// Tests that bundles are validated on submission.
+func TestAddBundle(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	config := DefaultConfig
+	config.MaxBundleTxs = 2
+	config.GlobalBundles = 2
+	pool, _ := newTestPool(t, config, 10, key, 5)
+
+	tests := []struct {
+		bundle *txpool.Bundle
+		err    error
+	}{
+		{&txpool.Bundle{BlockNumber: 11}, ErrEmptyBundle},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5), makeTx(key, 6), makeTx(key, 7)}, BlockNumber: 11}, ErrBundleTooLarge},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 10}, ErrBundleExpired},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 11, MaxTimestamp: 120}, ErrBundleExpired},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 10 + DefaultConfig.MaxHorizon + 1}, ErrBundleTooFar},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5), makeTx(key, 5)}, BlockNumber: 11}, ErrDuplicateBundleTx},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 11, RevertingTxHashes: []common.Hash{{0x01}}}, ErrUnknownRevertingTx},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 4)}, BlockNumber: 11}, core.ErrNonceTooLow},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 11}, nil},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5)}, BlockNumber: 11}, txpool.ErrAlreadyKnown},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 5), makeTx(key, 6)}, BlockNumber: 12}, nil},
+		{&txpool.Bundle{Txs: types.Transactions{makeTx(key, 6)}, BlockNumber: 12}, ErrBundlePoolFull},
+	}
+	for i, tt := range tests {
+		if err := pool.AddBundle(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if bundles := pool.Bundles(11, 132); len(bundles) != 2 {
+		t.Errorf("includable bundle count mismatch: have %d, want %d", len(bundles), 2)
+	}
+	if bundles := pool.Bundles(12, 144); len(bundles) != 1 {
+		t.Errorf("includable bundle count mismatch: have %d, want %d", len(bundles), 1)
+	}
+}
+
+// Tests that bundles of the same transactions are distinct if they target a
+// different block or time window.
+func TestBundleIdentity(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool, _ := newTestPool(t, DefaultConfig, 10, key, 5)
+
+	txs := types.Transactions{makeTx(key, 5)}
+	bundles := []*txpool.Bundle{
+		{Txs: txs, BlockNumber: 11},
+		{Txs: txs, BlockNumber: 12},
+		{Txs: txs, BlockNumber: 12, MinTimestamp: 130},
+		{Txs: txs, BlockNumber: 12, MaxTimestamp: 150},
+	}
+	for i, bundle := range bundles {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Errorf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	for i, bundle := range bundles {
+		if err := pool.AddBundle(bundle); !errors.Is(err, txpool.ErrAlreadyKnown) {
+			t.Errorf("bundle %d: error mismatch: have %v, want %v", i, err, txpool.ErrAlreadyKnown)
+		}
+	}
+}
+
+// Tests that resets drop the expired bundles and the ones with stale nonces.
+func TestResetBundles(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pool, chain := newTestPool(t, DefaultConfig, 10, key, 0)
+
+	var (
+		expiring = &txpool.Bundle{Txs: types.Transactions{makeTx(key, 1)}, BlockNumber: 11}
+		stale    = &txpool.Bundle{Txs: types.Transactions{makeTx(key, 0)}, BlockNumber: 20}
+		timed    = &txpool.Bundle{Txs: types.Transactions{makeTx(key, 2)}, BlockNumber: 20, MaxTimestamp: 132}
+		live     = &txpool.Bundle{Txs: types.Transactions{makeTx(key, 1), makeTx(key, 2)}, BlockNumber: 20, MinTimestamp: 140}
+	)
+	for i, bundle := range []*txpool.Bundle{expiring, stale, timed, live} {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	if bundles := pool.Bundles(11, 132); len(bundles) != 3 {
+		t.Fatalf("includable bundle count mismatch: have %d, want %d", len(bundles), 3)
+	}
+	// Include the first transaction of the key in the next block
+	statedb, _ := state.New(chain.head.Root, chain.db, nil)
+	statedb.SetNonce(crypto.PubkeyToAddress(key.PublicKey), 1)
+	root, _ := statedb.Commit(11, false)
+
+	head := &types.Header{Number: big.NewInt(11), Time: 132, GasLimit: chain.head.GasLimit, BaseFee: chain.head.BaseFee, Root: root}
+	pool.Reset(chain.head, head)
+	chain.head = head
+
+	bundles := pool.Bundles(12, 144)
+	if len(bundles) != 1 || bundles[0].Hash() != live.Hash() {
+		t.Fatalf("surviving bundles mismatch: have %d, want only the live one", len(bundles))
+	}
+}
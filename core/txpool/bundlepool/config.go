@@ -0,0 +1,54 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bundlepool
+
+import (
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config are the configuration parameters of the bundle pool.
+type Config struct {
+	GlobalBundles int    // Maximum number of bundles tracked by the pool
+	MaxBundleTxs  int    // Maximum number of transactions in a single bundle
+	MaxHorizon    uint64 // Maximum number of blocks ahead of the head a bundle may target
+}
+
+// DefaultConfig contains the default configurations for the bundle pool.
+var DefaultConfig = Config{
+	GlobalBundles: 1024,
+	MaxBundleTxs:  16,
+	MaxHorizon:    256,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.GlobalBundles < 1 {
+		log.Warn("Sanitizing invalid bundlepool global bundles", "provided", conf.GlobalBundles, "updated", DefaultConfig.GlobalBundles)
+		conf.GlobalBundles = DefaultConfig.GlobalBundles
+	}
+	if conf.MaxBundleTxs < 1 {
+		log.Warn("Sanitizing invalid bundlepool bundle size", "provided", conf.MaxBundleTxs, "updated", DefaultConfig.MaxBundleTxs)
+		conf.MaxBundleTxs = DefaultConfig.MaxBundleTxs
+	}
+	if conf.MaxHorizon < 1 {
+		log.Warn("Sanitizing invalid bundlepool horizon", "provided", conf.MaxHorizon, "updated", DefaultConfig.MaxHorizon)
+		conf.MaxHorizon = DefaultConfig.MaxHorizon
+	}
+	return conf
+}
@@ -54,4 +54,8 @@ var (
 	// ErrFutureReplacePending is returned if a future transaction replaces a pending
 	// one. Future transactions should only be able to replace other future transactions.
 	ErrFutureReplacePending = errors.New("future transaction tries to replace pending")
+
+	// ErrBundlesNotSupported is returned if a bundle is submitted to a pool without
+	// any subpool tracking bundles.
+	ErrBundlesNotSupported = errors.New("bundles not supported")
 )
@@ -100,6 +100,14 @@ var (
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
+
+	// Metrics for the transaction log
+	txlogReplayedMeter   = metrics.NewRegisteredMeter("txpool/txlog/replayed", nil)
+	txlogAcceptedMeter   = metrics.NewRegisteredMeter("txpool/txlog/accepted", nil)
+	txlogRejectedMeter   = metrics.NewRegisteredMeter("txpool/txlog/rejected", nil)
+	txlogCorruptMeter    = metrics.NewRegisteredMeter("txpool/txlog/corrupt", nil)
+	txlogCompactionMeter = metrics.NewRegisteredMeter("txpool/txlog/compaction", nil)
+	txlogSizeGauge       = metrics.NewRegisteredGauge("txpool/txlog/size", nil)
 )
 
 // BlockChain defines the minimal set of methods needed to back a tx pool with
@@ -123,12 +131,14 @@ type Config struct {
 	Locals    []common.Address // Addresses that should be treated by default as local
 	NoLocals  bool             // Whether local transaction handling should be disabled
 	Journal   string           // Journal of local transactions to survive node restarts
-	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
+	Rejournal time.Duration    // Time interval to regenerate the local transaction journal and compact the log
 
 	// JournalRemote controls whether journaling includes remote transactions or not.
 	// When true, all transactions loaded from the journal are treated as remote.
 	JournalRemote bool
 
+	TxLog string // Append-only log of all pool transactions to survive node restarts (empty = disabled)
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -138,6 +148,8 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	ValidationShards int // Number of sender shards validating transactions concurrently (0 = serial validation)
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -192,6 +204,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if conf.ValidationShards < 0 {
+		log.Warn("Sanitizing invalid txpool validation shards", "provided", conf.ValidationShards, "updated", DefaultConfig.ValidationShards)
+		conf.ValidationShards = DefaultConfig.ValidationShards
+	}
 	return conf
 }
 
@@ -214,9 +230,11 @@ type LegacyPool struct {
 	currentHead   atomic.Pointer[types.Header] // Current head of the blockchain
 	currentState  *state.StateDB               // Current state in the blockchain head
 	pendingNonces *noncer                      // Pending state tracking virtual nonces
+	accounts      *accountCache                // Sender-sharded view of the head state, nil if validating serially
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
+	txlog   *txLog      // Log of all transactions to back up to disk
 
 	reserve txpool.AddressReserver       // Address reserver to ensure exclusivity across subpools
 	pending map[common.Address]*list     // All currently processable transactions
@@ -275,6 +293,9 @@ func New(config Config, chain BlockChain) *LegacyPool {
 	if (!config.NoLocals || config.JournalRemote) && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 	}
+	if config.TxLog != "" {
+		pool.txlog = newTxLog(config.TxLog)
+	}
 	return pool
 }
 
@@ -303,9 +324,11 @@ func (pool *LegacyPool) Init(gasTip *big.Int, head *types.Header, reserve txpool
 	// Initialize the state with head block, or fallback to empty one in
 	// case the head state is not available(might occur when node is not
 	// fully synced).
-	statedb, err := pool.chain.StateAt(head.Root)
+	root := head.Root
+	statedb, err := pool.chain.StateAt(root)
 	if err != nil {
-		statedb, err = pool.chain.StateAt(types.EmptyRootHash)
+		root = types.EmptyRootHash
+		statedb, err = pool.chain.StateAt(root)
 	}
 	if err != nil {
 		return err
@@ -313,6 +336,7 @@ func (pool *LegacyPool) Init(gasTip *big.Int, head *types.Header, reserve txpool
 	pool.currentHead.Store(head)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
+	pool.resetAccounts(root)
 
 	// Start the reorg loop early, so it can handle requests generated during
 	// journal loading.
@@ -332,6 +356,23 @@ func (pool *LegacyPool) Init(gasTip *big.Int, head *types.Header, reserve txpool
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If the transaction log is enabled, replay it re-validating everything
+	if pool.txlog != nil {
+		add := func(txs []*types.Transaction, local bool) []error {
+			if local {
+				return pool.addLocals(txs)
+			}
+			return pool.addRemotesSync(txs)
+		}
+		if err := pool.txlog.replay(add); err != nil {
+			log.Warn("Failed to replay transaction log", "err", err)
+		}
+		pool.mu.Lock()
+		if err := pool.txlog.compact(pool.pooled(), pool.locals.contains); err != nil {
+			log.Warn("Failed to compact transaction log", "err", err)
+		}
+		pool.mu.Unlock()
+	}
 	pool.wg.Add(1)
 	go pool.loop()
 	return nil
@@ -403,6 +444,15 @@ func (pool *LegacyPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.txlog != nil {
+				pool.mu.Lock()
+				if pool.txlog.grown() {
+					if err := pool.txlog.compact(pool.pooled(), pool.locals.contains); err != nil {
+						log.Warn("Failed to compact tx log", "err", err)
+					}
+				}
+				pool.mu.Unlock()
+			}
 		}
 	}
 }
@@ -416,6 +466,9 @@ func (pool *LegacyPool) Close() error {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.txlog != nil {
+		pool.txlog.close()
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 }
@@ -583,6 +636,13 @@ func (pool *LegacyPool) toJournal() map[common.Address]types.Transactions {
 	if !pool.config.JournalRemote {
 		return pool.local()
 	}
+	return pool.pooled()
+}
+
+// pooled retrieves all transactions in the pool, grouped by origin account and
+// sorted by nonce. The returned transaction set is a copy and can be freely
+// modified by calling code.
+func (pool *LegacyPool) pooled() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr, pending := range pool.pending {
 		txs[addr] = append(txs[addr], pending.Flatten()...)
@@ -636,7 +696,7 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 	opts := &txpool.ValidationOptionsWithState{
-		State: pool.currentState,
+		State: pool.stateReader(),
 
 		FirstNonceGap: nil, // Pool allows arbitrary arrival order, don't invalidate nonce gaps
 		UsedAndLeftSlots: func(addr common.Address) (int, int) {
@@ -897,9 +957,15 @@ func (pool *LegacyPool) enqueueTx(hash common.Hash, tx *types.Transaction, local
 	return old != nil, nil
 }
 
-// journalTx adds the specified transaction to the local disk journal if it is
-// deemed to have been sent from a local account.
+// journalTx adds the specified transaction to the transaction log if enabled,
+// and to the local disk journal if it is deemed to have been sent from a local
+// account.
 func (pool *LegacyPool) journalTx(from common.Address, tx *types.Transaction) {
+	if pool.txlog != nil {
+		if err := pool.txlog.insert(tx, pool.locals.contains(from)); err != nil {
+			log.Warn("Failed to log transaction", "err", err)
+		}
+	}
 	// Only journal if it's enabled and the transaction is local
 	if pool.journal == nil || (!pool.config.JournalRemote && !pool.locals.contains(from)) {
 		return
@@ -1019,7 +1085,29 @@ func (pool *LegacyPool) Add(txs []*types.Transaction, local, sync bool) []error
 	if len(news) == 0 {
 		return errs
 	}
-
+	// If sharded validation is enabled, weed out the transactions failing the
+	// sender-local state checks concurrently, before obtaining the pool lock
+	if pool.config.ValidationShards > 0 {
+		var (
+			valid   = news[:0]
+			nilSlot = 0
+		)
+		for i, err := range pool.prevalidate(news) {
+			for errs[nilSlot] != nil {
+				nilSlot++
+			}
+			if err != nil {
+				errs[nilSlot] = err
+				invalidTxMeter.Mark(1)
+			} else {
+				valid = append(valid, news[i])
+			}
+			nilSlot++
+		}
+		if news = valid; len(news) == 0 {
+			return errs
+		}
+	}
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
@@ -1444,6 +1532,7 @@ func (pool *LegacyPool) reset(oldHead, newHead *types.Header) {
 	pool.currentHead.Store(newHead)
 	pool.currentState = statedb
 	pool.pendingNonces = newNoncer(statedb)
+	pool.resetAccounts(newHead.Root)
 
 	costFn := types.NewL1CostFunc(pool.chainconfig, statedb)
 	pool.l1CostFn = func(dataGas types.RollupGasData) *big.Int {
@@ -0,0 +1,297 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// txLogHeaderSize is the size of a log record header: the payload length
+	// followed by its checksum, both 4 bytes big endian.
+	txLogHeaderSize = 8
+
+	// txLogMaxRecordSize is the maximum payload size of a log record. Anything
+	// larger is deemed corruption, as the pool rejects such transactions anyway.
+	txLogMaxRecordSize = 1 + txMaxSize
+
+	// txLogLocalFlag marks the records of transactions added as local.
+	txLogLocalFlag = 0x01
+)
+
+// txLogTable is the checksum table of the log records.
+var txLogTable = crc32.MakeTable(crc32.Castagnoli)
+
+// errCorruptTxLog is returned if a record of the transaction log is torn or
+// fails its checksum.
+var errCorruptTxLog = errors.New("corrupt transaction log record")
+
+// txLog is an append-only log of all the transactions entering the pool, local
+// and remote ones alike, allowing them to survive node restarts.
+//
+// Every transaction is appended as a single record made of a header (payload
+// length and CRC32-C checksum) and a payload (flags byte and the transaction in
+// its binary encoding). A crash can only tear the last record, which is dropped
+// and truncated away on replay. Transactions leaving the pool are not recorded,
+// instead the log is periodically compacted to the content of the pool, and
+// the replay re-validates everything against the head state.
+type txLog struct {
+	path   string         // Filesystem path to store the transactions at
+	writer io.WriteCloser // Output stream to append new transactions into
+
+	size      int64 // Current size of the log
+	compacted int64 // Size of the log after the last compaction
+}
+
+// newTxLog creates a new transaction log at the given path.
+func newTxLog(path string) *txLog {
+	return &txLog{
+		path: path,
+	}
+}
+
+// replay parses the transaction log from disk, loading its contents into the
+// pool through the given add function, which is expected to fully validate the
+// transactions. A torn or corrupt tail is truncated away.
+func (l *txLog) replay(add func(txs []*types.Transaction, local bool) []error) error {
+	input, err := os.OpenFile(l.path, os.O_RDWR, 0644)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // Nothing logged yet
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	// Temporarily discard any log additions (don't double add on replay)
+	l.writer = new(devNull)
+	defer func() { l.writer = nil }()
+
+	var (
+		total, accepted, rejected int
+
+		locals, remotes types.Transactions
+	)
+	load := func(txs types.Transactions, local bool) {
+		for _, err := range add(txs, local) {
+			if err != nil {
+				log.Debug("Failed to replay logged transaction", "err", err)
+				rejected++
+			} else {
+				accepted++
+			}
+		}
+	}
+	// Parse the records until the end of the log or the first corrupt one
+	var (
+		offset  int64
+		failure error
+	)
+	for {
+		tx, local, size, err := readTxLogRecord(input)
+		if err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			break
+		}
+		offset += size
+		total++
+
+		if local {
+			if locals = append(locals, tx); len(locals) > 1024 {
+				load(locals, true)
+				locals = locals[:0]
+			}
+		} else {
+			if remotes = append(remotes, tx); len(remotes) > 1024 {
+				load(remotes, false)
+				remotes = remotes[:0]
+			}
+		}
+	}
+	if len(locals) > 0 {
+		load(locals, true)
+	}
+	if len(remotes) > 0 {
+		load(remotes, false)
+	}
+	txlogReplayedMeter.Mark(int64(total))
+	txlogAcceptedMeter.Mark(int64(accepted))
+	txlogRejectedMeter.Mark(int64(rejected))
+
+	// Drop anything past the last intact record, it will be overwritten anyway
+	if failure != nil {
+		txlogCorruptMeter.Mark(1)
+		log.Warn("Truncating corrupt transaction log", "offset", offset, "err", failure)
+		if err := input.Truncate(offset); err != nil {
+			return err
+		}
+	}
+	l.size, l.compacted = offset, offset
+	log.Info("Replayed transaction log", "transactions", total, "accepted", accepted, "rejected", rejected)
+	return nil
+}
+
+// readTxLogRecord reads the next record from the log, returning the decoded
+// transaction, whether it was local and the size of the whole record. It returns
+// io.EOF at the clean end of the log and errCorruptTxLog for torn records.
+func readTxLogRecord(r io.Reader) (*types.Transaction, bool, int64, error) {
+	var header [txLogHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, false, 0, io.EOF
+		}
+		return nil, false, 0, fmt.Errorf("%w: %v", errCorruptTxLog, err)
+	}
+	size := binary.BigEndian.Uint32(header[:4])
+	if size < 2 || size > txLogMaxRecordSize {
+		return nil, false, 0, fmt.Errorf("%w: invalid size %d", errCorruptTxLog, size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, false, 0, fmt.Errorf("%w: %v", errCorruptTxLog, err)
+	}
+	if sum := crc32.Checksum(payload, txLogTable); sum != binary.BigEndian.Uint32(header[4:]) {
+		return nil, false, 0, fmt.Errorf("%w: checksum mismatch", errCorruptTxLog)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(payload[1:]); err != nil {
+		return nil, false, 0, fmt.Errorf("%w: %v", errCorruptTxLog, err)
+	}
+	return tx, payload[0]&txLogLocalFlag != 0, txLogHeaderSize + int64(size), nil
+}
+
+// encodeTxLogRecord encodes a transaction into a log record.
+func encodeTxLogRecord(tx *types.Transaction, local bool) ([]byte, error) {
+	blob, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	record := make([]byte, txLogHeaderSize+1+len(blob))
+	if local {
+		record[txLogHeaderSize] = txLogLocalFlag
+	}
+	copy(record[txLogHeaderSize+1:], blob)
+
+	payload := record[txLogHeaderSize:]
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(payload, txLogTable))
+	return record, nil
+}
+
+// insert appends the specified transaction to the log.
+func (l *txLog) insert(tx *types.Transaction, local bool) error {
+	if l.writer == nil {
+		return errNoActiveJournal
+	}
+	record, err := encodeTxLogRecord(tx, local)
+	if err != nil {
+		return err
+	}
+	// Write the whole record at once, so a crash can at most tear the tail
+	if _, err := l.writer.Write(record); err != nil {
+		return err
+	}
+	l.size += int64(len(record))
+	txlogSizeGauge.Update(l.size)
+	return nil
+}
+
+// grown reports whether anything was appended to the log since the last
+// compaction.
+func (l *txLog) grown() bool {
+	return l.size > l.compacted
+}
+
+// compact regenerates the transaction log based on the current contents of
+// the transaction pool.
+func (l *txLog) compact(all map[common.Address]types.Transactions, local func(common.Address) bool) error {
+	// Close the current log (if any is open)
+	if l.writer != nil {
+		if err := l.writer.Close(); err != nil {
+			return err
+		}
+		l.writer = nil
+	}
+	// Generate a new log with the contents of the current pool
+	replacement, err := os.OpenFile(l.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var size int64
+	for addr, txs := range all {
+		for _, tx := range txs {
+			record, err := encodeTxLogRecord(tx, local(addr))
+			if err == nil {
+				_, err = replacement.Write(record)
+			}
+			if err != nil {
+				replacement.Close()
+				return err
+			}
+			size += int64(len(record))
+		}
+	}
+	// Make sure the replacement is durable before it takes the place of the log
+	if err := replacement.Sync(); err != nil {
+		replacement.Close()
+		return err
+	}
+	replacement.Close()
+
+	if err = os.Rename(l.path+".new", l.path); err != nil {
+		return err
+	}
+	sink, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	l.writer = sink
+	l.size, l.compacted = size, size
+
+	txlogCompactionMeter.Mark(1)
+	txlogSizeGauge.Update(size)
+	log.Debug("Compacted transaction log", "accounts", len(all), "size", common.StorageSize(size))
+	return nil
+}
+
+// close flushes the transaction log contents to disk and closes the file.
+func (l *txLog) close() error {
+	var err error
+
+	if l.writer != nil {
+		if file, ok := l.writer.(*os.File); ok {
+			err = file.Sync()
+		}
+		if cerr := l.writer.Close(); err == nil {
+			err = cerr
+		}
+		l.writer = nil
+	}
+	return err
+}
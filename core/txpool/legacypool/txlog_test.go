@@ -0,0 +1,176 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// replayTxLog replays the log at path, returning the transactions fed to the
+// pool along with whether they were local.
+func replayTxLog(t *testing.T, path string) ([]*types.Transaction, []bool) {
+	t.Helper()
+
+	var (
+		txs    []*types.Transaction
+		locals []bool
+	)
+	err := newTxLog(path).replay(func(batch []*types.Transaction, local bool) []error {
+		for _, tx := range batch {
+			txs = append(txs, tx)
+			locals = append(locals, local)
+		}
+		return make([]error, len(batch))
+	})
+	if err != nil {
+		t.Fatalf("failed to replay log: %v", err)
+	}
+	return txs, locals
+}
+
+// Tests that the transaction log survives torn and corrupt records, dropping
+// everything from the first bad record onwards.
+func TestTxLogCorruption(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	path := filepath.Join(t.TempDir(), "txlog")
+
+	txlog := newTxLog(path)
+	if err := txlog.compact(nil, nil); err != nil {
+		t.Fatalf("failed to create log: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := txlog.insert(transaction(uint64(i), 100000, key), i == 0); err != nil {
+			t.Fatalf("failed to insert tx %d: %v", i, err)
+		}
+	}
+	txlog.close()
+
+	txs, locals := replayTxLog(t, path)
+	if len(txs) != 3 || !locals[0] || locals[1] || locals[2] {
+		t.Fatalf("replay mismatch: have %d txs, locals %v", len(txs), locals)
+	}
+	// Tear the last record as if the node crashed mid-write
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatalf("failed to tear log: %v", err)
+	}
+	if txs, _ = replayTxLog(t, path); len(txs) != 2 {
+		t.Fatalf("torn replay mismatch: have %d txs, want %d", len(txs), 2)
+	}
+	info, _ = os.Stat(path)
+	record, _ := encodeTxLogRecord(transaction(0, 100000, key), true)
+	if want := 2 * int64(len(record)); info.Size() != want {
+		t.Fatalf("torn log not truncated: have %d bytes, want %d", info.Size(), want)
+	}
+	// Flip a byte in the second record, failing its checksum
+	blob, _ := os.ReadFile(path)
+	blob[len(record)+txLogHeaderSize+10] ^= 0xff
+	os.WriteFile(path, blob, 0644)
+
+	if txs, _ = replayTxLog(t, path); len(txs) != 1 || txs[0].Nonce() != 0 {
+		t.Fatalf("corrupt replay mismatch: have %d txs, want %d", len(txs), 1)
+	}
+}
+
+// Tests that compaction rewrites the log to the given transaction set only.
+func TestTxLogCompaction(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	path := filepath.Join(t.TempDir(), "txlog")
+
+	txlog := newTxLog(path)
+	txlog.compact(nil, nil)
+	for i := 0; i < 8; i++ {
+		txlog.insert(transaction(uint64(i), 100000, key), false)
+	}
+	if !txlog.grown() {
+		t.Fatal("log not grown after inserts")
+	}
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	live := map[common.Address]types.Transactions{addr: {transaction(6, 100000, key), transaction(7, 100000, key)}}
+	if err := txlog.compact(live, func(common.Address) bool { return true }); err != nil {
+		t.Fatalf("failed to compact log: %v", err)
+	}
+	if txlog.grown() {
+		t.Fatal("log grown right after compaction")
+	}
+	txlog.close()
+
+	txs, locals := replayTxLog(t, path)
+	if len(txs) != 2 || txs[0].Nonce() != 6 || txs[1].Nonce() != 7 || !locals[0] || !locals[1] {
+		t.Fatalf("compacted replay mismatch: have %d txs", len(txs))
+	}
+}
+
+// Tests that remote transactions survive a pool restart through the log, being
+// re-validated against the new head state.
+func TestTxLogRestart(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.NoLocals = true
+	config.TxLog = filepath.Join(t.TempDir(), "txlog")
+
+	pool := New(config, blockchain)
+	pool.Init(new(big.Int).SetUint64(config.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver())
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+
+	for i := uint64(0); i < 3; i++ {
+		if err := pool.addRemoteSync(transaction(i, 100000, key)); err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	if err := pool.addRemoteSync(transaction(5, 100000, key)); err != nil {
+		t.Fatalf("failed to add queued remote transaction: %v", err)
+	}
+	pool.Close()
+
+	// Include the first transaction, restart and check the rest is restored
+	statedb.SetNonce(addr, 1)
+	blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	pool = New(config, blockchain)
+	pool.Init(new(big.Int).SetUint64(config.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	if pending, queued := pool.Stats(); pending != 2 || queued != 1 {
+		t.Fatalf("restored pool mismatch: have %d pending, %d queued, want 2, 1", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// senderAccount is the nonce and balance of a transaction sender in the head
+// state of the pool.
+type senderAccount struct {
+	nonce   uint64
+	balance *big.Int
+}
+
+// accountShard is the part of the account cache owning a subset of senders.
+// Each shard reads through its own state database, so shards never contend.
+type accountShard struct {
+	lock     sync.Mutex
+	state    *state.StateDB // Lazily opened view of the head state
+	accounts map[common.Address]senderAccount
+}
+
+// accountCache is a sender-sharded cache of the nonces and balances in the
+// head state of the pool. It implements txpool.StateReader and is safe for
+// concurrent use, which allows the state-dependent checks of transactions
+// from different senders to run in parallel.
+type accountCache struct {
+	chain  BlockChain
+	root   common.Hash
+	shards []*accountShard
+}
+
+// newAccountCache creates an empty account cache over the state with the
+// given root.
+func newAccountCache(chain BlockChain, root common.Hash, shards int) *accountCache {
+	cache := &accountCache{
+		chain:  chain,
+		root:   root,
+		shards: make([]*accountShard, shards),
+	}
+	for i := range cache.shards {
+		cache.shards[i] = &accountShard{accounts: make(map[common.Address]senderAccount)}
+	}
+	return cache
+}
+
+// index returns the shard owning the given sender. Addresses are hashes, so
+// their leading bytes are uniformly distributed.
+func (c *accountCache) index(addr common.Address) int {
+	return int(binary.BigEndian.Uint32(addr[:4]) % uint32(len(c.shards)))
+}
+
+// account retrieves the nonce and balance of an address, reading it from the
+// state on the first access.
+func (c *accountCache) account(addr common.Address) senderAccount {
+	shard := c.shards[c.index(addr)]
+
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+
+	if acc, ok := shard.accounts[addr]; ok {
+		return acc
+	}
+	if shard.state == nil {
+		statedb, err := c.chain.StateAt(c.root)
+		if err != nil {
+			log.Error("Failed to open txpool validation state", "root", c.root, "err", err)
+			return senderAccount{balance: new(big.Int)}
+		}
+		shard.state = statedb
+	}
+	acc := senderAccount{
+		nonce:   shard.state.GetNonce(addr),
+		balance: shard.state.GetBalance(addr),
+	}
+	shard.accounts[addr] = acc
+	return acc
+}
+
+// GetNonce implements txpool.StateReader.
+func (c *accountCache) GetNonce(addr common.Address) uint64 {
+	return c.account(addr).nonce
+}
+
+// GetBalance implements txpool.StateReader.
+func (c *accountCache) GetBalance(addr common.Address) *big.Int {
+	return c.account(addr).balance
+}
+
+// stateReader returns the view of the head state transactions are validated
+// against: the sharded account cache if enabled, the head state otherwise.
+func (pool *LegacyPool) stateReader() txpool.StateReader {
+	if pool.accounts != nil {
+		return pool.accounts
+	}
+	return pool.currentState
+}
+
+// resetAccounts drops the cached sender accounts after a head change.
+func (pool *LegacyPool) resetAccounts(root common.Hash) {
+	if pool.config.ValidationShards > 0 {
+		pool.accounts = newAccountCache(pool.chain, root, pool.config.ValidationShards)
+	}
+}
+
+// prevalidate runs the sender-local state checks (nonce and balance) of a batch
+// of transactions concurrently, one goroutine per sender shard. Only checks
+// which cannot be affected by other pooled transactions are done here, the
+// full validation is repeated when inserting under the pool lock, but by then
+// all the state reads are served from memory.
+//
+// The returned slice holds an error for every transaction failing the checks.
+func (pool *LegacyPool) prevalidate(txs []*types.Transaction) []error {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var (
+		accounts = pool.accounts
+		groups   = make([][]int, len(accounts.shards))
+		errs     = make([]error, len(txs))
+	)
+	for i, tx := range txs {
+		from, _ := types.Sender(pool.signer, tx) // already validated in validateTxBasics
+		idx := accounts.index(from)
+		groups[idx] = append(groups[idx], i)
+	}
+	check := func(group []int) {
+		for _, i := range group {
+			var (
+				tx      = txs[i]
+				from, _ = types.Sender(pool.signer, tx)
+				acc     = accounts.account(from)
+			)
+			if acc.nonce > tx.Nonce() {
+				errs[i] = fmt.Errorf("%w: next nonce %v, tx nonce %v", core.ErrNonceTooLow, acc.nonce, tx.Nonce())
+				continue
+			}
+			// The rollup cost function caches without synchronisation, leave
+			// it to the full validation under the pool lock
+			if cost := tx.Cost(); acc.balance.Cmp(cost) < 0 {
+				errs[i] = fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, acc.balance, cost, new(big.Int).Sub(cost, acc.balance))
+			}
+		}
+	}
+	var pend sync.WaitGroup
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		pend.Add(1)
+		go func(group []int) {
+			defer pend.Done()
+			check(group)
+		}(group)
+	}
+	pend.Wait()
+	return errs
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// committedTestChain is a test blockchain whose head state is committed to a
+// database, handing out a fresh state view on every request as the sharded
+// validation needs.
+type committedTestChain struct {
+	*testBlockChain
+	db   state.Database
+	root common.Hash
+}
+
+func (bc *committedTestChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return state.New(bc.root, bc.db, nil)
+}
+
+// setupShardedPool creates a pool validating transactions over the given number
+// of sender shards, with the given keys funded and nonces set in its head state.
+func setupShardedPool(config Config, shards int, keys []*ecdsa.PrivateKey, nonce uint64) *LegacyPool {
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(types.EmptyRootHash, db, nil)
+	for _, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		statedb.AddBalance(addr, big.NewInt(1000000000))
+		statedb.SetNonce(addr, nonce)
+	}
+	root, err := statedb.Commit(0, false)
+	if err != nil {
+		panic(err)
+	}
+	blockchain := &committedTestChain{
+		testBlockChain: newTestBlockChain(params.TestChainConfig, 10000000, nil, new(event.Feed)),
+		db:             db,
+		root:           root,
+	}
+	config.ValidationShards = shards
+
+	pool := New(config, blockchain)
+	if err := pool.Init(new(big.Int).SetUint64(config.PriceLimit), blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		panic(err)
+	}
+	<-pool.initDoneCh
+	return pool
+}
+
+// Tests that the sharded validation rejects transactions failing the sender
+// state checks and accepts the rest, keeping errors in input order.
+func TestShardedValidation(t *testing.T) {
+	t.Parallel()
+
+	funded := make([]*ecdsa.PrivateKey, 8)
+	for i := range funded {
+		funded[i], _ = crypto.GenerateKey()
+	}
+	pool := setupShardedPool(testTxPoolConfig, 4, funded, 1)
+	defer pool.Close()
+
+	unfunded, _ := crypto.GenerateKey()
+
+	var txs []*types.Transaction
+	for _, key := range funded {
+		txs = append(txs, transaction(1, 100000, key))
+	}
+	txs = append(txs, transaction(0, 100000, funded[0])) // Nonce too low
+	txs = append(txs, transaction(0, 100000, unfunded))  // Insufficient funds
+	txs = append(txs, transaction(2, 100000, funded[1])) // Valid follow-up
+
+	errs := pool.Add(txs, false, true)
+	for i := 0; i < len(funded); i++ {
+		if errs[i] != nil {
+			t.Errorf("tx %d: unexpected error: %v", i, errs[i])
+		}
+	}
+	if !errors.Is(errs[len(funded)], core.ErrNonceTooLow) {
+		t.Errorf("stale tx error mismatch: have %v, want %v", errs[len(funded)], core.ErrNonceTooLow)
+	}
+	if !errors.Is(errs[len(funded)+1], core.ErrInsufficientFunds) {
+		t.Errorf("unfunded tx error mismatch: have %v, want %v", errs[len(funded)+1], core.ErrInsufficientFunds)
+	}
+	if errs[len(funded)+2] != nil {
+		t.Errorf("follow-up tx: unexpected error: %v", errs[len(funded)+2])
+	}
+	if pending, queued := pool.Stats(); pending != len(funded)+1 || queued != 0 {
+		t.Errorf("pool stats mismatch: have %d pending, %d queued, want %d, 0", pending, queued, len(funded)+1)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Benchmarks the ingestion of transfers from many senders, arriving in network
+// sized batches from concurrent peers, with varying validation shard counts.
+func BenchmarkShardedInsert(b *testing.B) {
+	const senders = 4096
+
+	keys := make([]*ecdsa.PrivateKey, senders)
+	txs := make([]*types.Transaction, senders)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		txs[i] = transaction(0, 100000, keys[i])
+		types.Sender(types.HomesteadSigner{}, txs[i]) // Warm the sender cache
+	}
+	config := testTxPoolConfig
+	config.GlobalSlots = 2 * senders
+	config.GlobalQueue = 2 * senders
+
+	for _, shards := range []int{0, 1, 4, 16} {
+		b.Run(fmt.Sprintf("shards-%d", shards), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				pool := setupShardedPool(config, shards, keys, 0)
+				b.StartTimer()
+
+				var (
+					pend    sync.WaitGroup
+					batches = make(chan []*types.Transaction)
+					workers = runtime.GOMAXPROCS(0)
+				)
+				for w := 0; w < workers; w++ {
+					pend.Add(1)
+					go func() {
+						defer pend.Done()
+						for batch := range batches {
+							pool.Add(batch, false, false)
+						}
+					}()
+				}
+				for j := 0; j < len(txs); j += 256 {
+					batches <- txs[j:min(j+256, len(txs))]
+				}
+				close(batches)
+				pend.Wait()
+
+				b.StopTimer()
+				pool.Close()
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(b.N*senders)/b.Elapsed().Seconds(), "tx/s")
+		})
+	}
+}
@@ -319,6 +319,29 @@ func (p *TxPool) Pending(enforceTips bool) map[common.Address][]*LazyTransaction
 	return txs
 }
 
+// AddBundle enqueues a bundle into the first subpool tracking bundles, if it
+// is valid.
+func (p *TxPool) AddBundle(bundle *Bundle) error {
+	for _, subpool := range p.subpools {
+		if bundles, ok := subpool.(BundleSubPool); ok {
+			return bundles.AddBundle(bundle)
+		}
+	}
+	return ErrBundlesNotSupported
+}
+
+// Bundles retrieves the bundles includable into a block with the given number
+// and timestamp from all the subpools tracking bundles.
+func (p *TxPool) Bundles(number uint64, time uint64) []*Bundle {
+	var bundles []*Bundle
+	for _, subpool := range p.subpools {
+		if pool, ok := subpool.(BundleSubPool); ok {
+			bundles = append(bundles, pool.Bundles(number, time)...)
+		}
+	}
+	return bundles
+}
+
 // SubscribeTransactions registers a subscription for new transaction events,
 // supporting feeding only newly seen or also resurrected transactions.
 func (p *TxPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
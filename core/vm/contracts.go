@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -147,8 +148,19 @@ func init() {
 	}
 }
 
-// ActivePrecompiles returns the precompiles enabled with the current configuration.
+// ActivePrecompiles returns the precompiles enabled with the current configuration,
+// including the registered custom precompiles activated by the chain config.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	builtins := activeBuiltinPrecompiles(rules)
+	custom := activeCustomPrecompiles(rules)
+	if len(custom) == 0 {
+		return builtins
+	}
+	return append(slices.Clone(builtins), custom...)
+}
+
+// activeBuiltinPrecompiles returns the builtin precompiles of the active fork.
+func activeBuiltinPrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsCancun:
 		return PrecompiledAddressesCancun
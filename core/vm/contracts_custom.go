@@ -0,0 +1,179 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// errPrecompileNeedsEnvironment is returned if a stateful precompile is run
+	// through the stateless PrecompiledContract interface.
+	errPrecompileNeedsEnvironment = errors.New("stateful precompile requires an environment")
+
+	// customPrecompiles is the registry of embedder supplied precompiles. A
+	// registered contract is only reachable once the chain config schedules
+	// its address in ChainConfig.Precompiles.
+	customPrecompiles   = make(map[common.Address]StatefulPrecompiledContract)
+	customPrecompilesMu sync.RWMutex
+)
+
+// PrecompileEnvironment is the execution context handed to a stateful
+// precompiled contract.
+type PrecompileEnvironment struct {
+	StateDB  StateDB        // State of the executing transaction
+	Context  BlockContext   // Block the transaction is executed in
+	Origin   common.Address // Transaction sender
+	Caller   common.Address // Immediate caller of the precompile
+	Address  common.Address // Address the precompile is invoked at
+	Value    *big.Int       // Value transferred along with the call, nil for delegate and static calls
+	ReadOnly bool           // Whether state modifications are forbidden
+}
+
+// StatefulPrecompiledContract is the interface for native Go contracts that
+// an embedding chain registers at custom addresses. Unlike PrecompiledContract
+// it is handed the surrounding execution environment and may modify state
+// unless the environment is read-only.
+type StatefulPrecompiledContract interface {
+	RequiredGas(input []byte) uint64                              // RequiredGas calculates the contract gas use
+	Run(env *PrecompileEnvironment, input []byte) ([]byte, error) // Run runs the precompiled contract
+}
+
+// RegisterPrecompile registers a stateful precompiled contract at addr. It
+// fails if the address is taken by a builtin precompile of any fork or by
+// another registered contract.
+func RegisterPrecompile(addr common.Address, p StatefulPrecompiledContract) error {
+	if p == nil {
+		return errors.New("nil precompile")
+	}
+	for _, builtins := range []map[common.Address]PrecompiledContract{
+		PrecompiledContractsHomestead, PrecompiledContractsByzantium,
+		PrecompiledContractsIstanbul, PrecompiledContractsBerlin,
+		PrecompiledContractsCancun, PrecompiledContractsBLS,
+	} {
+		if _, ok := builtins[addr]; ok {
+			return fmt.Errorf("address %v is reserved for a builtin precompile", addr)
+		}
+	}
+	customPrecompilesMu.Lock()
+	defer customPrecompilesMu.Unlock()
+
+	if _, ok := customPrecompiles[addr]; ok {
+		return fmt.Errorf("precompile already registered at %v", addr)
+	}
+	customPrecompiles[addr] = p
+	return nil
+}
+
+// UnregisterPrecompile removes the stateful precompiled contract at addr, if any.
+func UnregisterPrecompile(addr common.Address) {
+	customPrecompilesMu.Lock()
+	defer customPrecompilesMu.Unlock()
+
+	delete(customPrecompiles, addr)
+}
+
+// RegisteredPrecompile returns the stateful precompiled contract registered
+// at addr, regardless of its activation.
+func RegisteredPrecompile(addr common.Address) (StatefulPrecompiledContract, bool) {
+	customPrecompilesMu.RLock()
+	defer customPrecompilesMu.RUnlock()
+
+	p, ok := customPrecompiles[addr]
+	return p, ok
+}
+
+// activePrecompile returns the registered precompile at addr if it's activated
+// by the given rules.
+func activePrecompile(rules params.Rules, addr common.Address) (StatefulPrecompiledContract, bool) {
+	for _, active := range rules.CustomPrecompiles {
+		if active == addr {
+			return RegisteredPrecompile(addr)
+		}
+	}
+	return nil, false
+}
+
+// activeCustomPrecompiles returns the addresses of the registered precompiles
+// activated by the given rules.
+func activeCustomPrecompiles(rules params.Rules) []common.Address {
+	var addrs []common.Address
+	for _, addr := range rules.CustomPrecompiles {
+		if _, ok := RegisteredPrecompile(addr); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// statefulPrecompile adapts a stateful precompile to the PrecompiledContract
+// interface so it can be resolved alongside the builtin ones. It must be run
+// through RunStatefulPrecompiledContract.
+type statefulPrecompile struct {
+	contract StatefulPrecompiledContract
+}
+
+func (p *statefulPrecompile) RequiredGas(input []byte) uint64 {
+	return p.contract.RequiredGas(input)
+}
+
+func (p *statefulPrecompile) Run(input []byte) ([]byte, error) {
+	return nil, errPrecompileNeedsEnvironment
+}
+
+// RunStatefulPrecompiledContract runs and evaluates the output of a stateful
+// precompiled contract. It returns
+// - the returned bytes,
+// - the _remaining_ gas,
+// - any error that occurred
+func RunStatefulPrecompiledContract(p StatefulPrecompiledContract, env *PrecompileEnvironment, input []byte, suppliedGas uint64) (ret []byte, remainingGas uint64, err error) {
+	gasCost := p.RequiredGas(input)
+	if suppliedGas < gasCost {
+		return nil, 0, ErrOutOfGas
+	}
+	suppliedGas -= gasCost
+	output, err := p.Run(env, input)
+	return output, suppliedGas, err
+}
+
+// runPrecompile runs the precompile p invoked at addr, handing stateful
+// precompiles the current execution environment.
+func (evm *EVM) runPrecompile(p PrecompiledContract, caller ContractRef, addr common.Address, input []byte, gas uint64, value *big.Int, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if evm.gasLedger != nil {
+		defer func() { evm.gasLedger.Precompiles += gas - remainingGas }()
+	}
+	sp, ok := p.(*statefulPrecompile)
+	if !ok {
+		return RunPrecompiledContract(p, input, gas)
+	}
+	env := &PrecompileEnvironment{
+		StateDB:  evm.StateDB,
+		Context:  evm.Context,
+		Origin:   evm.Origin,
+		Caller:   caller.Address(),
+		Address:  addr,
+		Value:    value,
+		ReadOnly: readOnly || evm.interpreter.readOnly,
+	}
+	return RunStatefulPrecompiledContract(sp.contract, env, input, gas)
+}
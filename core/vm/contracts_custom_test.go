@@ -0,0 +1,99 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// storePrecompile stores its input at slot zero of its own account.
+type storePrecompile struct{}
+
+func (storePrecompile) RequiredGas(input []byte) uint64 { return 100 }
+
+func (storePrecompile) Run(env *PrecompileEnvironment, input []byte) ([]byte, error) {
+	if env.ReadOnly {
+		return nil, ErrWriteProtection
+	}
+	env.StateDB.SetState(env.Address, common.Hash{}, common.BytesToHash(input))
+	return env.Caller.Bytes(), nil
+}
+
+func TestCustomPrecompile(t *testing.T) {
+	var (
+		addr   = common.HexToAddress("0x0100")
+		caller = common.HexToAddress("0xc0ffee")
+	)
+	if err := RegisterPrecompile(addr, storePrecompile{}); err != nil {
+		t.Fatalf("failed to register precompile: %v", err)
+	}
+	defer UnregisterPrecompile(addr)
+
+	if err := RegisterPrecompile(addr, storePrecompile{}); err == nil {
+		t.Fatal("duplicate registration succeeded")
+	}
+	if err := RegisterPrecompile(common.BytesToAddress([]byte{1}), storePrecompile{}); err == nil {
+		t.Fatal("registration over builtin precompile succeeded")
+	}
+	config := *params.AllEthashProtocolChanges
+	config.Precompiles = map[common.Address]*params.PrecompileActivation{
+		addr: {Block: big.NewInt(10)},
+	}
+	if slices.Contains(ActivePrecompiles(config.Rules(big.NewInt(9), false, 0)), addr) {
+		t.Fatal("precompile active before its activation block")
+	}
+	if !slices.Contains(ActivePrecompiles(config.Rules(big.NewInt(10), false, 0)), addr) {
+		t.Fatal("precompile inactive after its activation block")
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		BlockNumber: big.NewInt(10),
+	}
+	vmenv := NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+	vmenv.ArcologyNetworkAPIs.APIs = NoopArcologyAPIs{}
+
+	ret, gas, err := vmenv.Call(AccountRef(caller), addr, []byte{0x2a}, 1000, new(big.Int))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if gas != 900 {
+		t.Errorf("gas mismatch: have %d, want %d", gas, 900)
+	}
+	if common.BytesToAddress(ret) != caller {
+		t.Errorf("caller mismatch: have %x, want %x", ret, caller)
+	}
+	if have := statedb.GetState(addr, common.Hash{}); have != common.BytesToHash([]byte{0x2a}) {
+		t.Errorf("storage mismatch: have %x", have)
+	}
+	if _, _, err := vmenv.StaticCall(AccountRef(caller), addr, []byte{0x2b}, 1000); err != ErrWriteProtection {
+		t.Errorf("static call error mismatch: have %v, want %v", err, ErrWriteProtection)
+	}
+	if _, _, err := vmenv.Call(AccountRef(caller), addr, nil, 99, new(big.Int)); err != ErrOutOfGas {
+		t.Errorf("underpriced call error mismatch: have %v, want %v", err, ErrOutOfGas)
+	}
+}
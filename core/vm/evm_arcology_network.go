@@ -19,6 +19,28 @@ type ArcologyAPIRouterInterface interface {
 	Call(caller, callee [20]byte, input []byte, origin [20]byte, nonce uint64, blockhash common.Hash) (bool, []byte, bool, int64)
 }
 
+// NoopArcologyAPIs is an Arcology API router intercepting no calls, so all of
+// them are executed by the EVM itself.
+type NoopArcologyAPIs struct{}
+
+// Call implements ArcologyAPIRouterInterface.
+func (NoopArcologyAPIs) Call(caller, callee [20]byte, input []byte, origin [20]byte, nonce uint64, blockhash common.Hash) (bool, []byte, bool, int64) {
+	return false, nil, false, 0
+}
+
+// ArcologyAccessReporter is an optional interface for Arcology API routers which
+// can tell the state an intercepted call accessed. The items are opaque keys,
+// unique within the called API.
+type ArcologyAccessReporter interface {
+	Accesses(caller, callee [20]byte, input []byte) (reads [][]byte, writes [][]byte)
+}
+
+// ArcologyCallLogger is an optional interface for EVMLogger implementations
+// which want to observe the calls redirected to the Arcology APIs.
+type ArcologyCallLogger interface {
+	CaptureArcologyCall(caller, callee common.Address, input []byte, gas, leftOverGas uint64, output []byte, err error)
+}
+
 type ArcologyNetwork struct {
 	evm         *EVM
 	CallContext *ScopeContext              // only available at run time
@@ -32,8 +54,19 @@ func NewArcologyNetwork(evm *EVM) *ArcologyNetwork {
 	}
 }
 
-// Redirect to Arcology API intead
+// Redirect to Arcology API intead. Without a router installed, all the calls are
+// executed by the EVM itself.
 func (this ArcologyNetwork) Call(callerContract ContractRef, addr common.Address, input []byte, gas uint64) (called bool, ret []byte, leftOverGas uint64, err error) {
+	if this.APIs == nil {
+		return false, nil, gas, nil
+	}
+	if tracer, ok := this.evm.Config.Tracer.(ArcologyCallLogger); ok {
+		defer func() {
+			if called {
+				tracer.CaptureArcologyCall(callerContract.Address(), addr, input, gas, leftOverGas, ret, err)
+			}
+		}()
+	}
 	if called, ret, ok, gasUsed := this.APIs.Call(
 		callerContract.Address(),
 		addr,
@@ -42,6 +75,7 @@ func (this ArcologyNetwork) Call(callerContract ContractRef, addr common.Address
 		this.evm.StateDB.GetNonce(this.evm.Origin),
 		this.evm.Context.GetHash(new(big.Int).Sub(this.evm.Context.BlockNumber, big1).Uint64()),
 	); called {
+		this.evm.auditExternalCall(addr, gas, gasUsed)
 		if gasUsed < 0 {
 			leftOverGas = gas + uint64(gasUsed*-1)
 		} else {
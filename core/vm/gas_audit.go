@@ -0,0 +1,171 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kinds of gas accounting violations detected by the gas audit.
+const (
+	// GasViolationLeftOverExceedsProvided is reported if a call frame hands
+	// back more gas than it was given, e.g. an Arcology API reporting a
+	// negative gas usage.
+	GasViolationLeftOverExceedsProvided = "leftover-exceeds-provided"
+
+	// GasViolationExternalOvercharge is reported if an Arcology API charges
+	// more gas than was provided to the call.
+	GasViolationExternalOvercharge = "external-overcharge"
+
+	// GasViolationRefundCap is reported if the refund applied at the end of
+	// the transaction exceeds the cap of the active fork.
+	GasViolationRefundCap = "refund-cap-exceeded"
+
+	// GasViolationGasUsedExceedsLimit is reported if a transaction uses more
+	// gas than its gas limit.
+	GasViolationGasUsedExceedsLimit = "gas-used-exceeds-limit"
+)
+
+// GasAuditLogger is an optional interface for EVMLogger implementations which
+// want to receive the gas ledger of every transaction executed with gas
+// auditing enabled.
+type GasAuditLogger interface {
+	CaptureGasAudit(ledger *GasLedger)
+}
+
+// GasViolation is a single breach of the gas accounting invariants.
+type GasViolation struct {
+	Kind     string         `json:"kind"`
+	Depth    int            `json:"depth"`
+	Address  common.Address `json:"address"`
+	Provided uint64         `json:"provided"`
+	Returned uint64         `json:"returned"`
+	Detail   string         `json:"detail"`
+}
+
+// GasLedger is the per-transaction record of where gas was charged and
+// returned, collected when Config.GasAudit is set.
+type GasLedger struct {
+	GasLimit        uint64 `json:"gasLimit"`        // Gas purchased by the transaction
+	Intrinsic       uint64 `json:"intrinsic"`       // Intrinsic gas of the transaction
+	Static          uint64 `json:"static"`          // Constant gas of executed opcodes
+	Dynamic         uint64 `json:"dynamic"`         // Dynamic gas of executed opcodes, excluding memory expansion
+	MemoryExpansion uint64 `json:"memoryExpansion"` // Memory expansion part of the dynamic gas
+	Precompiles     uint64 `json:"precompiles"`     // Gas charged by precompiled contracts
+	ExternalCharged uint64 `json:"externalCharged"` // Gas charged by Arcology API calls
+	ExternalRefund  uint64 `json:"externalRefund"`  // Gas granted back by Arcology API calls
+	RefundCounter   uint64 `json:"refundCounter"`   // Refund counter of the state at the end of the transaction
+	RefundCap       uint64 `json:"refundCap"`       // Maximum refund allowed by the active fork
+	RefundApplied   uint64 `json:"refundApplied"`   // Refund actually returned to the sender
+	GasUsed         uint64 `json:"gasUsed"`         // Gas used by the transaction after refunds
+
+	Violations []GasViolation `json:"violations,omitempty"`
+}
+
+// Failed returns whether any violation was recorded.
+func (l *GasLedger) Failed() bool {
+	return len(l.Violations) > 0
+}
+
+// Copy returns a deep copy of the ledger.
+func (l *GasLedger) Copy() *GasLedger {
+	cpy := *l
+	cpy.Violations = slices.Clone(l.Violations)
+	return &cpy
+}
+
+// violate records a violation of kind in the ledger.
+func (l *GasLedger) violate(kind string, depth int, addr common.Address, provided, returned uint64, format string, args ...interface{}) {
+	l.Violations = append(l.Violations, GasViolation{
+		Kind:     kind,
+		Depth:    depth,
+		Address:  addr,
+		Provided: provided,
+		Returned: returned,
+		Detail:   fmt.Sprintf(format, args...),
+	})
+}
+
+// GasLedger returns the gas ledger of the current transaction, or nil if gas
+// auditing is disabled.
+func (evm *EVM) GasLedger() *GasLedger {
+	return evm.gasLedger
+}
+
+// BeginGasAudit resets the gas ledger for a new transaction. It is a no-op if
+// gas auditing is disabled.
+func (evm *EVM) BeginGasAudit(gasLimit, intrinsic uint64) {
+	if evm.gasLedger == nil {
+		return
+	}
+	*evm.gasLedger = GasLedger{GasLimit: gasLimit, Intrinsic: intrinsic}
+}
+
+// EndGasAudit checks the transaction level invariants of the gas ledger and
+// delivers it to the tracer, if that implements GasAuditLogger. It is a no-op
+// if gas auditing is disabled.
+func (evm *EVM) EndGasAudit(gasUsed, refund, refundQuotient uint64) {
+	ledger := evm.gasLedger
+	if ledger == nil {
+		return
+	}
+	ledger.GasUsed = gasUsed
+	ledger.RefundApplied = refund
+	ledger.RefundCounter = evm.StateDB.GetRefund()
+	if refundQuotient > 0 {
+		ledger.RefundCap = (gasUsed + refund) / refundQuotient
+	}
+	if refund > ledger.RefundCap {
+		ledger.violate(GasViolationRefundCap, 0, common.Address{}, ledger.RefundCap, refund,
+			"refund %d exceeds cap %d", refund, ledger.RefundCap)
+	}
+	if refund > ledger.RefundCounter {
+		ledger.violate(GasViolationRefundCap, 0, common.Address{}, ledger.RefundCounter, refund,
+			"refund %d exceeds refund counter %d", refund, ledger.RefundCounter)
+	}
+	if gasUsed > ledger.GasLimit {
+		ledger.violate(GasViolationGasUsedExceedsLimit, 0, common.Address{}, ledger.GasLimit, gasUsed,
+			"gas used %d exceeds gas limit %d", gasUsed, ledger.GasLimit)
+	}
+	if tracer, ok := evm.Config.Tracer.(GasAuditLogger); ok {
+		tracer.CaptureGasAudit(ledger.Copy())
+	}
+}
+
+// auditExternalCall records the gas usage reported by an Arcology API call
+// and flags usages which break the gas provided to the call.
+func (evm *EVM) auditExternalCall(addr common.Address, provided uint64, gasUsed int64) {
+	ledger := evm.gasLedger
+	if ledger == nil {
+		return
+	}
+	if gasUsed < 0 {
+		refund := uint64(-gasUsed)
+		ledger.ExternalRefund += refund
+		ledger.violate(GasViolationLeftOverExceedsProvided, evm.depth, addr, provided, provided+refund,
+			"external call returned %d gas more than provided", refund)
+		return
+	}
+	ledger.ExternalCharged += uint64(gasUsed)
+	if uint64(gasUsed) > provided {
+		ledger.violate(GasViolationExternalOvercharge, evm.depth, addr, provided, 0,
+			"external call charged %d gas, only %d provided", gasUsed, provided)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// refundingArcologyAPIs is an Arcology API router that intercepts calls to a
+// single address and reports a fixed gas usage.
+type refundingArcologyAPIs struct {
+	target  common.Address
+	gasUsed int64
+}
+
+func (r refundingArcologyAPIs) Call(caller, callee [20]byte, input []byte, origin [20]byte, nonce uint64, blockhash common.Hash) (bool, []byte, bool, int64) {
+	if common.Address(callee) != r.target {
+		return false, nil, false, 0
+	}
+	return true, nil, true, r.gasUsed
+}
+
+// auditTracer collects the gas ledgers delivered to it.
+type auditTracer struct {
+	ledgers []*GasLedger
+}
+
+func (t *auditTracer) CaptureTxStart(gasLimit uint64) {}
+func (t *auditTracer) CaptureTxEnd(restGas uint64)    {}
+func (t *auditTracer) CaptureStart(env *EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+}
+func (t *auditTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+func (t *auditTracer) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+func (t *auditTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+func (t *auditTracer) CaptureState(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, rData []byte, depth int, err error) {
+}
+func (t *auditTracer) CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error) {
+}
+
+func (t *auditTracer) CaptureGasAudit(ledger *GasLedger) {
+	t.ledgers = append(t.ledgers, ledger)
+}
+
+func newAuditEVM(t *testing.T, apis ArcologyAPIRouterInterface, tracer EVMLogger) (*EVM, *state.StateDB) {
+	t.Helper()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		BlockNumber: big.NewInt(1),
+	}
+	vmenv := NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{GasAudit: true, Tracer: tracer})
+	vmenv.ArcologyNetworkAPIs.APIs = apis
+	return vmenv, statedb
+}
+
+func TestGasAuditOpcodes(t *testing.T) {
+	var (
+		vmenv, statedb = newAuditEVM(t, NoopArcologyAPIs{}, nil)
+		addr           = common.HexToAddress("0xc0de")
+	)
+	// PUSH1 0x01, PUSH1 0x00, MSTORE, STOP
+	statedb.SetCode(addr, []byte{byte(PUSH1), 0x01, byte(PUSH1), 0x00, byte(MSTORE), byte(STOP)})
+
+	vmenv.BeginGasAudit(100000, 21000)
+	if _, _, err := vmenv.Call(AccountRef(common.Address{}), addr, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	ledger := vmenv.GasLedger()
+	if want := 3 * GasFastestStep; ledger.Static != want {
+		t.Errorf("static gas mismatch: have %d, want %d", ledger.Static, want)
+	}
+	if ledger.MemoryExpansion != 3 {
+		t.Errorf("memory expansion gas mismatch: have %d, want %d", ledger.MemoryExpansion, 3)
+	}
+	if ledger.Failed() {
+		t.Errorf("unexpected violations: %v", ledger.Violations)
+	}
+}
+
+func TestGasAuditExternalRefund(t *testing.T) {
+	var (
+		target   = common.HexToAddress("0xa0")
+		tracer   = new(auditTracer)
+		vmenv, _ = newAuditEVM(t, refundingArcologyAPIs{target: target, gasUsed: -500}, tracer)
+	)
+	vmenv.BeginGasAudit(1000, 0)
+	_, left, err := vmenv.Call(AccountRef(common.Address{}), target, nil, 1000, new(big.Int))
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if left != 1500 {
+		t.Fatalf("leftover gas mismatch: have %d, want %d", left, 1500)
+	}
+	vmenv.EndGasAudit(0, 0, params.RefundQuotientEIP3529)
+
+	if len(tracer.ledgers) != 1 {
+		t.Fatalf("ledger count mismatch: have %d, want 1", len(tracer.ledgers))
+	}
+	ledger := tracer.ledgers[0]
+	if ledger.ExternalRefund != 500 {
+		t.Errorf("external refund mismatch: have %d, want %d", ledger.ExternalRefund, 500)
+	}
+	if len(ledger.Violations) != 1 || ledger.Violations[0].Kind != GasViolationLeftOverExceedsProvided {
+		t.Fatalf("violation mismatch: have %v", ledger.Violations)
+	}
+	if v := ledger.Violations[0]; v.Address != target || v.Provided != 1000 || v.Returned != 1500 {
+		t.Errorf("violation details mismatch: have %+v", v)
+	}
+}
+
+func TestGasAuditRefundCap(t *testing.T) {
+	vmenv, _ := newAuditEVM(t, NoopArcologyAPIs{}, nil)
+
+	vmenv.BeginGasAudit(100000, 21000)
+	vmenv.EndGasAudit(40000, 20000, params.RefundQuotientEIP3529)
+
+	ledger := vmenv.GasLedger()
+	if ledger.RefundCap != 12000 {
+		t.Errorf("refund cap mismatch: have %d, want %d", ledger.RefundCap, 12000)
+	}
+	var kinds []string
+	for _, v := range ledger.Violations {
+		kinds = append(kinds, v.Kind)
+	}
+	// The refund breaches both the fork cap and the (empty) refund counter.
+	if len(kinds) != 2 || kinds[0] != GasViolationRefundCap || kinds[1] != GasViolationRefundCap {
+		t.Errorf("violation mismatch: have %v", kinds)
+	}
+}
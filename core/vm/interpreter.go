@@ -29,6 +29,9 @@ type Config struct {
 	NoBaseFee               bool      // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	EnablePreimageRecording bool      // Enables recording of SHA3/keccak preimages
 	ExtraEips               []int     // Additional EIPS that are to be enabled
+	GasAudit                bool      // Enables the per-transaction gas accounting audit
+
+	ArcologyAPIs ArcologyAPIRouterInterface // Optional router of the Arcology API calls installed into every EVM
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -145,6 +148,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		logged  bool   // deferred EVMLogger should ignore already logged steps
 		res     []byte // result of the opcode execution function
 		debug   = in.evm.Config.Tracer != nil
+		audit   = in.evm.gasLedger
 	)
 
 	in.evm.ArcologyNetworkAPIs.CopyContext(callContext) // For Arcology
@@ -191,6 +195,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		if !contract.UseGas(cost) {
 			return nil, ErrOutOfGas
 		}
+		if audit != nil {
+			audit.Static += cost
+		}
 		if operation.dynamicGas != nil {
 			// All ops with a dynamic memory usage also has a dynamic gas cost.
 			var memorySize uint64
@@ -211,12 +218,20 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			}
 			// Consume the gas and return an error if not enough gas is available.
 			// cost is explicitly set so that the capture state defer method can get the proper cost
-			var dynamicCost uint64
+			var (
+				dynamicCost uint64
+				memoryCost  = mem.lastGasCost
+			)
 			dynamicCost, err = operation.dynamicGas(in.evm, contract, stack, mem, memorySize)
 			cost += dynamicCost // for tracing
 			if err != nil || !contract.UseGas(dynamicCost) {
 				return nil, ErrOutOfGas
 			}
+			if audit != nil {
+				memoryCost = mem.lastGasCost - memoryCost
+				audit.MemoryExpansion += memoryCost
+				audit.Dynamic += dynamicCost - memoryCost
+			}
 			// Do tracing before memory expansion
 			if debug {
 				in.evm.Config.Tracer.CaptureState(pc, op, gasCopy, cost, callContext, in.returnData, in.evm.depth, err)
@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -90,6 +91,7 @@ type Ethereum struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
+	logIndexer        *core.ChainIndexer             // Log indexer operating during block imports, if enabled
 	closeBloomHandler chan struct{}
 
 	APIBackend *EthAPIBackend
@@ -135,8 +137,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
 
-	// Assemble the Ethereum object
-	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/", false)
+	// Assemble the Ethereum object. If the trie nodes are stored separately, the
+	// database allowances are split evenly between the two databases.
+	dbCache, dbHandles := config.DatabaseCache, config.DatabaseHandles
+	if config.ShardedTrieDatabase {
+		dbCache, dbHandles = dbCache/2, dbHandles/2
+	}
+	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", dbCache, dbHandles, config.DatabaseFreezer, "eth/db/chaindata/", false)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +151,16 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	var trieShards *rawdb.ShardedDatabase
+	if config.ShardedTrieDatabase {
+		if scheme != rawdb.HashScheme {
+			return nil, fmt.Errorf("sharded trie database unsupported by the %s scheme", scheme)
+		}
+		trieShards, err = stack.OpenShardedDatabase("triedata", dbCache, dbHandles, "eth/db/triedata/", false)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// Try to recover offline state pruning only in hash-based.
 	if scheme == rawdb.HashScheme {
 		if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb); err != nil {
@@ -209,7 +226,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
 			StateHistory:        config.StateHistory,
+			SnapshotHistory:     config.SnapshotHistory,
 			StateScheme:         scheme,
+			TrieShards:          trieShards,
 		}
 	)
 	// Override the chain config with provided settings.
@@ -242,6 +261,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.LogIndex {
+		eth.logIndexer = core.NewLogIndexer(chainDb, params.LogIndexBlocks, params.BloomConfirms)
+		eth.logIndexer.Start(eth.blockchain)
+	}
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
@@ -251,9 +274,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.TxLog != "" {
+		config.TxPool.TxLog = stack.ResolvePath(config.TxPool.TxLog)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	bundlePool := bundlepool.New(config.BundlePool, eth.blockchain)
 
-	eth.txPool, err = txpool.New(new(big.Int).SetUint64(config.TxPool.PriceLimit), eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(new(big.Int).SetUint64(config.TxPool.PriceLimit), eth.blockchain, []txpool.SubPool{legacyPool, blobPool, bundlePool})
 	if err != nil {
 		return nil, err
 	}
@@ -352,13 +379,26 @@ func makeExtraData(extra []byte) []byte {
 // APIs return the collection of RPC services the ethereum package offers.
 // NOTE, some of these services probably need to be moved to somewhere else.
 func (s *Ethereum) APIs() []rpc.API {
-	apis := ethapi.GetAPIs(s.APIBackend)
-
 	// Append any APIs exposed explicitly by the consensus engine
-	apis = append(apis, s.engine.APIs(s.BlockChain())...)
+	return append(APIs(s), s.engine.APIs(s.BlockChain())...)
+}
 
+// APIs returns the RPC services of the given full node, apart from the ones of
+// its consensus engine. A nil node yields the services without any, only to be
+// inspected for their methods, e.g. to document them.
+func APIs(s *Ethereum) []rpc.API {
+	var (
+		backend       ethapi.Backend
+		downloaderAPI = new(downloader.DownloaderAPI)
+		netAPI        = new(ethapi.NetAPI)
+	)
+	if s != nil {
+		backend = s.APIBackend
+		downloaderAPI = downloader.NewDownloaderAPI(s.handler.downloader, s.eventMux)
+		netAPI = s.netRPCService
+	}
 	// Append all the local APIs and return
-	return append(apis, []rpc.API{
+	return append(ethapi.GetAPIs(backend), []rpc.API{
 		{
 			Namespace: "eth",
 			Service:   NewEthereumAPI(s),
@@ -367,7 +407,10 @@ func (s *Ethereum) APIs() []rpc.API {
 			Service:   NewMinerAPI(s),
 		}, {
 			Namespace: "eth",
-			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.eventMux),
+			Service:   NewBundleAPI(s),
+		}, {
+			Namespace: "eth",
+			Service:   downloaderAPI,
 		}, {
 			Namespace: "admin",
 			Service:   NewAdminAPI(s),
@@ -376,7 +419,7 @@ func (s *Ethereum) APIs() []rpc.API {
 			Service:   NewDebugAPI(s),
 		}, {
 			Namespace: "net",
-			Service:   s.netRPCService,
+			Service:   netAPI,
 		},
 	}...)
 }
@@ -582,6 +625,9 @@ func (s *Ethereum) Stop() error {
 	// Then stop everything else.
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
 	s.txPool.Close()
 	s.miner.Close()
 	s.blockchain.Stop()
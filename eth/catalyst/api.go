@@ -40,14 +40,25 @@ import (
 // Register adds the engine API to the full node.
 func Register(stack *node.Node, backend *eth.Ethereum) error {
 	log.Warn("Engine API enabled", "protocol", "eth")
-	stack.RegisterAPIs([]rpc.API{
+	stack.RegisterAPIs(APIs(backend))
+	return nil
+}
+
+// APIs returns the engine API of the given full node. A nil node yields the
+// service without any, only to be inspected for its methods, e.g. to document
+// it.
+func APIs(backend *eth.Ethereum) []rpc.API {
+	api := new(ConsensusAPI)
+	if backend != nil {
+		api = NewConsensusAPI(backend)
+	}
+	return []rpc.API{
 		{
 			Namespace:     "engine",
-			Service:       NewConsensusAPI(backend),
+			Service:       api,
 			Authenticated: true,
 		},
-	})
-	return nil
+	}
 }
 
 const (
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -27,7 +28,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TxLookupLimit                           uint64                 `toml:",omitempty"`
 		TransactionHistory                      uint64                 `toml:",omitempty"`
 		StateHistory                            uint64                 `toml:",omitempty"`
+		SnapshotHistory                         uint64                 `toml:",omitempty"`
+		LogIndex                                bool                   `toml:",omitempty"`
 		StateScheme                             string                 `toml:",omitempty"`
+		ShardedTrieDatabase                     bool                   `toml:",omitempty"`
 		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
 		LightServ                               int                    `toml:",omitempty"`
 		LightIngress                            int                    `toml:",omitempty"`
@@ -48,6 +52,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Miner                                   miner.Config
 		TxPool                                  legacypool.Config
 		BlobPool                                blobpool.Config
+		BundlePool                              bundlepool.Config
 		GPO                                     gasprice.Config
 		EnablePreimageRecording                 bool
 		DocRoot                                 string `toml:"-"`
@@ -76,7 +81,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.TransactionHistory = c.TransactionHistory
 	enc.StateHistory = c.StateHistory
+	enc.SnapshotHistory = c.SnapshotHistory
+	enc.LogIndex = c.LogIndex
 	enc.StateScheme = c.StateScheme
+	enc.ShardedTrieDatabase = c.ShardedTrieDatabase
 	enc.RequiredBlocks = c.RequiredBlocks
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
@@ -97,6 +105,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
 	enc.BlobPool = c.BlobPool
+	enc.BundlePool = c.BundlePool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.DocRoot = c.DocRoot
@@ -129,7 +138,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TxLookupLimit                           *uint64                `toml:",omitempty"`
 		TransactionHistory                      *uint64                `toml:",omitempty"`
 		StateHistory                            *uint64                `toml:",omitempty"`
+		SnapshotHistory                         *uint64                `toml:",omitempty"`
+		LogIndex                                *bool                  `toml:",omitempty"`
 		StateScheme                             *string                `toml:",omitempty"`
+		ShardedTrieDatabase                     *bool                  `toml:",omitempty"`
 		RequiredBlocks                          map[uint64]common.Hash `toml:"-"`
 		LightServ                               *int                   `toml:",omitempty"`
 		LightIngress                            *int                   `toml:",omitempty"`
@@ -150,6 +162,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Miner                                   *miner.Config
 		TxPool                                  *legacypool.Config
 		BlobPool                                *blobpool.Config
+		BundlePool                              *bundlepool.Config
 		GPO                                     *gasprice.Config
 		EnablePreimageRecording                 *bool
 		DocRoot                                 *string `toml:"-"`
@@ -201,9 +214,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
+	if dec.SnapshotHistory != nil {
+		c.SnapshotHistory = *dec.SnapshotHistory
+	}
+	if dec.LogIndex != nil {
+		c.LogIndex = *dec.LogIndex
+	}
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
+	if dec.ShardedTrieDatabase != nil {
+		c.ShardedTrieDatabase = *dec.ShardedTrieDatabase
+	}
 	if dec.RequiredBlocks != nil {
 		c.RequiredBlocks = dec.RequiredBlocks
 	}
@@ -264,6 +286,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobPool != nil {
 		c.BlobPool = *dec.BlobPool
 	}
+	if dec.BundlePool != nil {
+		c.BundlePool = *dec.BundlePool
+	}
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
@@ -79,6 +79,20 @@ func NewFilterAPI(system *FilterSystem, lightMode bool) *FilterAPI {
 	return api
 }
 
+// APIs returns the log filtering RPC API of the given filter system. A nil system
+// yields the service without any, only to be inspected for its methods, e.g. to
+// document it.
+func APIs(system *FilterSystem, lightMode bool) []rpc.API {
+	api := new(FilterAPI)
+	if system != nil {
+		api = NewFilterAPI(system, lightMode)
+	}
+	return []rpc.API{{
+		Namespace: "eth",
+		Service:   api,
+	}}
+}
+
 // timeoutLoop runs at the interval set by 'timeout' and deletes filters
 // that have not been recently used. It is started when the API is created.
 func (api *FilterAPI) timeoutLoop(timeout time.Duration) {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -198,6 +199,20 @@ func (f *Filter) rangeLogsAsync(ctx context.Context) (chan *types.Log, chan erro
 			size, sections = f.sys.backend.BloomStatus()
 			err            error
 		)
+		// Use the log index first if available, falling back to the bloombits
+		// beyond it. Filters without criteria match all logs, nothing to index.
+		if logindex.Indexable(f.addresses, f.topics) {
+			if size, sections := f.sys.backend.LogIndexStatus(); sections*size > uint64(f.begin) {
+				indexed := sections * size
+				if indexed > end {
+					indexed = end + 1
+				}
+				if err = f.logIndexedLogs(ctx, indexed-1, logChan); err != nil {
+					errChan <- err
+					return
+				}
+			}
+		}
 		if indexed := sections * size; indexed > uint64(f.begin) {
 			if indexed > end {
 				indexed = end + 1
@@ -265,6 +280,55 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 	}
 }
 
+// logIndexedLogs returns the logs matching the filter criteria based on the log
+// index, only retrieving the blocks known to contain matching logs.
+func (f *Filter) logIndexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
+	var (
+		size, _ = f.sys.backend.LogIndexStatus()
+		db      = f.sys.backend.ChainDb()
+	)
+	for section := uint64(f.begin) / size; section <= end/size; section++ {
+		positions, err := logindex.Match(db, section, size, f.addresses, f.topics)
+		if err != nil {
+			return err
+		}
+		for _, pos := range positions {
+			if pos.Block > end {
+				break
+			}
+			// Skip blocks out of range, or already checked for an earlier log
+			if pos.Block < uint64(f.begin) {
+				continue
+			}
+			header, err := f.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(pos.Block))
+			if header == nil || err != nil {
+				return err
+			}
+			found, err := f.checkMatches(ctx, header)
+			if err != nil {
+				return err
+			}
+			for _, log := range found {
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			f.begin = int64(pos.Block) + 1
+		}
+		if next := (section + 1) * size; next <= end {
+			f.begin = int64(next)
+		} else {
+			f.begin = int64(end) + 1
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // unindexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
 func (f *Filter) unindexedLogs(ctx context.Context, end uint64, logChan chan *types.Log) error {
@@ -74,6 +74,10 @@ type Backend interface {
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+
+	// LogIndexStatus returns the section size of the log index and the number of
+	// sections indexed, zero if the index is disabled.
+	LogIndexStatus() (uint64, uint64)
 }
 
 // FilterSystem holds resources shared by all filters.
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/logindex"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -387,3 +388,94 @@ func TestFilters(t *testing.T) {
 		}
 	})
 }
+
+// Tests that the log index returns the same logs as the bloombits and block
+// iteration, also when the filter range extends beyond the indexed sections.
+func TestLogIndexFilters(t *testing.T) {
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(t, db, Config{})
+		addr1        = common.BytesToAddress([]byte("addr1"))
+		addr2        = common.BytesToAddress([]byte("addr2"))
+		topic1       = common.BytesToHash([]byte("topic1"))
+		topic2       = common.BytesToHash([]byte("topic2"))
+
+		gspec = &core.Genesis{
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Config:  params.TestChainConfig,
+		}
+		// Logs of the transactions of the blocks containing any
+		blockLogs = map[int][][]*types.Log{
+			3: {
+				{{Address: addr1, Topics: []common.Hash{topic1}}},
+				{{Address: addr2, Topics: []common.Hash{topic2}}, {Address: addr1}},
+			},
+			10: {{{Address: addr1, Topics: []common.Hash{topic2, topic1}}}},
+			25: {{{Address: addr2, Topics: []common.Hash{topic1, topic2}}}},
+			35: {{{Address: addr1, Topics: []common.Hash{topic1}}}},
+		}
+	)
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 40, func(i int, gen *core.BlockGen) {
+		for j, logs := range blockLogs[i+1] {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = logs
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(j), common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	// The test txs are not properly signed, write the blocks directly
+	gspec.MustCommit(db, trie.NewDatabase(db, trie.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// Index the first two sections of 16 blocks
+	const size = 16
+	for section := uint64(0); section < 2; section++ {
+		builder := logindex.NewBuilder(section, size)
+		for number := section * size; number < (section+1)*size; number++ {
+			builder.AddBlock(number, rawdb.ReadLogs(db, rawdb.ReadCanonicalHash(db, number), number))
+		}
+		builder.Commit(db)
+	}
+	for i, tc := range []struct {
+		begin, end int64
+		addresses  []common.Address
+		topics     [][]common.Hash
+		want       int
+	}{
+		{0, -1, []common.Address{addr1}, nil, 4},
+		{0, -1, []common.Address{addr1, addr2}, nil, 6},
+		{4, 30, []common.Address{addr1, addr2}, nil, 2},
+		{0, -1, []common.Address{addr1}, [][]common.Hash{{topic1}}, 2},
+		{0, -1, []common.Address{addr2}, [][]common.Hash{{topic1}}, 1},
+		{0, -1, nil, [][]common.Hash{{topic2}}, 2},
+		{0, -1, nil, [][]common.Hash{{topic1, topic2}, {topic1, topic2}}, 2},
+		{0, -1, nil, [][]common.Hash{nil, {topic2}}, 1},
+		{0, -1, nil, [][]common.Hash{{topic1}, nil}, 1},
+		{0, -1, []common.Address{addr2}, [][]common.Hash{{topic1}, {topic1}}, 0},
+		{0, -1, nil, nil, 6},
+	} {
+		backend.logIndexSize, backend.logIndexed = 0, 0
+		want, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: unindexed filter failed: %v", i, err)
+		}
+		backend.logIndexSize, backend.logIndexed = size, 2
+		have, err := sys.NewRangeFilter(tc.begin, tc.end, tc.addresses, tc.topics).Logs(context.Background())
+		if err != nil {
+			t.Fatalf("test %d: indexed filter failed: %v", i, err)
+		}
+		if len(have) != tc.want {
+			t.Errorf("test %d: log count mismatch: have %d, want %d", i, len(have), tc.want)
+		}
+		haveJSON, _ := json.Marshal(have)
+		wantJSON, _ := json.Marshal(want)
+		if string(haveJSON) != string(wantJSON) {
+			t.Errorf("test %d: indexed logs mismatch:\nhave %s\nwant %s", i, haveJSON, wantJSON)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package tracers
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -91,7 +92,8 @@ type Backend interface {
 
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
 type API struct {
-	backend Backend
+	backend  Backend
+	rangeDir string // Base directory of the range trace outputs, temp dir if empty
 }
 
 // NewAPI creates a new API definition for the tracing methods of the Ethereum service.
@@ -156,6 +158,10 @@ type TraceConfig struct {
 	// Config specific to given tracer. Note struct logger
 	// config are historically embedded in main object.
 	TracerConfig json.RawMessage
+	// Stream selects the versioned JSON-lines encoding of the struct logger
+	// (see logger.StreamSchema), returning its records as an array. Ignored if
+	// a custom tracer is given.
+	Stream bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -171,6 +177,7 @@ type StdTraceConfig struct {
 	logger.Config
 	Reexec *uint64
 	TxHash common.Hash
+	Stream bool // Dump traces in the versioned JSON-lines schema (see logger.StreamSchema)
 }
 
 // txTraceResult is the result of a single transaction trace.
@@ -762,10 +769,12 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 	var (
 		logConfig logger.Config
 		txHash    common.Hash
+		stream    bool
 	)
 	if config != nil {
 		logConfig = config.Config
 		txHash = config.TxHash
+		stream = config.Stream
 	}
 	logConfig.Debug = true
 
@@ -815,6 +824,9 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 				Tracer:                  logger.NewJSONLogger(&logConfig, writer),
 				EnablePreimageRecording: true,
 			}
+			if stream {
+				vmConf.Tracer = logger.NewStreamLogger(&logConfig, writer)
+			}
 		}
 		// Execute the transaction and flush any traces to disk
 		vmenv := vm.NewEVM(vmctx, txContext, statedb, chainConfig, vmConf)
@@ -972,6 +984,7 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
 	var (
 		tracer    Tracer
+		records   *streamRecords
 		err       error
 		timeout   = defaultTraceTimeout
 		txContext = core.NewEVMTxContext(message)
@@ -986,6 +999,9 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 		if err != nil {
 			return nil, err
 		}
+	} else if config.Stream {
+		records = new(streamRecords)
+		tracer = logger.NewStreamLogger(config.Config, records)
 	}
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
 
@@ -1011,16 +1027,53 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 	if _, err = core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit)); err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
+	if records != nil {
+		if _, err := tracer.GetResult(); err != nil {
+			return nil, err
+		}
+		return records.lines, nil
+	}
 	return tracer.GetResult()
 }
 
+// streamRecords splits the output of the stream logger into its records as
+// they are written. A JSON-RPC response is a single value, so the records are
+// held until the trace ends, but only in their compact encoded form rather than
+// as the struct logs of the default tracer.
+type streamRecords struct {
+	lines   []json.RawMessage
+	partial []byte // Unterminated tail of the last write
+}
+
+// Write implements io.Writer, storing every complete line as a record.
+func (s *streamRecords) Write(p []byte) (int, error) {
+	n := len(p)
+	for {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			s.partial = append(s.partial, p...)
+			return n, nil
+		}
+		if line := append(s.partial, p[:i]...); len(line) > 0 {
+			s.lines = append(s.lines, json.RawMessage(common.CopyBytes(line)))
+		}
+		s.partial, p = s.partial[:0], p[i+1:]
+	}
+}
+
 // APIs return the collection of RPC services the tracer package offers.
 func APIs(backend Backend) []rpc.API {
+	return APIsWithRangeDir(backend, "")
+}
+
+// APIsWithRangeDir returns the RPC services of the tracer package like APIs,
+// with the outputs of debug_traceBlockRange confined to rangeDir.
+func APIsWithRangeDir(backend Backend, rangeDir string) []rpc.API {
 	// Append all the local APIs and return
 	return []rpc.API{
 		{
 			Namespace: "debug",
-			Service:   NewAPI(backend),
+			Service:   &API{backend: backend, rangeDir: rangeDir},
 		},
 	}
 }
@@ -0,0 +1,337 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// defaultRangeChunkSize is the number of consecutive blocks traced by a
+	// worker into a single output file.
+	defaultRangeChunkSize = uint64(1000)
+
+	// rangeCheckpointFile is the name of the file tracking the completed chunks
+	// of a range trace within its output directory.
+	rangeCheckpointFile = "checkpoint.json"
+)
+
+// RangeTraceConfig holds the parameters of a block range trace.
+type RangeTraceConfig struct {
+	TraceConfig
+	Workers   int    // Number of chunks traced concurrently, defaults to the number of CPUs
+	ChunkSize uint64 // Number of blocks per output file, defaults to 1000
+	Dir       string // Output directory relative to the node's range trace directory, a fresh one is created if empty
+}
+
+// RangeTraceResult summarizes a finished block range trace.
+type RangeTraceResult struct {
+	Dir     string         `json:"dir"`     // Directory holding the traces and the checkpoint
+	Files   []string       `json:"files"`   // Trace files of the range, in block order
+	Traced  hexutil.Uint64 `json:"traced"`  // Number of blocks traced by this invocation
+	Resumed hexutil.Uint64 `json:"resumed"` // Number of blocks skipped as already traced
+}
+
+// rangeCheckpoint is the persisted progress of a range trace. A chunk is only
+// recorded once its trace file is complete, so an interrupted trace can be
+// resumed by rerunning the same request against the same directory.
+type rangeCheckpoint struct {
+	Start     uint64   `json:"start"`
+	End       uint64   `json:"end"`
+	ChunkSize uint64   `json:"chunkSize"`
+	Tracer    string   `json:"tracer"`
+	Config    string   `json:"tracerConfig,omitempty"` // Compacted JSON of the tracer config
+	Done      []uint64 `json:"done"`                   // First block numbers of the completed chunks
+}
+
+// TraceBlockRange traces all blocks in the inclusive range [start, end] and
+// writes the results into gzip compressed JSON-lines files, one line per block
+// in the format emitted by TraceChain. The range is split into chunks which are
+// traced concurrently by the configured number of workers, each regenerating
+// the states it needs via StateAtBlock. Progress is checkpointed per chunk into
+// the output directory, an interrupted trace continues where it left off when
+// invoked again with the same directory. The output directory is always
+// resolved within the range trace directory the API was configured with.
+func (api *API) TraceBlockRange(ctx context.Context, start, end rpc.BlockNumber, config *RangeTraceConfig) (*RangeTraceResult, error) {
+	from, err := api.blockByNumber(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockByNumber(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+	if from.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	if from.NumberU64() > to.NumberU64() {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end, start)
+	}
+	if config == nil {
+		config = new(RangeTraceConfig)
+	}
+	var (
+		workers   = config.Workers
+		chunkSize = config.ChunkSize
+		dir       = config.Dir
+		tracer    string
+		tracerCfg string
+	)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if chunkSize == 0 {
+		chunkSize = defaultRangeChunkSize
+	}
+	if config.Tracer != nil {
+		tracer = *config.Tracer
+	}
+	if len(config.TracerConfig) > 0 {
+		compact := new(bytes.Buffer)
+		if err := json.Compact(compact, config.TracerConfig); err != nil {
+			return nil, fmt.Errorf("invalid tracer config: %v", err)
+		}
+		tracerCfg = compact.String()
+	}
+	if dir, err = api.rangeTraceDir(dir); err != nil {
+		return nil, err
+	}
+	checkpoint, err := loadRangeCheckpoint(dir)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		checkpoint = &rangeCheckpoint{Start: from.NumberU64(), End: to.NumberU64(), ChunkSize: chunkSize, Tracer: tracer, Config: tracerCfg}
+	} else if checkpoint.Start != from.NumberU64() || checkpoint.End != to.NumberU64() || checkpoint.ChunkSize != chunkSize || checkpoint.Tracer != tracer || checkpoint.Config != tracerCfg {
+		return nil, fmt.Errorf("checkpoint in %s belongs to a different trace (blocks #%d-#%d, chunk size %d, tracer %q, tracer config %q)",
+			dir, checkpoint.Start, checkpoint.End, checkpoint.ChunkSize, checkpoint.Tracer, checkpoint.Config)
+	}
+	// Split the range into chunks and schedule the ones not yet traced
+	var (
+		result = &RangeTraceResult{Dir: dir}
+		chunks []uint64
+	)
+	for first := checkpoint.Start; first <= checkpoint.End; first += chunkSize {
+		result.Files = append(result.Files, filepath.Join(dir, rangeChunkName(first, checkpoint.chunkEnd(first))))
+		if slices.Contains(checkpoint.Done, first) {
+			result.Resumed += hexutil.Uint64(checkpoint.chunkEnd(first) - first + 1)
+			continue
+		}
+		chunks = append(chunks, first)
+	}
+	// Trace the outstanding chunks concurrently, each chunk on a single worker
+	var (
+		ctxt, cancel = context.WithCancel(ctx)
+		tasks        = make(chan uint64)
+		lock         sync.Mutex // Protects the checkpoint and the result counters
+		failed       error
+		pend         sync.WaitGroup
+	)
+	defer cancel()
+
+	fail := func(err error) {
+		lock.Lock()
+		if failed == nil {
+			failed = err
+		}
+		lock.Unlock()
+		cancel()
+	}
+	for i := 0; i < min(workers, len(chunks)); i++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+			for first := range tasks {
+				last := checkpoint.chunkEnd(first)
+				if err := api.traceRangeChunk(ctxt, dir, first, last, &config.TraceConfig); err != nil {
+					fail(err)
+					return
+				}
+				lock.Lock()
+				checkpoint.Done = append(checkpoint.Done, first)
+				result.Traced += hexutil.Uint64(last - first + 1)
+				err := checkpoint.store(dir)
+				lock.Unlock()
+				if err != nil {
+					fail(err)
+					return
+				}
+				log.Info("Traced block range chunk", "from", first, "to", last)
+			}
+		}()
+	}
+	for _, first := range chunks {
+		select {
+		case tasks <- first:
+		case <-ctxt.Done():
+		}
+		if ctxt.Err() != nil {
+			break
+		}
+	}
+	close(tasks)
+	pend.Wait()
+
+	if failed != nil {
+		return nil, failed
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// traceRangeChunk traces the blocks [first, last] and writes them to the chunk
+// file in dir. The file is written under a temporary name and only moved into
+// place once complete.
+func (api *API) traceRangeChunk(ctx context.Context, dir string, first, last uint64, config *TraceConfig) error {
+	var (
+		name = filepath.Join(dir, rangeChunkName(first, last))
+		temp = name + ".tmp"
+	)
+	file, err := os.Create(temp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp)
+	defer file.Close()
+
+	var (
+		buffer  = bufio.NewWriter(file)
+		zipper  = gzip.NewWriter(buffer)
+		encoder = json.NewEncoder(zipper)
+		start   = time.Now()
+	)
+	for number := first; number <= last; number++ {
+		block, err := api.blockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return err
+		}
+		traces, err := api.traceBlock(ctx, block, config)
+		if err != nil {
+			return fmt.Errorf("failed to trace block #%d: %w", number, err)
+		}
+		res := &blockTraceResult{
+			Block:  hexutil.Uint64(number),
+			Hash:   block.Hash(),
+			Traces: traces,
+		}
+		if err := encoder.Encode(res); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if err := zipper.Close(); err != nil {
+		return err
+	}
+	if err := buffer.Flush(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	log.Debug("Wrote block range traces", "file", name, "elapsed", time.Since(start))
+	return os.Rename(temp, name)
+}
+
+// rangeTraceDir resolves and creates the output directory of a range trace
+// within the configured base directory. An empty name creates a fresh directory,
+// anything else must be a local path not escaping the base directory.
+func (api *API) rangeTraceDir(name string) (string, error) {
+	base := api.rangeDir
+	if base == "" {
+		if name != "" {
+			return "", errors.New("range trace directory not configured")
+		}
+		base = os.TempDir()
+	} else if err := os.MkdirAll(base, 0755); err != nil {
+		return "", err
+	}
+	if name == "" {
+		return os.MkdirTemp(base, "trace-range-")
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid range trace directory %q: must be a relative path within %s", name, base)
+	}
+	dir := filepath.Join(base, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// rangeChunkName returns the file name of the traces of blocks [first, last].
+func rangeChunkName(first, last uint64) string {
+	return fmt.Sprintf("traces-%08d-%08d.jsonl.gz", first, last)
+}
+
+// chunkEnd returns the last block of the chunk starting at first.
+func (c *rangeCheckpoint) chunkEnd(first uint64) uint64 {
+	return min(first+c.ChunkSize-1, c.End)
+}
+
+// loadRangeCheckpoint reads the checkpoint from dir, returning nil if there
+// is none.
+func loadRangeCheckpoint(dir string) (*rangeCheckpoint, error) {
+	blob, err := os.ReadFile(filepath.Join(dir, rangeCheckpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := new(rangeCheckpoint)
+	if err := json.Unmarshal(blob, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+	if checkpoint.ChunkSize == 0 {
+		return nil, errors.New("invalid checkpoint: zero chunk size")
+	}
+	return checkpoint, nil
+}
+
+// store atomically replaces the checkpoint in dir.
+func (c *rangeCheckpoint) store(dir string) error {
+	slices.Sort(c.Done)
+	blob, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, rangeCheckpointFile)
+	if err := os.WriteFile(path+".tmp", blob, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// readRangeChunk decodes the block traces of a range trace output file.
+func readRangeChunk(t *testing.T, path string) []*blockTraceResult {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open trace file: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("failed to decompress trace file: %v", err)
+	}
+	var (
+		results []*blockTraceResult
+		scanner = bufio.NewScanner(reader)
+	)
+	for scanner.Scan() {
+		res := new(blockTraceResult)
+		if err := json.Unmarshal(scanner.Bytes(), res); err != nil {
+			t.Fatalf("failed to decode block trace: %v", err)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func TestTraceBlockRange(t *testing.T) {
+	genesis := &core.Genesis{Config: params.TestChainConfig}
+	backend := newTestBackend(t, 10, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := &API{backend: backend, rangeDir: t.TempDir()}
+
+	var (
+		dir    = filepath.Join(api.rangeDir, "trace")
+		config = &RangeTraceConfig{Workers: 2, ChunkSize: 4, Dir: "trace"}
+	)
+	res, err := api.TraceBlockRange(context.Background(), 1, 10, config)
+	if err != nil {
+		t.Fatalf("failed to trace range: %v", err)
+	}
+	if len(res.Files) != 3 || res.Traced != 10 || res.Resumed != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	next := uint64(1)
+	for _, file := range res.Files {
+		for _, block := range readRangeChunk(t, file) {
+			if uint64(block.Block) != next {
+				t.Fatalf("unexpected block: have %d, want %d", block.Block, next)
+			}
+			next++
+		}
+	}
+	if next != 11 {
+		t.Fatalf("missing blocks: traced up to %d", next-1)
+	}
+	// Drop the middle chunk from the checkpoint and check only that is redone
+	checkpoint, err := loadRangeCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	checkpoint.Done = []uint64{1, 9}
+	if err := checkpoint.store(dir); err != nil {
+		t.Fatalf("failed to store checkpoint: %v", err)
+	}
+	os.Remove(filepath.Join(dir, rangeChunkName(5, 8)))
+
+	res, err = api.TraceBlockRange(context.Background(), 1, 10, config)
+	if err != nil {
+		t.Fatalf("failed to resume range: %v", err)
+	}
+	if res.Traced != 4 || res.Resumed != 6 {
+		t.Fatalf("unexpected resumed result: %+v", res)
+	}
+	if blocks := readRangeChunk(t, res.Files[1]); len(blocks) != 4 {
+		t.Fatalf("unexpected retraced chunk length: have %d, want 4", len(blocks))
+	}
+	// A checkpoint of a different range must not be resumed
+	if _, err := api.TraceBlockRange(context.Background(), rpc.BlockNumber(2), 10, config); err == nil {
+		t.Fatal("expected checkpoint mismatch error")
+	}
+}
+
+func TestTraceBlockRangeTracerConfig(t *testing.T) {
+	genesis := &core.Genesis{Config: params.TestChainConfig}
+	backend := newTestBackend(t, 2, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+	api := &API{backend: backend, rangeDir: t.TempDir()}
+
+	trace := func(tracerConfig string) (*RangeTraceResult, error) {
+		config := &RangeTraceConfig{Dir: "trace"}
+		config.TracerConfig = json.RawMessage(tracerConfig)
+		return api.TraceBlockRange(context.Background(), 1, 2, config)
+	}
+	if _, err := trace(`{"limit": 1}`); err != nil {
+		t.Fatalf("failed to trace range: %v", err)
+	}
+	// The same config in a different layout resumes the trace
+	res, err := trace(`{ "limit":1 }`)
+	if err != nil {
+		t.Fatalf("failed to resume range: %v", err)
+	}
+	if res.Traced != 0 || res.Resumed != 2 {
+		t.Fatalf("unexpected resumed result: %+v", res)
+	}
+	// A checkpoint of a different tracer config must not be resumed
+	for _, config := range []string{`{"limit": 2}`, ``} {
+		if _, err := trace(config); err == nil {
+			t.Errorf("config %q: expected checkpoint mismatch error", config)
+		}
+	}
+}
+
+func TestTraceBlockRangeDir(t *testing.T) {
+	genesis := &core.Genesis{Config: params.TestChainConfig}
+	backend := newTestBackend(t, 2, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.teardown()
+
+	api := &API{backend: backend, rangeDir: t.TempDir()}
+	for _, dir := range []string{"/tmp/trace", "..", "../trace", "trace/../../x"} {
+		if _, err := api.TraceBlockRange(context.Background(), 1, 2, &RangeTraceConfig{Dir: dir}); err == nil {
+			t.Errorf("dir %q: expected error", dir)
+		}
+	}
+	res, err := api.TraceBlockRange(context.Background(), 1, 2, &RangeTraceConfig{Dir: "nested/trace"})
+	if err != nil {
+		t.Fatalf("failed to trace range: %v", err)
+	}
+	if want := filepath.Join(api.rangeDir, "nested", "trace"); res.Dir != want {
+		t.Errorf("unexpected output dir: have %s, want %s", res.Dir, want)
+	}
+	// Without a configured base directory, only fresh temporary outputs are allowed
+	api = NewAPI(backend)
+	if _, err := api.TraceBlockRange(context.Background(), 1, 2, &RangeTraceConfig{Dir: "trace"}); err == nil {
+		t.Error("expected error for unconfigured range trace directory")
+	}
+}
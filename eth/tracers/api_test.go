@@ -447,6 +447,61 @@ func TestTraceTransaction(t *testing.T) {
 		t.Error("Transaction tracing result is different")
 	}
 }
+
+// Tests that the tracing endpoints return the versioned JSON-lines records of the
+// stream logger when requested over RPC.
+func TestTraceStream(t *testing.T) {
+	t.Parallel()
+
+	// Initialize test accounts, with a contract storing 1 into slot 0
+	accounts := newAccounts(1)
+	contract := common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+			contract:         {Code: []byte{byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x00, byte(vm.SSTORE), byte(vm.STOP)}},
+		},
+	}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {})
+	defer backend.chain.Stop()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", NewAPI(backend)); err != nil {
+		t.Fatalf("failed to register API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var (
+		records []map[string]interface{}
+		call    = map[string]interface{}{"from": accounts[0].addr, "to": contract, "gas": hexutil.Uint64(100000)}
+	)
+	if err := client.Call(&records, "debug_traceCall", call, "latest", map[string]interface{}{"stream": true}); err != nil {
+		t.Fatalf("failed to trace call: %v", err)
+	}
+	// Header, four steps and the summary are expected
+	if len(records) != 6 {
+		t.Fatalf("record count mismatch: have %d, want 6: %v", len(records), records)
+	}
+	if records[0]["schema"] != logger.StreamSchema || records[0]["version"] != float64(logger.StreamSchemaVersion) {
+		t.Errorf("header mismatch: %v", records[0])
+	}
+	for i, op := range []string{"PUSH1", "PUSH1", "SSTORE", "STOP"} {
+		if have := records[i+1]["opName"]; have != op {
+			t.Errorf("step %d opcode mismatch: have %v, want %s", i, have, op)
+		}
+	}
+	storage, ok := records[3]["storage"].(map[string]interface{})
+	if !ok || storage["post"] != common.BigToHash(common.Big1).Hex() {
+		t.Errorf("storage write mismatch: %v", records[3]["storage"])
+	}
+	if _, ok := records[5]["gasUsed"]; !ok {
+		t.Errorf("summary missing: %v", records[5])
+	}
+}
+
 func TestTraceTransactionHistorical(t *testing.T) {
 	t.Parallel()
 
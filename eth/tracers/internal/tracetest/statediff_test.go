@@ -0,0 +1,169 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+type stateDiffSlot struct {
+	Pre  common.Hash `json:"pre"`
+	Post common.Hash `json:"post"`
+}
+
+type stateDiffAccount struct {
+	Balance *struct {
+		Pre  *hexutil.Big `json:"pre"`
+		Post *hexutil.Big `json:"post"`
+	} `json:"balance"`
+	Nonce *struct {
+		Pre  hexutil.Uint64 `json:"pre"`
+		Post hexutil.Uint64 `json:"post"`
+	} `json:"nonce"`
+	Storage          map[common.Hash]stateDiffSlot `json:"storage"`
+	TransientStorage map[common.Hash]stateDiffSlot `json:"transientStorage"`
+}
+
+type stateDiffFrame struct {
+	Type     string                               `json:"type"`
+	Error    string                               `json:"error"`
+	Reverted bool                                 `json:"reverted"`
+	Accounts map[common.Address]*stateDiffAccount `json:"accounts"`
+	Calls    []*stateDiffFrame                    `json:"calls"`
+}
+
+func TestStateDiffTracer(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		origin = crypto.PubkeyToAddress(key.PublicKey)
+		a      = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		b      = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+		config = *params.AllEthashProtocolChanges
+	)
+	config.ShanghaiTime = new(uint64)
+	config.CancunTime = new(uint64)
+
+	// A: TSTORE(1, 2), SSTORE(0, 1), CALL(B), STOP
+	codeA := []byte{
+		byte(vm.PUSH1), 0x02, byte(vm.PUSH1), 0x01, byte(vm.TSTORE),
+		byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00,
+		byte(vm.PUSH20),
+	}
+	codeA = append(codeA, b.Bytes()...)
+	codeA = append(codeA, byte(vm.PUSH2), 0xff, 0xff, byte(vm.CALL), byte(vm.POP), byte(vm.STOP))
+
+	// B: SSTORE(0, 5), REVERT(0, 0)
+	codeB := []byte{
+		byte(vm.PUSH1), 0x05, byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.REVERT),
+	}
+	alloc := core.GenesisAlloc{
+		origin: {Balance: big.NewInt(params.Ether)},
+		a:      {Code: codeA},
+		b:      {Code: codeB},
+	}
+	var (
+		signer = types.LatestSigner(&config)
+		tx     = types.MustSignNewTx(key, signer, &types.LegacyTx{
+			To:       &a,
+			Gas:      200000,
+			GasPrice: big.NewInt(params.GWei),
+		})
+		context = vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetHash:     func(uint64) common.Hash { return common.Hash{} },
+			Coinbase:    common.HexToAddress("0xc0ffee"),
+			BlockNumber: big.NewInt(1),
+			Difficulty:  big.NewInt(0),
+			GasLimit:    30000000,
+			BaseFee:     big.NewInt(params.GWei),
+		}
+		triedb, _, statedb = tests.MakePreState(rawdb.NewMemoryDatabase(), alloc, false, rawdb.HashScheme)
+	)
+	defer triedb.Close()
+
+	tracer, err := tracers.DefaultDirectory.New("stateDiffTracer", new(tracers.Context), nil)
+	if err != nil {
+		t.Fatalf("failed to create state diff tracer: %v", err)
+	}
+	msg, err := core.TransactionToMessage(tx, signer, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	evm := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, &config, vm.Config{Tracer: tracer})
+	evm.ArcologyNetworkAPIs.APIs = vm.NoopArcologyAPIs{}
+
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if _, err = st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatalf("failed to retrieve trace result: %v", err)
+	}
+	var root stateDiffFrame
+	if err := json.Unmarshal(res, &root); err != nil {
+		t.Fatalf("failed to unmarshal trace result: %v", err)
+	}
+	// The root frame holds the sender's nonce and balance and A's writes
+	sender := root.Accounts[origin]
+	if sender == nil || sender.Nonce == nil || sender.Nonce.Pre != 0 || sender.Nonce.Post != 1 {
+		t.Fatalf("sender nonce mismatch: have %+v", sender)
+	}
+	if sender.Balance == nil || sender.Balance.Pre.ToInt().Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("sender balance mismatch: have %+v", sender.Balance)
+	}
+	acc := root.Accounts[a]
+	if acc == nil {
+		t.Fatalf("missing diff of %x", a)
+	}
+	if have := acc.Storage[common.Hash{}]; have.Pre != (common.Hash{}) || have.Post != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("storage diff mismatch: have %+v", have)
+	}
+	if have := acc.TransientStorage[common.BigToHash(big.NewInt(1))]; have.Post != common.BigToHash(big.NewInt(2)) {
+		t.Errorf("transient storage diff mismatch: have %+v", have)
+	}
+	if root.Reverted {
+		t.Errorf("root frame unexpectedly reverted")
+	}
+	// The reverted inner call keeps its own, flagged diff
+	if len(root.Calls) != 1 {
+		t.Fatalf("call count mismatch: have %d, want 1", len(root.Calls))
+	}
+	call := root.Calls[0]
+	if !call.Reverted || call.Error == "" {
+		t.Errorf("inner call not flagged as reverted: %+v", call)
+	}
+	if acc := call.Accounts[b]; acc == nil || acc.Storage[common.Hash{}].Post != common.BigToHash(big.NewInt(5)) {
+		t.Errorf("inner storage diff mismatch: have %+v", acc)
+	}
+}
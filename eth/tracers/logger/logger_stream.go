@@ -0,0 +1,272 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+)
+
+// StreamSchema and StreamSchemaVersion identify the format written by the
+// StreamLogger. The version is bumped on every incompatible change.
+//
+// A stream is a sequence of JSON objects, one per line:
+//
+//   - a header record {"schema":"evm-trace","version":1} opening every transaction,
+//   - one step record per executed opcode, following EIP-3155 (pc, op, gas, gasCost,
+//     memSize, stack, depth, returnData, refund, opName, error, memory) and extended
+//     with a "storage" object on SSTORE and TSTORE carrying the slot's pre and post
+//     values,
+//   - one {"arcologyCall":{...}} record per call redirected to the Arcology APIs,
+//   - a closing summary record, following EIP-3155 (output, gasUsed, error, time)
+//     and extended with the gas refund counter.
+const (
+	StreamSchema        = "evm-trace"
+	StreamSchemaVersion = 1
+)
+
+// streamHeader opens the trace of a transaction.
+type streamHeader struct {
+	Schema  string `json:"schema"`
+	Version int    `json:"version"`
+}
+
+// streamStep is the record of a single executed opcode.
+type streamStep struct {
+	Pc         uint64         `json:"pc"`
+	Op         vm.OpCode      `json:"op"`
+	Gas        hexutil.Uint64 `json:"gas"`
+	GasCost    hexutil.Uint64 `json:"gasCost"`
+	Memory     hexutil.Bytes  `json:"memory,omitempty"`
+	MemorySize int            `json:"memSize"`
+	Stack      []uint256.Int  `json:"stack"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Depth      int            `json:"depth"`
+	Refund     hexutil.Uint64 `json:"refund"`
+	OpName     string         `json:"opName"`
+	Error      string         `json:"error,omitempty"`
+	Storage    *streamStorage `json:"storage,omitempty"`
+}
+
+// streamStorage is the storage write performed by an SSTORE or TSTORE step.
+type streamStorage struct {
+	Address   common.Address `json:"address"`
+	Key       common.Hash    `json:"key"`
+	Pre       common.Hash    `json:"pre"`
+	Post      common.Hash    `json:"post"`
+	Transient bool           `json:"transient,omitempty"`
+}
+
+// streamArcologyCall is the record of a call redirected to the Arcology APIs.
+type streamArcologyCall struct {
+	Caller      common.Address `json:"caller"`
+	Callee      common.Address `json:"callee"`
+	Input       hexutil.Bytes  `json:"input"`
+	Gas         hexutil.Uint64 `json:"gas"`
+	LeftOverGas hexutil.Uint64 `json:"leftOverGas"`
+	Output      hexutil.Bytes  `json:"output"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// streamSummary closes the trace of a transaction.
+type streamSummary struct {
+	Output  hexutil.Bytes  `json:"output"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Refund  hexutil.Uint64 `json:"refund"`
+	Error   string         `json:"error,omitempty"`
+	Time    time.Duration  `json:"time"`
+}
+
+// StreamLogger is an EVM state logger which encodes the execution trace into
+// the versioned JSON-lines format described by StreamSchema. Unlike StructLogger
+// it retains nothing per step, every record is written as soon as it's captured.
+type StreamLogger struct {
+	encoder *json.Encoder
+	cfg     Config
+	env     *vm.EVM
+
+	start   time.Time
+	steps   int // Number of step records written
+	summary json.RawMessage
+	err     error // First write error, aborts further output
+
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// NewStreamLogger creates a new EVM tracer that streams execution steps as
+// JSON lines into the provided writer.
+func NewStreamLogger(cfg *Config, writer io.Writer) *StreamLogger {
+	l := &StreamLogger{encoder: json.NewEncoder(writer)}
+	if cfg != nil {
+		l.cfg = *cfg
+	}
+	return l
+}
+
+// write encodes a single record, remembering the first failure.
+func (l *StreamLogger) write(record interface{}) {
+	if l.err != nil {
+		return
+	}
+	l.err = l.encoder.Encode(record)
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (l *StreamLogger) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	l.env = env
+	l.start = time.Now()
+	l.write(streamHeader{Schema: StreamSchema, Version: StreamSchemaVersion})
+}
+
+// CaptureState outputs state information on the logger.
+func (l *StreamLogger) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	// If tracing was interrupted, stop writing records
+	if l.interrupt.Load() {
+		return
+	}
+	// Respect the step limit, counting records rather than buffered logs
+	if l.cfg.Limit != 0 && l.steps >= l.cfg.Limit {
+		return
+	}
+	l.steps++
+
+	var (
+		memory = scope.Memory
+		stack  = scope.Stack
+	)
+	step := streamStep{
+		Pc:         pc,
+		Op:         op,
+		Gas:        hexutil.Uint64(gas),
+		GasCost:    hexutil.Uint64(cost),
+		MemorySize: memory.Len(),
+		Depth:      depth,
+		Refund:     hexutil.Uint64(l.env.StateDB.GetRefund()),
+		OpName:     op.String(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	if l.cfg.EnableMemory {
+		step.Memory = memory.Data()
+	}
+	if !l.cfg.DisableStack {
+		step.Stack = stack.Data()
+	}
+	if l.cfg.EnableReturnData {
+		step.ReturnData = rData
+	}
+	if data := stack.Data(); !l.cfg.DisableStorage && (op == vm.SSTORE || op == vm.TSTORE) && len(data) >= 2 {
+		var (
+			address = scope.Contract.Address()
+			key     = common.Hash(data[len(data)-1].Bytes32())
+			write   = &streamStorage{
+				Address:   address,
+				Key:       key,
+				Post:      common.Hash(data[len(data)-2].Bytes32()),
+				Transient: op == vm.TSTORE,
+			}
+		)
+		if op == vm.SSTORE {
+			write.Pre = l.env.StateDB.GetState(address, key)
+		} else {
+			write.Pre = l.env.StateDB.GetTransientState(address, key)
+		}
+		step.Storage = write
+	}
+	l.write(step)
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (l *StreamLogger) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	l.CaptureState(pc, op, gas, cost, scope, nil, depth, err)
+}
+
+// CaptureEnd is triggered at end of execution.
+func (l *StreamLogger) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	summary := streamSummary{
+		Output:  output,
+		GasUsed: hexutil.Uint64(gasUsed),
+		Time:    time.Since(l.start),
+	}
+	if l.env != nil {
+		summary.Refund = hexutil.Uint64(l.env.StateDB.GetRefund())
+	}
+	if err != nil {
+		summary.Error = err.Error()
+	}
+	l.summary, _ = json.Marshal(summary)
+	l.write(summary)
+}
+
+// CaptureArcologyCall implements the vm.ArcologyCallLogger interface to trace
+// a call redirected to the Arcology APIs.
+func (l *StreamLogger) CaptureArcologyCall(caller, callee common.Address, input []byte, gas, leftOverGas uint64, output []byte, err error) {
+	call := streamArcologyCall{
+		Caller:      caller,
+		Callee:      callee,
+		Input:       input,
+		Gas:         hexutil.Uint64(gas),
+		LeftOverGas: hexutil.Uint64(leftOverGas),
+		Output:      output,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	l.write(struct {
+		Call streamArcologyCall `json:"arcologyCall"`
+	}{call})
+}
+
+func (l *StreamLogger) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (l *StreamLogger) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (l *StreamLogger) CaptureTxStart(gasLimit uint64) {}
+
+func (l *StreamLogger) CaptureTxEnd(restGas uint64) {}
+
+// GetResult returns the summary record of the traced execution, the steps
+// themselves are delivered through the stream.
+func (l *StreamLogger) GetResult() (json.RawMessage, error) {
+	if l.reason != nil {
+		return nil, l.reason
+	}
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.summary, nil
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (l *StreamLogger) Stop(err error) {
+	l.reason = err
+	l.interrupt.Store(true)
+}
+
+// Error returns the first failure encountered while writing the stream.
+func (l *StreamLogger) Error() error { return l.err }
@@ -0,0 +1,100 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestStreamLogger(t *testing.T) {
+	var (
+		out      = new(bytes.Buffer)
+		logger   = NewStreamLogger(nil, out)
+		env      = vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, &dummyStatedb{}, params.TestChainConfig, vm.Config{Tracer: logger})
+		contract = vm.NewContract(&dummyContractRef{}, &dummyContractRef{}, new(big.Int), 100000)
+	)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE)}
+
+	logger.CaptureStart(env, common.Address{}, contract.Address(), false, nil, 0, nil)
+	if _, err := env.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+	logger.CaptureEnd(nil, 21000, nil)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("record count mismatch: have %d, want %d\n%s", len(lines), 6, out)
+	}
+	var header streamHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatal(err)
+	}
+	if header.Schema != StreamSchema || header.Version != StreamSchemaVersion {
+		t.Errorf("header mismatch: have %+v", header)
+	}
+	var step streamStep
+	if err := json.Unmarshal([]byte(lines[3]), &step); err != nil {
+		t.Fatal(err)
+	}
+	if step.Op != vm.SSTORE || step.Refund != 1337 {
+		t.Errorf("step mismatch: have %+v", step)
+	}
+	if step.Storage == nil || step.Storage.Post != common.BigToHash(big.NewInt(1)) || step.Storage.Transient {
+		t.Errorf("storage write mismatch: have %+v", step.Storage)
+	}
+	var summary streamSummary
+	if err := json.Unmarshal([]byte(lines[5]), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.GasUsed != 21000 {
+		t.Errorf("gas used mismatch: have %d, want %d", summary.GasUsed, 21000)
+	}
+	result, err := logger.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != lines[5] {
+		t.Errorf("result mismatch: have %s, want %s", result, lines[5])
+	}
+}
+
+func TestStreamLoggerLimit(t *testing.T) {
+	var (
+		out      = new(bytes.Buffer)
+		logger   = NewStreamLogger(&Config{Limit: 1}, out)
+		env      = vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, &dummyStatedb{}, params.TestChainConfig, vm.Config{Tracer: logger})
+		contract = vm.NewContract(&dummyContractRef{}, &dummyContractRef{}, new(big.Int), 100000)
+	)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE)}
+
+	logger.CaptureStart(env, common.Address{}, contract.Address(), false, nil, 0, nil)
+	if _, err := env.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+	// Header plus a single step
+	if have := strings.Count(out.String(), "\n"); have != 2 {
+		t.Fatalf("record count mismatch: have %d, want %d", have, 2)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+func init() {
+	tracers.DefaultDirectory.Register("stateDiffTracer", newStateDiffTracer, false)
+}
+
+// balanceDiff is the balance change of an account within a call frame.
+type balanceDiff struct {
+	Pre  *hexutil.Big `json:"pre"`
+	Post *hexutil.Big `json:"post"`
+}
+
+// nonceDiff is the nonce change of an account within a call frame.
+type nonceDiff struct {
+	Pre  hexutil.Uint64 `json:"pre"`
+	Post hexutil.Uint64 `json:"post"`
+}
+
+// codeDiff is the code change of an account within a call frame.
+type codeDiff struct {
+	Pre  hexutil.Bytes `json:"pre"`
+	Post hexutil.Bytes `json:"post"`
+}
+
+// slotDiff is the change of a single storage slot within a call frame.
+type slotDiff struct {
+	Pre  common.Hash `json:"pre"`
+	Post common.Hash `json:"post"`
+}
+
+// accountDiff collects the changes a call frame directly made to an account.
+type accountDiff struct {
+	Balance          *balanceDiff              `json:"balance,omitempty"`
+	Nonce            *nonceDiff                `json:"nonce,omitempty"`
+	Code             *codeDiff                 `json:"code,omitempty"`
+	Storage          map[common.Hash]*slotDiff `json:"storage,omitempty"`
+	TransientStorage map[common.Hash]*slotDiff `json:"transientStorage,omitempty"`
+	SelfDestructed   bool                      `json:"selfDestructed,omitempty"`
+}
+
+// diffFrame is a call frame along with the state changes it directly caused.
+// Changes made by subcalls are attributed to the subcall frames.
+type diffFrame struct {
+	Type     string                          `json:"type"`
+	From     common.Address                  `json:"from"`
+	To       common.Address                  `json:"to"`
+	Value    *hexutil.Big                    `json:"value,omitempty"`
+	Gas      hexutil.Uint64                  `json:"gas"`
+	GasUsed  hexutil.Uint64                  `json:"gasUsed"`
+	Error    string                          `json:"error,omitempty"`
+	Reverted bool                            `json:"reverted,omitempty"` // Whether the changes of the frame were rolled back
+	Accounts map[common.Address]*accountDiff `json:"accounts,omitempty"`
+	Calls    []*diffFrame                    `json:"calls,omitempty"`
+}
+
+// account returns the diff of addr in the frame, creating it if needed.
+func (f *diffFrame) account(addr common.Address) *accountDiff {
+	if f.Accounts == nil {
+		f.Accounts = make(map[common.Address]*accountDiff)
+	}
+	diff, ok := f.Accounts[addr]
+	if !ok {
+		diff = new(accountDiff)
+		f.Accounts[addr] = diff
+	}
+	return diff
+}
+
+// setBalance records a balance change, keeping the earliest pre value.
+func (f *diffFrame) setBalance(addr common.Address, pre, post *big.Int) {
+	diff := f.account(addr)
+	if diff.Balance == nil {
+		diff.Balance = &balanceDiff{Pre: (*hexutil.Big)(pre)}
+	}
+	diff.Balance.Post = (*hexutil.Big)(post)
+}
+
+// setNonce records a nonce change, keeping the earliest pre value.
+func (f *diffFrame) setNonce(addr common.Address, pre, post uint64) {
+	diff := f.account(addr)
+	if diff.Nonce == nil {
+		diff.Nonce = &nonceDiff{Pre: hexutil.Uint64(pre)}
+	}
+	diff.Nonce.Post = hexutil.Uint64(post)
+}
+
+// setSlot records a storage change, keeping the earliest pre value.
+func (f *diffFrame) setSlot(addr common.Address, key, pre, post common.Hash, transient bool) {
+	var (
+		diff  = f.account(addr)
+		slots = &diff.Storage
+	)
+	if transient {
+		slots = &diff.TransientStorage
+	}
+	if *slots == nil {
+		*slots = make(map[common.Hash]*slotDiff)
+	}
+	slot, ok := (*slots)[key]
+	if !ok {
+		slot = &slotDiff{Pre: pre}
+		(*slots)[key] = slot
+	}
+	slot.Post = post
+}
+
+// stateDiffTracer is a native go tracer which builds the call tree of a
+// transaction, listing in each frame the storage slots (persistent and
+// transient), balances, nonces and code it changed. Changes rolled back by a
+// failing frame are retained and marked as reverted.
+type stateDiffTracer struct {
+	noopTracer
+	env       *vm.EVM
+	callstack []*diffFrame
+	root      *diffFrame
+	from      common.Address // Transaction sender, charged for gas
+	coinbase  *big.Int       // Coinbase balance before execution, credited with fees
+	gasLimit  uint64
+	interrupt atomic.Bool // Atomic flag to signal execution interruption
+	reason    error       // Textual reason for the interruption
+}
+
+// newStateDiffTracer returns a native go tracer which attributes state changes
+// to the call frames of a tx, and implements vm.EVMLogger.
+func newStateDiffTracer(ctx *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &stateDiffTracer{}, nil
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *stateDiffTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.from = from
+
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.root = t.newFrame(typ, from, to, t.gasLimit, value)
+	t.callstack = []*diffFrame{t.root}
+
+	// The sender was charged for gas and its nonce bumped before execution
+	// started, attribute both to the top frame.
+	var (
+		db     = env.StateDB
+		sender = t.root.account(from)
+	)
+	if sender.Balance == nil {
+		balance := db.GetBalance(from)
+		sender.Balance = &balanceDiff{Pre: (*hexutil.Big)(balance), Post: (*hexutil.Big)(balance)}
+	}
+	if gasPrice := env.TxContext.GasPrice; gasPrice != nil {
+		cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(t.gasLimit))
+		sender.Balance.Pre = (*hexutil.Big)(cost.Add(cost, sender.Balance.Pre.ToInt()))
+	}
+	t.coinbase = new(big.Int).Set(db.GetBalance(env.Context.Coinbase))
+	if nonce := db.GetNonce(from); nonce > 0 {
+		t.root.setNonce(from, nonce-1, nonce)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *stateDiffTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.exitFrame(t.root, err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *stateDiffTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	if op != vm.SSTORE && op != vm.TSTORE {
+		return
+	}
+	stackData := scope.Stack.Data()
+	if len(stackData) < 2 {
+		return
+	}
+	var (
+		frame = t.callstack[len(t.callstack)-1]
+		addr  = scope.Contract.Address()
+		key   = common.Hash(stackData[len(stackData)-1].Bytes32())
+		post  = common.Hash(stackData[len(stackData)-2].Bytes32())
+	)
+	if op == vm.SSTORE {
+		frame.setSlot(addr, key, t.env.StateDB.GetState(addr, key), post, false)
+	} else {
+		frame.setSlot(addr, key, t.env.StateDB.GetTransientState(addr, key), post, true)
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *stateDiffTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	// Skip if tracing was interrupted
+	if t.interrupt.Load() {
+		return
+	}
+	frame := t.newFrame(typ, from, to, gas, value)
+	parent := t.callstack[len(t.callstack)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.callstack = append(t.callstack, frame)
+
+	if typ == vm.CREATE || typ == vm.CREATE2 {
+		// The creator's nonce was bumped before entering the frame
+		if nonce := t.env.StateDB.GetNonce(from); nonce > 0 {
+			frame.setNonce(from, nonce-1, nonce)
+		}
+	}
+	if typ == vm.SELFDESTRUCT {
+		frame.account(from).SelfDestructed = true
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *stateDiffTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+	frame := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	t.exitFrame(frame, err)
+}
+
+func (t *stateDiffTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+// CaptureTxEnd settles the sender and coinbase balances after the gas refund
+// and fee payment, which are attributed to the top frame.
+func (t *stateDiffTracer) CaptureTxEnd(restGas uint64) {
+	if t.root == nil {
+		return
+	}
+	t.root.GasUsed = hexutil.Uint64(t.gasLimit - restGas)
+
+	db := t.env.StateDB
+	t.root.setBalance(t.from, nil, db.GetBalance(t.from))
+	if coinbase := t.env.Context.Coinbase; coinbase != t.from {
+		var pre *big.Int
+		if diff := t.root.account(coinbase); diff.Balance != nil {
+			pre = (*big.Int)(diff.Balance.Pre)
+		} else {
+			pre = t.coinbase
+		}
+		if post := db.GetBalance(coinbase); pre.Cmp(post) != 0 {
+			t.root.setBalance(coinbase, pre, post)
+		}
+	}
+}
+
+// GetResult returns the json-encoded call tree annotated with state changes,
+// and any error arising from the encoding or forceful termination (via `Stop`).
+func (t *stateDiffTracer) GetResult() (json.RawMessage, error) {
+	if t.root == nil {
+		return nil, errors.New("no call frame captured")
+	}
+	markReverted(t.root, false)
+
+	res, err := json.Marshal(t.root)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *stateDiffTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// newFrame creates a call frame, attributing the value transfer which took
+// place right before entering it.
+func (t *stateDiffTracer) newFrame(typ vm.OpCode, from, to common.Address, gas uint64, value *big.Int) *diffFrame {
+	frame := &diffFrame{
+		Type: typ.String(),
+		From: from,
+		To:   to,
+		Gas:  hexutil.Uint64(gas),
+	}
+	if value != nil && value.Sign() != 0 {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	// DELEGATECALL inherits the value without transferring it and CALLCODE
+	// transfers to the caller itself, everything else moves balance.
+	if frame.Value == nil || typ == vm.DELEGATECALL || typ == vm.CALLCODE || from == to {
+		return frame
+	}
+	db := t.env.StateDB
+	fromBal, toBal := db.GetBalance(from), db.GetBalance(to)
+	frame.setBalance(from, new(big.Int).Add(fromBal, value), fromBal)
+	frame.setBalance(to, new(big.Int).Sub(toBal, value), toBal)
+	return frame
+}
+
+// exitFrame finalizes a call frame, recording the code deployed by a
+// successful contract creation and the error of a failing one.
+func (t *stateDiffTracer) exitFrame(frame *diffFrame, err error) {
+	if err != nil {
+		frame.Error = err.Error()
+		frame.Reverted = true
+		return
+	}
+	if frame.Type != vm.CREATE.String() && frame.Type != vm.CREATE2.String() {
+		return
+	}
+	db := t.env.StateDB
+	if code := db.GetCode(frame.To); len(code) > 0 {
+		frame.account(frame.To).Code = &codeDiff{Pre: []byte{}, Post: code}
+	}
+	if nonce := db.GetNonce(frame.To); nonce > 0 {
+		frame.setNonce(frame.To, 0, nonce)
+	}
+}
+
+// markReverted flags the frames whose changes were rolled back because they
+// or one of their ancestors failed.
+func markReverted(frame *diffFrame, parentReverted bool) {
+	frame.Reverted = frame.Reverted || parentReverted
+	for _, call := range frame.Calls {
+		markReverted(call, frame.Reverted)
+	}
+}
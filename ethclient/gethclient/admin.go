@@ -0,0 +1,61 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gethclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Peers returns information about the connected remote nodes.
+func (ec *Client) Peers(ctx context.Context) ([]*p2p.PeerInfo, error) {
+	var result []*p2p.PeerInfo
+	err := ec.c.CallContext(ctx, &result, "admin_peers")
+	return result, err
+}
+
+// AddPeer requests connecting to the remote node with the given enode URL, and
+// maintaining the connection at all times.
+func (ec *Client) AddPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_addPeer", url)
+}
+
+// RemovePeer disconnects from the remote node with the given enode URL, if the
+// connection exists.
+func (ec *Client) RemovePeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_removePeer", url)
+}
+
+// AddTrustedPeer allows the remote node with the given enode URL to always
+// connect, even if the peer slots are full.
+func (ec *Client) AddTrustedPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_addTrustedPeer", url)
+}
+
+// RemoveTrustedPeer removes the remote node with the given enode URL from the
+// trusted peers, without disconnecting it.
+func (ec *Client) RemoveTrustedPeer(ctx context.Context, url string) error {
+	return ec.c.CallContext(ctx, nil, "admin_removeTrustedPeer", url)
+}
+
+// SubscribePeerEvents subscribes to the events of peers being added, dropped or
+// exchanging messages.
+func (ec *Client) SubscribePeerEvents(ctx context.Context, ch chan<- *p2p.PeerEvent) (*rpc.ClientSubscription, error) {
+	return ec.c.Subscribe(ctx, "admin", ch, "peerEvents")
+}
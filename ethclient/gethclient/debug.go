@@ -0,0 +1,177 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gethclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// TxTraceResult is the result of tracing a single transaction of a block.
+type TxTraceResult struct {
+	TxHash common.Hash     `json:"txHash"`           // Hash of the traced transaction
+	Result json.RawMessage `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string          `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// BlockTraceResult is the result of tracing the transactions of a block, as
+// delivered by a trace chain subscription.
+type BlockTraceResult struct {
+	Block  hexutil.Uint64   `json:"block"`  // Number of the traced block
+	Hash   common.Hash      `json:"hash"`   // Hash of the traced block
+	Traces []*TxTraceResult `json:"traces"` // Trace results of the transactions
+}
+
+// CallFrame is a call made during the execution of a transaction, as reported
+// by the callTracer.
+type CallFrame struct {
+	Type         string          `json:"type"`
+	From         common.Address  `json:"from"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	To           *common.Address `json:"to,omitempty"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []CallFrame     `json:"calls,omitempty"`
+	Logs         []CallLog       `json:"logs,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+}
+
+// CallLog is a log emitted during a call, as reported by the callTracer if
+// configured with withLog.
+type CallLog struct {
+	Address  common.Address `json:"address"`
+	Topics   []common.Hash  `json:"topics"`
+	Data     hexutil.Bytes  `json:"data"`
+	Position hexutil.Uint   `json:"position"` // Position of the log relative to the subcalls
+}
+
+// StorageRangeResult is the result of a debug_storageRangeAt call.
+type StorageRangeResult struct {
+	Storage map[common.Hash]StorageEntry `json:"storage"` // Slots keyed by their hashed key
+	NextKey *common.Hash                 `json:"nextKey"` // nil if Storage includes the last key in the trie
+}
+
+// StorageEntry is a storage slot of a StorageRangeResult.
+type StorageEntry struct {
+	Key   *common.Hash `json:"key"` // nil if the preimage of the key is unknown
+	Value common.Hash  `json:"value"`
+}
+
+// TraceTransaction returns the trace of the transaction with the given hash,
+// produced by the tracer of the config or the struct logger if none is set.
+func (ec *Client) TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, config)
+	return result, err
+}
+
+// TraceTransactionStructLogs returns the opcode level trace of the transaction
+// with the given hash, produced by the struct logger.
+func (ec *Client) TraceTransactionStructLogs(ctx context.Context, hash common.Hash, config *logger.Config) (*logger.ExecutionResult, error) {
+	var result *logger.ExecutionResult
+	err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, &tracers.TraceConfig{Config: config})
+	return result, err
+}
+
+// TraceTransactionCalls returns the call tree of the transaction with the given
+// hash, produced by the callTracer.
+func (ec *Client) TraceTransactionCalls(ctx context.Context, hash common.Hash, withLogs bool) (*CallFrame, error) {
+	var result *CallFrame
+	err := ec.c.CallContext(ctx, &result, "debug_traceTransaction", hash, callTracerConfig(withLogs))
+	return result, err
+}
+
+// TraceCall returns the trace of executing the given call in the context of the
+// given block, produced by the tracer of the config or the struct logger if none
+// is set. The block number can be nil, in which case the latest block is used.
+func (ec *Client) TraceCall(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int, config *tracers.TraceCallConfig) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := ec.c.CallContext(ctx, &result, "debug_traceCall", toCallArg(msg), toBlockNumArg(blockNumber), config)
+	return result, err
+}
+
+// TraceBlockByNumber returns the traces of the transactions of the block with the
+// given number. The block number can be nil, in which case the latest block is
+// traced.
+func (ec *Client) TraceBlockByNumber(ctx context.Context, number *big.Int, config *tracers.TraceConfig) ([]*TxTraceResult, error) {
+	var result []*TxTraceResult
+	err := ec.c.CallContext(ctx, &result, "debug_traceBlockByNumber", toBlockNumArg(number), config)
+	return result, err
+}
+
+// TraceBlockByHash returns the traces of the transactions of the block with the
+// given hash.
+func (ec *Client) TraceBlockByHash(ctx context.Context, hash common.Hash, config *tracers.TraceConfig) ([]*TxTraceResult, error) {
+	var result []*TxTraceResult
+	err := ec.c.CallContext(ctx, &result, "debug_traceBlockByHash", hash, config)
+	return result, err
+}
+
+// SubscribeTraceChain subscribes to the traces of the blocks in the range
+// (start, end], delivered in order. The subscription isn't ended by the server
+// once the end block is delivered, it's up to the caller to unsubscribe.
+func (ec *Client) SubscribeTraceChain(ctx context.Context, ch chan<- *BlockTraceResult, start, end *big.Int, config *tracers.TraceConfig) (*rpc.ClientSubscription, error) {
+	return ec.c.Subscribe(ctx, "debug", ch, "traceChain", toBlockNumArg(start), toBlockNumArg(end), config)
+}
+
+// StorageRangeAt returns at most maxResult storage slots of the given contract,
+// starting at the hashed key keyStart, as of the execution of the transaction
+// with the given index in the block.
+func (ec *Client) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contract common.Address, keyStart []byte, maxResult int) (*StorageRangeResult, error) {
+	var result *StorageRangeResult
+	err := ec.c.CallContext(ctx, &result, "debug_storageRangeAt", blockHash, txIndex, contract, hexutil.Bytes(keyStart), maxResult)
+	return result, err
+}
+
+// GetModifiedAccountsByNumber returns the accounts modified between the given
+// blocks, exclusive of the start and inclusive of the end block. If end is nil,
+// the accounts modified in the start block are returned.
+func (ec *Client) GetModifiedAccountsByNumber(ctx context.Context, start uint64, end *uint64) ([]common.Address, error) {
+	var result []common.Address
+	err := ec.c.CallContext(ctx, &result, "debug_getModifiedAccountsByNumber", start, end)
+	return result, err
+}
+
+// GetModifiedAccountsByHash returns the accounts modified between the given
+// blocks, exclusive of the start and inclusive of the end block. If end is nil,
+// the accounts modified in the start block are returned.
+func (ec *Client) GetModifiedAccountsByHash(ctx context.Context, start common.Hash, end *common.Hash) ([]common.Address, error) {
+	var result []common.Address
+	err := ec.c.CallContext(ctx, &result, "debug_getModifiedAccountsByHash", start, end)
+	return result, err
+}
+
+// callTracerConfig returns the trace config selecting the callTracer.
+func callTracerConfig(withLogs bool) *tracers.TraceConfig {
+	tracer := "callTracer"
+	config := &tracers.TraceConfig{Tracer: &tracer}
+	if withLogs {
+		config.TracerConfig = json.RawMessage(`{"withLog":true}`)
+	}
+	return config
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
@@ -67,6 +68,7 @@ func newTestBackend(t *testing.T) (*node.Node, []*types.Block) {
 		Namespace: "eth",
 		Service:   filters.NewFilterAPI(filterSystem, false),
 	}})
+	n.RegisterAPIs(tracers.APIs(ethservice.APIBackend))
 
 	// Import the test chain.
 	if err := n.Start(); err != nil {
@@ -99,7 +101,7 @@ func generateTestChain() (*core.Genesis, []*types.Block) {
 }
 
 func TestGethClient(t *testing.T) {
-	backend, _ := newTestBackend(t)
+	backend, blocks := newTestBackend(t)
 	client := backend.Attach()
 	defer backend.Close()
 	defer client.Close()
@@ -138,6 +140,21 @@ func TestGethClient(t *testing.T) {
 		}, {
 			"TestSubscribePendingTxs",
 			func(t *testing.T) { testSubscribeFullPendingTransactions(t, client) },
+		}, {
+			"TestTxPool",
+			func(t *testing.T) { testTxPool(t, client) },
+		}, {
+			"TestPeers",
+			func(t *testing.T) { testPeers(t, client) },
+		}, {
+			"TestStorageRangeAt",
+			func(t *testing.T) { testStorageRangeAt(t, client, blocks[1]) },
+		}, {
+			"TestTraceBlock",
+			func(t *testing.T) { testTraceBlock(t, client, blocks[1]) },
+		}, {
+			"TestSubscribeTraceChain",
+			func(t *testing.T) { testSubscribeTraceChain(t, client, blocks[1]) },
 		}, {
 			"TestCallContract",
 			func(t *testing.T) { testCallContract(t, client) },
@@ -423,6 +440,102 @@ func testSubscribeFullPendingTransactions(t *testing.T, client *rpc.Client) {
 	}
 }
 
+func testTxPool(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	// The pending transaction subscription tests leave two transactions in the pool
+	status, err := ec.TxPoolStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Pending != 2 || status.Queued != 0 {
+		t.Fatalf("Invalid pool status: pending %d, queued %d", status.Pending, status.Queued)
+	}
+	content, err := ec.TxPoolContent(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content.Pending[testAddr]) != 2 || len(content.Queued) != 0 {
+		t.Fatalf("Invalid pool content: %v", content)
+	}
+	if tx := content.Pending[testAddr][1]; tx == nil || tx.From != testAddr || uint64(tx.Nonce) != 1 {
+		t.Fatalf("Invalid pending transaction: %v", tx)
+	}
+	from, err := ec.TxPoolContentFrom(context.Background(), testAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(from.Pending) != 2 || from.Pending[0].Hash != content.Pending[testAddr][0].Hash {
+		t.Fatalf("Invalid account pool content: %v", from)
+	}
+	inspect, err := ec.TxPoolInspect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0x0100000000000000000000000000000000000000: 1 wei + 22000 gas × 1 wei"; inspect.Pending[testAddr][0] != want {
+		t.Fatalf("Invalid pool inspection: have %q, want %q", inspect.Pending[testAddr][0], want)
+	}
+}
+
+func testPeers(t *testing.T, client *rpc.Client) {
+	ec := New(client)
+	peers, err := ec.Peers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 0 {
+		t.Fatalf("Unexpected peers: %v", peers)
+	}
+}
+
+func testStorageRangeAt(t *testing.T, client *rpc.Client, block *types.Block) {
+	ec := New(client)
+	result, err := ec.StorageRangeAt(context.Background(), block.Hash(), 0, testAddr, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := result.Storage[crypto.Keccak256Hash(testSlot[:])]
+	if len(result.Storage) != 1 || !ok || entry.Value != testValue || result.NextKey != nil {
+		t.Fatalf("Invalid storage range: %v", result)
+	}
+}
+
+func testTraceBlock(t *testing.T, client *rpc.Client, block *types.Block) {
+	ec := New(client)
+	byNumber, err := ec.TraceBlockByNumber(context.Background(), block.Number(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byHash, err := ec.TraceBlockByHash(context.Background(), block.Hash(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byNumber) != 0 || len(byHash) != 0 {
+		t.Fatalf("Unexpected traces of empty block: %v, %v", byNumber, byHash)
+	}
+	if _, err := ec.TraceBlockByHash(context.Background(), common.Hash{1}, nil); err == nil {
+		t.Fatal("Expected error tracing unknown block")
+	}
+}
+
+func testSubscribeTraceChain(t *testing.T, client *rpc.Client, block *types.Block) {
+	ec := New(client)
+	ch := make(chan *BlockTraceResult)
+	sub, err := ec.SubscribeTraceChain(context.Background(), ch, big.NewInt(0), block.Number(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case result := <-ch:
+		if uint64(result.Block) != block.NumberU64() || result.Hash != block.Hash() || len(result.Traces) != 0 {
+			t.Fatalf("Invalid block trace: %v", result)
+		}
+	case err := <-sub.Err():
+		t.Fatal(err)
+	}
+}
+
 func testCallContract(t *testing.T, client *rpc.Client) {
 	ec := New(client)
 	msg := ethereum.CallMsg{
@@ -0,0 +1,86 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gethclient
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+)
+
+// RPCTransaction is a transaction in its RPC representation, as returned by the
+// txpool namespace.
+type RPCTransaction = ethapi.RPCTransaction
+
+// TxPoolContent is the content of the transaction pool, keyed by sender and nonce.
+type TxPoolContent struct {
+	Pending map[common.Address]map[uint64]*RPCTransaction `json:"pending"`
+	Queued  map[common.Address]map[uint64]*RPCTransaction `json:"queued"`
+}
+
+// TxPoolAccountContent is the content of the transaction pool of a single sender,
+// keyed by nonce.
+type TxPoolAccountContent struct {
+	Pending map[uint64]*RPCTransaction `json:"pending"`
+	Queued  map[uint64]*RPCTransaction `json:"queued"`
+}
+
+// TxPoolInspection is the summary of the content of the transaction pool, keyed
+// by sender and nonce.
+type TxPoolInspection struct {
+	Pending map[common.Address]map[uint64]string `json:"pending"`
+	Queued  map[common.Address]map[uint64]string `json:"queued"`
+}
+
+// TxPoolStatus is the number of transactions in the transaction pool.
+type TxPoolStatus struct {
+	Pending hexutil.Uint `json:"pending"`
+	Queued  hexutil.Uint `json:"queued"`
+}
+
+// TxPoolContent returns the transactions contained in the transaction pool.
+func (ec *Client) TxPoolContent(ctx context.Context) (*TxPoolContent, error) {
+	var result *TxPoolContent
+	err := ec.c.CallContext(ctx, &result, "txpool_content")
+	return result, err
+}
+
+// TxPoolContentFrom returns the transactions of the given sender contained in
+// the transaction pool.
+func (ec *Client) TxPoolContentFrom(ctx context.Context, addr common.Address) (*TxPoolAccountContent, error) {
+	var result *TxPoolAccountContent
+	err := ec.c.CallContext(ctx, &result, "txpool_contentFrom", addr)
+	return result, err
+}
+
+// TxPoolInspect returns a textual summary of the transactions contained in the
+// transaction pool.
+func (ec *Client) TxPoolInspect(ctx context.Context) (*TxPoolInspection, error) {
+	var result *TxPoolInspection
+	err := ec.c.CallContext(ctx, &result, "txpool_inspect")
+	return result, err
+}
+
+// TxPoolStatus returns the number of pending and queued transactions in the
+// transaction pool.
+func (ec *Client) TxPoolStatus(ctx context.Context) (*TxPoolStatus, error) {
+	var result *TxPoolStatus
+	err := ec.c.CallContext(ctx, &result, "txpool_status")
+	return result, err
+}
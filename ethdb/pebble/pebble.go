@@ -134,6 +134,26 @@ func (l panicLogger) Fatalf(format string, args ...interface{}) {
 	panic(errors.Errorf("fatal: "+format, args...))
 }
 
+// Cache is a block cache which can be shared across multiple pebble databases.
+type Cache struct {
+	cache *pebble.Cache
+}
+
+// NewCache creates a block cache with the given capacity in megabytes, to be
+// shared across multiple databases.
+func NewCache(size int) *Cache {
+	if size < minCache {
+		size = minCache
+	}
+	return &Cache{cache: pebble.NewCache(int64(size * 1024 * 1024))}
+}
+
+// Release drops the creator's reference to the cache. The memory is released
+// once all the databases using it are closed too.
+func (c *Cache) Release() {
+	c.cache.Unref()
+}
+
 // New returns a wrapped pebble DB object. The namespace is the prefix that the
 // metrics reporting should use for surfacing internal stats.
 func New(file string, cache int, handles int, namespace string, readonly bool, ephemeral bool) (*Database, error) {
@@ -141,6 +161,23 @@ func New(file string, cache int, handles int, namespace string, readonly bool, e
 	if cache < minCache {
 		cache = minCache
 	}
+	return newDatabase(file, pebble.NewCache(int64(cache*1024*1024)), cache, cache/2, handles, namespace, readonly, ephemeral)
+}
+
+// NewWithCache returns a wrapped pebble DB object using a block cache shared
+// with other databases. The memory allowance (in megabytes) is used for the
+// memory tables of the database only, the block cache is accounted for by its
+// creator.
+func NewWithCache(file string, shared *Cache, memTables int, handles int, namespace string, readonly bool, ephemeral bool) (*Database, error) {
+	if memTables < 1 {
+		memTables = 1
+	}
+	return newDatabase(file, shared.cache, memTables, memTables, handles, namespace, readonly, ephemeral)
+}
+
+// newDatabase opens a pebble database on top of the given block cache, with
+// memTables megabytes of memory allowance for its memory tables.
+func newDatabase(file string, blockCache *pebble.Cache, cache int, memTables int, handles int, namespace string, readonly bool, ephemeral bool) (*Database, error) {
 	if handles < minHandles {
 		handles = minHandles
 	}
@@ -162,7 +199,7 @@ func New(file string, cache int, handles int, namespace string, readonly bool, e
 	// Two memory tables is configured which is identical to leveldb,
 	// including a frozen memory table and another live one.
 	memTableLimit := 2
-	memTableSize := cache * 1024 * 1024 / 2 / memTableLimit
+	memTableSize := memTables * 1024 * 1024 / memTableLimit
 
 	// The memory table size is currently capped at maxMemTableSize-1 due to a
 	// known bug in the pebble where maxMemTableSize is not recognized as a
@@ -183,7 +220,7 @@ func New(file string, cache int, handles int, namespace string, readonly bool, e
 		// Pebble has a single combined cache area and the write
 		// buffers are taken from this too. Assign all available
 		// memory allowance for cache.
-		Cache:        pebble.NewCache(int64(cache * 1024 * 1024)),
+		Cache:        blockCache,
 		MaxOpenFiles: handles,
 
 		// The size of memory table(as well as the write buffer).
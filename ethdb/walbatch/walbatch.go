@@ -0,0 +1,326 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package walbatch implements atomic batches spanning multiple key-value stores,
+// made crash safe by a write-ahead log.
+//
+// Every batch is first persisted as a single record into the log store, then
+// applied to each of the target stores and finally removed from the log. If the
+// process crashes in between, the record is replayed when the stores are opened
+// next time, so either all or none of the stores observe the batch. Records
+// failing their checksum, i.e. torn writes of the log itself, are rolled back by
+// dropping them, as none of their content could have been applied yet.
+//
+// The package itself doesn't sync any writes, so the guarantees above cover
+// process crashes. Surviving power loss or OS crashes additionally requires the
+// log and target stores to sync every write before returning, as pebble stores
+// opened in non-ephemeral mode do; LevelDB stores don't.
+//
+// The guarantees only hold if the target stores are exclusively modified through
+// batches of this package, otherwise a replay might override newer data.
+package walbatch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// recordPrefix + seq (uint64 big endian) -> batch record
+var recordPrefix = []byte("wal-")
+
+// maxStores is the maximum number of target stores supported.
+const maxStores = 1 << 16
+
+var (
+	// errCorruptRecord is returned if a log record fails to decode.
+	errCorruptRecord = errors.New("corrupt log record")
+
+	// ErrPoisoned is wrapped by the errors returned from writes after a batch
+	// failed to be applied. The pending record is only replayed on reopen, so
+	// newer batches cannot be allowed until then.
+	ErrPoisoned = errors.New("previous batch failed")
+)
+
+// recordKey = recordPrefix + seq (uint64 big endian)
+func recordKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, recordPrefix...), seq)
+}
+
+// op is a single write operation of a batch.
+type op struct {
+	store  int
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// Database is a group of key-value stores updated atomically together.
+type Database struct {
+	log    ethdb.KeyValueStore   // Store holding the write-ahead records
+	stores []ethdb.KeyValueStore // Target stores updated by the batches
+
+	seq  uint64     // Sequence number of the next record
+	err  error      // Sticky error of a failed batch application
+	lock sync.Mutex // Lock serializing the batch writes
+}
+
+// New creates an atomic batch writer on top of the given stores, with the write
+// ahead log held in the given log store. The log store may be one of the target
+// stores too. Any records left over from a previous crash are replayed before
+// returning.
+func New(wal ethdb.KeyValueStore, stores []ethdb.KeyValueStore) (*Database, error) {
+	if len(stores) == 0 || len(stores) > maxStores {
+		return nil, fmt.Errorf("invalid store count %d", len(stores))
+	}
+	db := &Database{
+		log:    wal,
+		stores: stores,
+	}
+	if err := db.recover(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// recover replays all the complete records in the log in order and drops the
+// corrupted ones, leaving the log empty.
+func (db *Database) recover() error {
+	type record struct {
+		key []byte
+		seq uint64
+		ops []op
+	}
+	var records []record
+
+	it := db.log.NewIterator(recordPrefix, nil)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(recordPrefix)+8 {
+			continue
+		}
+		seq := binary.BigEndian.Uint64(key[len(recordPrefix):])
+		ops, err := decodeRecord(it.Value(), len(db.stores))
+		if err != nil {
+			log.Warn("Rolling back incomplete batch", "seq", seq, "err", err)
+			ops = nil
+		}
+		records = append(records, record{key: common.CopyBytes(key), seq: seq, ops: ops})
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.ops != nil {
+			log.Info("Replaying interrupted batch", "seq", r.seq, "ops", len(r.ops))
+			if err := db.apply(r.ops); err != nil {
+				return fmt.Errorf("failed to replay batch %d: %w", r.seq, err)
+			}
+		}
+		if err := db.log.Delete(r.key); err != nil {
+			return err
+		}
+		db.seq = r.seq + 1
+	}
+	return nil
+}
+
+// apply writes the operations into the target stores, one batch per store.
+func (db *Database) apply(ops []op) error {
+	batches := make(map[int]ethdb.Batch)
+	for _, o := range ops {
+		batch, ok := batches[o.store]
+		if !ok {
+			batch = db.stores[o.store].NewBatch()
+			batches[o.store] = batch
+		}
+		var err error
+		if o.delete {
+			err = batch.Delete(o.key)
+		} else {
+			err = batch.Put(o.key, o.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for i := range db.stores {
+		if batch, ok := batches[i]; ok {
+			if err := batch.Write(); err != nil {
+				return fmt.Errorf("store %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// write atomically persists the given operations across all the stores.
+func (db *Database) write(ops []op) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.err != nil {
+		return db.err
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	// Persist the record, if this fails the record might or might not be in
+	// the log, so refuse any further writes until it's settled by a reopen.
+	key := recordKey(db.seq)
+	if err := db.log.Put(key, encodeRecord(ops)); err != nil {
+		db.err = fmt.Errorf("%w: %v", ErrPoisoned, err)
+		return err
+	}
+	db.seq++
+
+	// The batch is committed from here on, apply it to the stores and
+	// clean up the log. Failures are recovered on the next reopen.
+	if err := db.apply(ops); err != nil {
+		db.err = fmt.Errorf("%w: %v", ErrPoisoned, err)
+		return err
+	}
+	if err := db.log.Delete(key); err != nil {
+		db.err = fmt.Errorf("%w: %v", ErrPoisoned, err)
+		return err
+	}
+	return nil
+}
+
+// NewBatch creates a batch spanning all the target stores.
+func (db *Database) NewBatch() *Batch {
+	return &Batch{db: db}
+}
+
+// encodeRecord serializes the operations into a checksummed log record.
+//
+// The record format is: crc32(payload) || payload, where the payload is the
+// sequence of: store (uvarint) || delete (byte) || key || value, with the key
+// and value being uvarint length prefixed.
+func encodeRecord(ops []op) []byte {
+	blob := make([]byte, 4)
+	for _, o := range ops {
+		blob = binary.AppendUvarint(blob, uint64(o.store))
+		if o.delete {
+			blob = append(blob, 1)
+		} else {
+			blob = append(blob, 0)
+		}
+		blob = binary.AppendUvarint(blob, uint64(len(o.key)))
+		blob = append(blob, o.key...)
+		blob = binary.AppendUvarint(blob, uint64(len(o.value)))
+		blob = append(blob, o.value...)
+	}
+	binary.BigEndian.PutUint32(blob, crc32.ChecksumIEEE(blob[4:]))
+	return blob
+}
+
+// decodeRecord verifies and parses a log record.
+func decodeRecord(blob []byte, stores int) ([]op, error) {
+	if len(blob) < 4 || binary.BigEndian.Uint32(blob) != crc32.ChecksumIEEE(blob[4:]) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errCorruptRecord)
+	}
+	var (
+		ops  []op
+		data = blob[4:]
+	)
+	readBytes := func() ([]byte, bool) {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return nil, false
+		}
+		b := common.CopyBytes(data[n : n+int(size)])
+		data = data[n+int(size):]
+		return b, true
+	}
+	for len(data) > 0 {
+		store, n := binary.Uvarint(data)
+		if n <= 0 || store >= uint64(stores) || len(data) < n+1 {
+			return nil, fmt.Errorf("%w: invalid store", errCorruptRecord)
+		}
+		o := op{store: int(store), delete: data[n] == 1}
+		data = data[n+1:]
+
+		var ok bool
+		if o.key, ok = readBytes(); !ok {
+			return nil, fmt.Errorf("%w: invalid key", errCorruptRecord)
+		}
+		if o.value, ok = readBytes(); !ok {
+			return nil, fmt.Errorf("%w: invalid value", errCorruptRecord)
+		}
+		ops = append(ops, o)
+	}
+	return ops, nil
+}
+
+// Batch is a write-only set of changes spanning multiple stores, which are all
+// committed atomically when Write is called. A batch cannot be used concurrently.
+type Batch struct {
+	db   *Database
+	ops  []op
+	size int
+}
+
+// Store returns a writer queueing changes into the batch for the given store.
+func (b *Batch) Store(index int) ethdb.KeyValueWriter {
+	if index < 0 || index >= len(b.db.stores) {
+		panic(fmt.Sprintf("store index %d out of range [0, %d)", index, len(b.db.stores)))
+	}
+	return &storeWriter{batch: b, store: index}
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *Batch) ValueSize() int {
+	return b.size
+}
+
+// Write atomically flushes the accumulated changes into all the stores.
+func (b *Batch) Write() error {
+	return b.db.write(b.ops)
+}
+
+// Reset resets the batch for reuse.
+func (b *Batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// storeWriter queues the changes of a single store into a multi-store batch.
+type storeWriter struct {
+	batch *Batch
+	store int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (w *storeWriter) Put(key []byte, value []byte) error {
+	w.batch.ops = append(w.batch.ops, op{store: w.store, key: common.CopyBytes(key), value: common.CopyBytes(value)})
+	w.batch.size += len(key) + len(value)
+	return nil
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (w *storeWriter) Delete(key []byte) error {
+	w.batch.ops = append(w.batch.ops, op{store: w.store, key: common.CopyBytes(key), delete: true})
+	w.batch.size += len(key)
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package walbatch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+var errCrash = errors.New("injected crash")
+
+// crashCounter is shared by all the stores of a test, simulating a process crash
+// after a given number of writes. After the crash, every write fails.
+type crashCounter struct {
+	left int
+}
+
+func (c *crashCounter) write() error {
+	if c.left <= 0 {
+		return errCrash
+	}
+	c.left--
+	return nil
+}
+
+// crashStore is a key-value store which drops all writes after a crash.
+type crashStore struct {
+	ethdb.KeyValueStore
+	crash *crashCounter
+}
+
+func (s *crashStore) Put(key []byte, value []byte) error {
+	if err := s.crash.write(); err != nil {
+		return err
+	}
+	return s.KeyValueStore.Put(key, value)
+}
+
+func (s *crashStore) Delete(key []byte) error {
+	if err := s.crash.write(); err != nil {
+		return err
+	}
+	return s.KeyValueStore.Delete(key)
+}
+
+func (s *crashStore) NewBatch() ethdb.Batch {
+	return &crashBatch{Batch: s.KeyValueStore.NewBatch(), crash: s.crash}
+}
+
+// crashBatch is a batch which is dropped entirely if written after a crash.
+type crashBatch struct {
+	ethdb.Batch
+	crash *crashCounter
+}
+
+func (b *crashBatch) Write() error {
+	if err := b.crash.write(); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+// newTestStores creates a log store and a set of target stores, filled with
+// some initial data.
+func newTestStores(n int) (*memorydb.Database, []*memorydb.Database) {
+	stores := make([]*memorydb.Database, n)
+	for i := range stores {
+		stores[i] = memorydb.New()
+		stores[i].Put([]byte("stale"), []byte{byte(i)})
+	}
+	return memorydb.New(), stores
+}
+
+// fillBatch adds an update and a deletion to every store.
+func fillBatch(batch *Batch, n int, value byte) {
+	for i := 0; i < n; i++ {
+		batch.Store(i).Put([]byte(fmt.Sprintf("key-%d", i)), []byte{value, byte(i)})
+		batch.Store(i).Delete([]byte("stale"))
+	}
+}
+
+// checkStores verifies whether the stores contain the batch content or the
+// initial data, all of them consistently.
+func checkStores(t *testing.T, stores []*memorydb.Database, applied bool, value byte) {
+	t.Helper()
+
+	for i, store := range stores {
+		blob, _ := store.Get([]byte(fmt.Sprintf("key-%d", i)))
+		stale, _ := store.Has([]byte("stale"))
+		if applied {
+			if !bytes.Equal(blob, []byte{value, byte(i)}) || stale {
+				t.Fatalf("Store %d: batch not applied: value %x, stale %v", i, blob, stale)
+			}
+		} else {
+			if blob != nil || !stale {
+				t.Fatalf("Store %d: batch partially applied: value %x, stale %v", i, blob, stale)
+			}
+		}
+	}
+}
+
+// openStores creates an atomic batch writer over the given memory stores.
+func openStores(t *testing.T, wal *memorydb.Database, stores []*memorydb.Database) *Database {
+	t.Helper()
+
+	kvstores := make([]ethdb.KeyValueStore, len(stores))
+	for i, store := range stores {
+		kvstores[i] = store
+	}
+	db, err := New(wal, kvstores)
+	if err != nil {
+		t.Fatalf("Failed to open stores: %v", err)
+	}
+	return db
+}
+
+// Tests that batches are applied to all the stores and the log is cleaned up.
+func TestBatchWrite(t *testing.T) {
+	wal, stores := newTestStores(16)
+	db := openStores(t, wal, stores)
+
+	batch := db.NewBatch()
+	fillBatch(batch, len(stores), 1)
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	checkStores(t, stores, true, 1)
+	if wal.Len() != 0 {
+		t.Fatalf("Log not cleaned up: %d records left", wal.Len())
+	}
+}
+
+// Tests that a crash at any point of a batch write leaves the stores in a state
+// which is recovered into either all or none of the stores being updated.
+func TestBatchCrashRecovery(t *testing.T) {
+	const n = 4
+
+	// A batch write consists of the log write, a batch write per store and
+	// the log cleanup, crash at every step.
+	for crashAt := 0; crashAt <= n+1; crashAt++ {
+		t.Run(fmt.Sprintf("crash-%d", crashAt), func(t *testing.T) {
+			var (
+				wal, stores = newTestStores(n)
+				crash       = &crashCounter{left: crashAt}
+				kvstores    = make([]ethdb.KeyValueStore, n)
+			)
+			for i, store := range stores {
+				kvstores[i] = &crashStore{KeyValueStore: store, crash: crash}
+			}
+			db, err := New(&crashStore{KeyValueStore: wal, crash: crash}, kvstores)
+			if err != nil {
+				t.Fatalf("Failed to open stores: %v", err)
+			}
+			batch := db.NewBatch()
+			fillBatch(batch, n, 1)
+			if err := batch.Write(); !errors.Is(err, errCrash) {
+				t.Fatalf("Crash not triggered: %v", err)
+			}
+			// Any further write must be refused until recovered
+			if err := batch.Write(); !errors.Is(err, ErrPoisoned) {
+				t.Fatalf("Write after failure: have %v, want %v", err, ErrPoisoned)
+			}
+			// Restart over the surviving data, the batch must be all or nothing
+			openStores(t, wal, stores)
+			checkStores(t, stores, crashAt > 0, 1)
+			if wal.Len() != 0 {
+				t.Fatalf("Log not cleaned up: %d records left", wal.Len())
+			}
+		})
+	}
+}
+
+// Tests that torn log records are rolled back on recovery and subsequent writes
+// continue after them.
+func TestBatchTornRecord(t *testing.T) {
+	wal, stores := newTestStores(4)
+
+	batch := (&Database{stores: make([]ethdb.KeyValueStore, len(stores))}).NewBatch()
+	fillBatch(batch, len(stores), 1)
+	blob := encodeRecord(batch.ops)
+
+	wal.Put(recordKey(0), blob[:len(blob)/2])
+	blob[len(blob)-1] ^= 0xff
+	wal.Put(recordKey(1), blob)
+
+	db := openStores(t, wal, stores)
+	checkStores(t, stores, false, 1)
+	if wal.Len() != 0 {
+		t.Fatalf("Torn records not rolled back: %d records left", wal.Len())
+	}
+	if db.seq != 2 {
+		t.Fatalf("Sequence number mismatch: have %d, want %d", db.seq, 2)
+	}
+	batch = db.NewBatch()
+	fillBatch(batch, len(stores), 2)
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	checkStores(t, stores, true, 2)
+}
+
+// Tests that interrupted records are replayed in order on recovery.
+func TestBatchReplayOrder(t *testing.T) {
+	wal, stores := newTestStores(4)
+	for i := 0; i < 3; i++ {
+		batch := (&Database{stores: make([]ethdb.KeyValueStore, len(stores))}).NewBatch()
+		fillBatch(batch, len(stores), byte(i+1))
+		wal.Put(recordKey(uint64(i)), encodeRecord(batch.ops))
+	}
+	openStores(t, wal, stores)
+	checkStores(t, stores, true, 3)
+}
@@ -0,0 +1,272 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/types"
+)
+
+var (
+	queryCostHistogram  = metrics.NewRegisteredHistogram("graphql/query/cost", nil, metrics.NewExpDecaySample(1028, 0.015))
+	queryDepthHistogram = metrics.NewRegisteredHistogram("graphql/query/depth", nil, metrics.NewExpDecaySample(1028, 0.015))
+	tooDeepMeter        = metrics.NewRegisteredMeter("graphql/query/rejected/depth", nil)
+	tooCostlyMeter      = metrics.NewRegisteredMeter("graphql/query/rejected/cost", nil)
+)
+
+// listSizeEstimate is the number of items assumed to be returned by the fields
+// of list types, multiplying the cost of their selections.
+const listSizeEstimate = 100
+
+// defaultFieldCosts are the costs of the fields hitting the database or the
+// EVM, keyed by Type.field. All the other fields cost one.
+var defaultFieldCosts = map[string]uint64{
+	"Query.logs":                    1000,
+	"Query.transaction":             10,
+	"Block.logs":                    100,
+	"Block.call":                    1000,
+	"Block.estimateGas":             1000,
+	"Block.transactions":            10,
+	"Block.ommers":                  10,
+	"Block.withdrawals":             10,
+	"Block.raw":                     10,
+	"Pending.transactions":          10,
+	"Pending.call":                  1000,
+	"Pending.estimateGas":           1000,
+	"Account.balance":               10,
+	"Account.transactionCount":      10,
+	"Account.code":                  10,
+	"Account.storage":               10,
+	"Transaction.status":            10,
+	"Transaction.gasUsed":           10,
+	"Transaction.cumulativeGasUsed": 10,
+	"Transaction.effectiveGasPrice": 10,
+	"Transaction.createdContract":   10,
+	"Transaction.logs":              10,
+	"Transaction.rawReceipt":        10,
+	"Transaction.blobGasUsed":       10,
+	"Transaction.blobGasPrice":      10,
+}
+
+// CostConfig holds the limits of the static analysis of the GraphQL queries,
+// which rejects the too complex ones before their execution.
+type CostConfig struct {
+	MaxDepth   int               // Maximum nesting depth of the selected fields (0 = unlimited)
+	MaxCost    uint64            // Maximum cost of a request (0 = unlimited)
+	FieldCosts map[string]uint64 // Costs of the fields keyed by Type.field, overriding the defaults
+}
+
+// Error codes of the rejected queries, reported in the error extensions.
+const (
+	errCodeParseFailed     = "GRAPHQL_PARSE_FAILED"
+	errCodeQueryTooDeep    = "QUERY_TOO_DEEP"
+	errCodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+)
+
+// costAnalyzer computes the depth and the cost of the queries of a schema.
+type costAnalyzer struct {
+	schema   *types.Schema
+	costs    map[string]uint64
+	maxDepth int
+	maxCost  uint64
+}
+
+func newCostAnalyzer(schema *types.Schema, config CostConfig) *costAnalyzer {
+	costs := make(map[string]uint64, len(defaultFieldCosts)+len(config.FieldCosts))
+	for field, cost := range defaultFieldCosts {
+		costs[field] = cost
+	}
+	for field, cost := range config.FieldCosts {
+		costs[field] = cost
+	}
+	return &costAnalyzer{
+		schema:   schema,
+		costs:    costs,
+		maxDepth: config.MaxDepth,
+		maxCost:  config.MaxCost,
+	}
+}
+
+// check analyzes a request, returning an error if it exceeds the limits. The
+// requests which can't be analyzed are rejected as well, as they can't be told
+// apart from the ones exploiting the differences to the executing parser.
+func (a *costAnalyzer) check(query string, operationName string) *errors.QueryError {
+	doc, err := parseDocument(query)
+	if err != nil {
+		return &errors.QueryError{
+			Message:    err.Error(),
+			Extensions: map[string]interface{}{"code": errCodeParseFailed},
+		}
+	}
+	depth, cost := a.analyze(doc, operationName)
+	queryDepthHistogram.Update(int64(depth))
+	if cost > math.MaxInt64 {
+		queryCostHistogram.Update(math.MaxInt64)
+	} else {
+		queryCostHistogram.Update(int64(cost))
+	}
+
+	if a.maxDepth > 0 && depth > a.maxDepth {
+		tooDeepMeter.Mark(1)
+		return &errors.QueryError{
+			Message: fmt.Sprintf("query depth %d exceeds the limit of %d", depth, a.maxDepth),
+			Extensions: map[string]interface{}{
+				"code":     errCodeQueryTooDeep,
+				"depth":    depth,
+				"maxDepth": a.maxDepth,
+			},
+		}
+	}
+	if a.maxCost > 0 && cost > a.maxCost {
+		tooCostlyMeter.Mark(1)
+		return &errors.QueryError{
+			Message: fmt.Sprintf("query cost %d exceeds the limit of %d", cost, a.maxCost),
+			Extensions: map[string]interface{}{
+				"code":    errCodeQueryTooComplex,
+				"cost":    cost,
+				"maxCost": a.maxCost,
+			},
+		}
+	}
+	return nil
+}
+
+// analyze returns the depth and the cost of the executed operation, or the
+// maximum ones of all operations if it isn't named.
+func (a *costAnalyzer) analyze(doc *document, operationName string) (depth int, cost uint64) {
+	for _, op := range doc.operations {
+		if operationName != "" && op.name != operationName {
+			continue
+		}
+		root, ok := a.schema.EntryPoints[op.typ]
+		if !ok {
+			continue
+		}
+		d, c := a.selectionSet(doc, root.TypeName(), op.selections, make(map[string]bool))
+		if d > depth {
+			depth = d
+		}
+		if c > cost {
+			cost = c
+		}
+	}
+	return depth, cost
+}
+
+// selectionSet returns the depth and the cost of the selections of a type.
+// The introspection fields are free, being bounded by the schema size.
+func (a *costAnalyzer) selectionSet(doc *document, typeName string, sels []*selection, fragments map[string]bool) (depth int, cost uint64) {
+	for _, sel := range sels {
+		var (
+			d int
+			c uint64
+		)
+		switch {
+		case sel.spread != "":
+			frag, ok := doc.fragments[sel.spread]
+			if !ok || fragments[sel.spread] {
+				continue // Unknown or cyclic fragment, rejected by the validation
+			}
+			fragments[sel.spread] = true
+			d, c = a.selectionSet(doc, frag.typeCond, frag.selections, fragments)
+			delete(fragments, sel.spread)
+
+		case sel.field == "":
+			cond := typeName
+			if sel.typeCond != "" {
+				cond = sel.typeCond
+			}
+			d, c = a.selectionSet(doc, cond, sel.selections, fragments)
+
+		case strings.HasPrefix(sel.field, "__"):
+			continue
+
+		default:
+			fieldType, list := a.fieldType(typeName, sel.field)
+			d, c = a.selectionSet(doc, fieldType, sel.selections, fragments)
+			if list {
+				c = mulCost(c, listSizeEstimate)
+			}
+			d, c = d+1, addCost(c, a.fieldCost(typeName, sel.field))
+		}
+		if d > depth {
+			depth = d
+		}
+		cost = addCost(cost, c)
+	}
+	return depth, cost
+}
+
+// fieldType returns the name of the type of a field, and whether it is a list.
+func (a *costAnalyzer) fieldType(typeName string, field string) (string, bool) {
+	var fields types.FieldsDefinition
+	switch t := a.schema.Types[typeName].(type) {
+	case *types.ObjectTypeDefinition:
+		fields = t.Fields
+	case *types.InterfaceTypeDefinition:
+		fields = t.Fields
+	default:
+		return "", false
+	}
+	def := fields.Get(field)
+	if def == nil {
+		return "", false
+	}
+	var (
+		typ  = def.Type
+		list bool
+	)
+	for {
+		switch t := typ.(type) {
+		case *types.NonNull:
+			typ = t.OfType
+		case *types.List:
+			typ, list = t.OfType, true
+		case types.NamedType:
+			return t.TypeName(), list
+		default:
+			return "", list
+		}
+	}
+}
+
+func (a *costAnalyzer) fieldCost(typeName string, field string) uint64 {
+	if cost, ok := a.costs[typeName+"."+field]; ok {
+		return cost
+	}
+	return 1
+}
+
+// addCost and mulCost saturate instead of overflowing.
+func addCost(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
+
+func mulCost(a, b uint64) uint64 {
+	if b != 0 && a > math.MaxUint64/b {
+		return math.MaxUint64
+	}
+	return a * b
+}
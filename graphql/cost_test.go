@@ -0,0 +1,142 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/graph-gophers/graphql-go"
+)
+
+func TestParseDocument(t *testing.T) {
+	valid := []string{
+		`{ block { number } }`,
+		`query Q($n: Long = 1, $h: [Bytes32!]!) @dir { a: block(number: $n) { hash @include(if: true) } }`,
+		`{ logs(filter: {addresses: ["0x01", "0x02"], topics: [[], ["0x03"]], fromBlock: -1.5e3}) { data } }`,
+		"{ block(hash: \"\"\"block \\\"\"\" string\"\"\") { number } } # comment",
+		`query { ...F ... on Query { chainID } ... @skip(if: false) { gasPrice } } fragment F on Query { syncing { currentBlock } }`,
+		`mutation { sendRawTransaction(data: "0x\"00") } subscription S { newBlock { number } }`,
+		`{ é: block { ñumber2 } }`,
+	}
+	for _, query := range valid {
+		if _, err := parseDocument(query); err != nil {
+			t.Errorf("failed to parse %q: %v", query, err)
+		}
+	}
+	invalid := []string{
+		`{ block { number }`,
+		`{ block(number: ) { number } }`,
+		`{ block(hash: "0x) { number } }`,
+		`fragment F { number }`,
+		`{ block { number } } %`,
+		`{ 2a: block { number } }`,
+	}
+	for _, query := range invalid {
+		if _, err := parseDocument(query); err == nil {
+			t.Errorf("parsed invalid document %q", query)
+		}
+	}
+}
+
+func TestCostAnalysis(t *testing.T) {
+	s, err := graphql.ParseSchema(schema, nil)
+	if err != nil {
+		t.Fatalf("could not parse schema: %v", err)
+	}
+	tests := []struct {
+		query     string
+		operation string
+		costs     map[string]uint64
+		depth     int
+		cost      uint64
+	}{
+		{query: `{ block { number } }`, depth: 2, cost: 2},
+		{query: `{ block { number } }`, costs: map[string]uint64{"Block.number": 5}, depth: 2, cost: 6},
+		{query: `{ block { transactions { hash } } }`, depth: 3, cost: 1 + 10 + listSizeEstimate},
+		{
+			query: `{ block { transactions { logs { account { balance } } } } }`,
+			depth: 5,
+			cost:  1 + 10 + listSizeEstimate*(10+listSizeEstimate*(1+10)),
+		},
+		{query: `query { block { ...F } } fragment F on Block { hash parent { hash } }`, depth: 3, cost: 4},
+		{query: `{ pending { ... on Pending { transactionCount } } }`, depth: 2, cost: 2},
+		{query: `{ __schema { types { fields { name } } } block { __typename } }`, depth: 1, cost: 1},
+		{query: `query A { chainID } query B { block { number } }`, depth: 2, cost: 2},
+		{query: `query A { chainID } query B { block { number } }`, operation: "A", depth: 1, cost: 1},
+		{query: `{ ...F } fragment F on Query { ...F chainID }`, depth: 1, cost: 1},
+		{query: `mutation { sendRawTransaction(data: "0x") }`, depth: 1, cost: 1},
+	}
+	for i, tt := range tests {
+		doc, err := parseDocument(tt.query)
+		if err != nil {
+			t.Fatalf("test %d: could not parse query: %v", i, err)
+		}
+		depth, cost := newCostAnalyzer(s.ASTSchema(), CostConfig{FieldCosts: tt.costs}).analyze(doc, tt.operation)
+		if depth != tt.depth || cost != tt.cost {
+			t.Errorf("test %d: have depth %d cost %d, want depth %d cost %d", i, depth, cost, tt.depth, tt.cost)
+		}
+	}
+}
+
+func TestCostLimits(t *testing.T) {
+	stack, err := node.New(&node.DefaultConfig)
+	if err != nil {
+		t.Fatalf("could not create new node: %v", err)
+	}
+	defer stack.Close()
+
+	h, err := newHandler(stack, nil, nil, []string{}, []string{}, CostConfig{MaxDepth: 3, MaxCost: 1000})
+	if err != nil {
+		t.Fatalf("could not create graphql service: %v", err)
+	}
+	tests := []struct {
+		query string
+		code  string
+	}{
+		{query: `{ block { transactions { logs { index } } } }`, code: errCodeQueryTooDeep},
+		{query: `{ é: block { transactions { logs { account { balance } } } } }`, code: errCodeQueryTooDeep},
+		{query: `{ block { number }`, code: errCodeParseFailed},
+		{query: `{ block { transactions { from { balance } } } }`, code: errCodeQueryTooDeep},
+		{query: `{ blocks(from: 0) { transactions { hash } } }`, code: errCodeQueryTooComplex},
+	}
+	for i, tt := range tests {
+		body, _ := json.Marshal(map[string]string{"query": tt.query})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("test %d: status mismatch: have %d, want %d", i, rec.Code, http.StatusBadRequest)
+		}
+		var res struct {
+			Errors []struct {
+				Message    string                 `json:"message"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("test %d: could not decode response: %v", i, err)
+		}
+		if len(res.Errors) != 1 || res.Errors[0].Extensions["code"] != tt.code {
+			t.Errorf("test %d: unexpected response: %s", i, rec.Body)
+		}
+	}
+}
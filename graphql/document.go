@@ -0,0 +1,406 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// document is the outline of an executable GraphQL document, holding just the
+// selections needed by the static query analysis. Arguments, variables and
+// directives are parsed but dropped.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	name       string
+	typ        string // query, mutation or subscription
+	selections []*selection
+}
+
+type fragment struct {
+	typeCond   string
+	selections []*selection
+}
+
+// selection is a field, a fragment spread or an inline fragment.
+type selection struct {
+	field      string // Name of the selected field, empty for fragments
+	spread     string // Name of the spread fragment
+	typeCond   string // Type condition of the inline fragment, if any
+	selections []*selection
+}
+
+// parseDocument parses an executable GraphQL document.
+func parseDocument(query string) (doc *document, err error) {
+	p := &docParser{lex: lexer{src: query}}
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(docError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, perr
+		}
+	}()
+	p.next()
+
+	doc = &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is(tokPunct, "{"):
+			doc.operations = append(doc.operations, &operation{typ: "query", selections: p.selectionSet()})
+
+		case p.tok.is(tokName, "query"), p.tok.is(tokName, "mutation"), p.tok.is(tokName, "subscription"):
+			op := &operation{typ: p.tok.val}
+			p.next()
+			if p.tok.kind == tokName {
+				op.name = p.name()
+			}
+			if p.tok.is(tokPunct, "(") {
+				p.variableDefinitions()
+			}
+			p.directives()
+			op.selections = p.selectionSet()
+			doc.operations = append(doc.operations, op)
+
+		case p.tok.is(tokName, "fragment"):
+			p.next()
+			name := p.name()
+			p.expectName("on")
+			frag := &fragment{typeCond: p.name()}
+			p.directives()
+			frag.selections = p.selectionSet()
+			doc.fragments[name] = frag
+
+		default:
+			p.fail("unexpected %s", p.tok)
+		}
+	}
+	return doc, nil
+}
+
+// docError is a syntax error of a document.
+type docError struct {
+	msg string
+	pos int
+}
+
+func (e docError) Error() string {
+	return fmt.Sprintf("syntax error at offset %d: %s", e.pos, e.msg)
+}
+
+type docParser struct {
+	lex lexer
+	tok token
+}
+
+func (p *docParser) fail(format string, args ...interface{}) {
+	panic(docError{msg: fmt.Sprintf(format, args...), pos: p.tok.pos})
+}
+
+func (p *docParser) next() {
+	tok, err := p.lex.next()
+	if err != nil {
+		panic(docError{msg: err.Error(), pos: p.lex.pos})
+	}
+	p.tok = tok
+}
+
+func (p *docParser) expect(val string) {
+	if !p.tok.is(tokPunct, val) {
+		p.fail("expected %q, got %s", val, p.tok)
+	}
+	p.next()
+}
+
+func (p *docParser) expectName(val string) {
+	if !p.tok.is(tokName, val) {
+		p.fail("expected %q, got %s", val, p.tok)
+	}
+	p.next()
+}
+
+func (p *docParser) name() string {
+	if p.tok.kind != tokName {
+		p.fail("expected name, got %s", p.tok)
+	}
+	name := p.tok.val
+	p.next()
+	return name
+}
+
+func (p *docParser) selectionSet() []*selection {
+	p.expect("{")
+	var sels []*selection
+	for !p.tok.is(tokPunct, "}") {
+		sels = append(sels, p.selection())
+	}
+	p.next()
+	return sels
+}
+
+func (p *docParser) selection() *selection {
+	if p.tok.is(tokPunct, "...") {
+		p.next()
+		if p.tok.kind == tokName && p.tok.val != "on" {
+			sel := &selection{spread: p.name()}
+			p.directives()
+			return sel
+		}
+		sel := new(selection)
+		if p.tok.is(tokName, "on") {
+			p.next()
+			sel.typeCond = p.name()
+		}
+		p.directives()
+		sel.selections = p.selectionSet()
+		return sel
+	}
+	sel := &selection{field: p.name()}
+	if p.tok.is(tokPunct, ":") {
+		p.next()
+		sel.field = p.name() // The first name was an alias
+	}
+	if p.tok.is(tokPunct, "(") {
+		p.arguments()
+	}
+	p.directives()
+	if p.tok.is(tokPunct, "{") {
+		sel.selections = p.selectionSet()
+	}
+	return sel
+}
+
+func (p *docParser) arguments() {
+	p.expect("(")
+	for !p.tok.is(tokPunct, ")") {
+		p.name()
+		p.expect(":")
+		p.value()
+	}
+	p.next()
+}
+
+func (p *docParser) directives() {
+	for p.tok.is(tokPunct, "@") {
+		p.next()
+		p.name()
+		if p.tok.is(tokPunct, "(") {
+			p.arguments()
+		}
+	}
+}
+
+func (p *docParser) variableDefinitions() {
+	p.expect("(")
+	for !p.tok.is(tokPunct, ")") {
+		p.expect("$")
+		p.name()
+		p.expect(":")
+		p.typeRef()
+		if p.tok.is(tokPunct, "=") {
+			p.next()
+			p.value()
+		}
+		p.directives()
+	}
+	p.next()
+}
+
+func (p *docParser) typeRef() {
+	if p.tok.is(tokPunct, "[") {
+		p.next()
+		p.typeRef()
+		p.expect("]")
+	} else {
+		p.name()
+	}
+	if p.tok.is(tokPunct, "!") {
+		p.next()
+	}
+}
+
+func (p *docParser) value() {
+	switch {
+	case p.tok.is(tokPunct, "$"):
+		p.next()
+		p.name()
+	case p.tok.is(tokPunct, "["):
+		p.next()
+		for !p.tok.is(tokPunct, "]") {
+			p.value()
+		}
+		p.next()
+	case p.tok.is(tokPunct, "{"):
+		p.next()
+		for !p.tok.is(tokPunct, "}") {
+			p.name()
+			p.expect(":")
+			p.value()
+		}
+		p.next()
+	case p.tok.kind == tokName, p.tok.kind == tokNumber, p.tok.kind == tokString:
+		p.next()
+	default:
+		p.fail("unexpected %s", p.tok)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokNumber
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) is(kind tokenKind, val string) bool {
+	return t.kind == kind && t.val == val
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of document"
+	}
+	return fmt.Sprintf("%q", t.val)
+}
+
+var errUnterminatedString = errors.New("unterminated string")
+
+// lexer splits a GraphQL document into tokens, skipping the ignored ones.
+type lexer struct {
+	src string
+	pos int
+}
+
+// skipIgnored skips the whitespace, commas, comments and byte order marks.
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, val: "...", pos: start}, nil
+
+	case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, val: string(c), pos: start}, nil
+
+	case l.identRune(0) > 0:
+		for i := 0; ; i++ {
+			size := l.identRune(i)
+			if size == 0 {
+				break
+			}
+			l.pos += size
+		}
+		return token{kind: tokName, val: l.src[start:l.pos], pos: start}, nil
+
+	case c == '-' || isDigit(c):
+		l.pos++
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || isLetter(l.src[l.pos]) || l.src[l.pos] == '.' || l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		return token{kind: tokNumber, val: l.src[start:l.pos], pos: start}, nil
+
+	case strings.HasPrefix(l.src[l.pos:], `"""`):
+		for i := l.pos + 3; i < len(l.src); i++ {
+			switch {
+			case strings.HasPrefix(l.src[i:], `\"""`):
+				i += 3
+			case strings.HasPrefix(l.src[i:], `"""`):
+				l.pos = i + 3
+				return token{kind: tokString, val: l.src[start:l.pos], pos: start}, nil
+			}
+		}
+		return token{}, errUnterminatedString
+
+	case c == '"':
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] != '"' {
+			if l.src[l.pos] == '\\' {
+				l.pos++
+			}
+			if l.pos < len(l.src) && (l.src[l.pos] == '\n' || l.src[l.pos] == '\r') {
+				return token{}, errUnterminatedString
+			}
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, errUnterminatedString
+		}
+		l.pos++
+		return token{kind: tokString, val: l.src[start:l.pos], pos: start}, nil
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, fmt.Errorf("unexpected character %q", r)
+}
+
+// identRune returns the size of the rune at the current position if it may be
+// the i-th one of a name, or zero otherwise. Names are scanned like graphql-go
+// does, allowing Unicode letters and digits, so the analysis sees the same
+// selections as the execution.
+func (l *lexer) identRune(i int) int {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+		return size
+	}
+	return 0
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
@@ -33,11 +33,6 @@ const schema string = `
     # 0x-prefixed hexadecimal.
     scalar Long
 
-    schema {
-        query: Query
-        mutation: Mutation
-    }
-
     # Account is an Ethereum account at a particular block.
     type Account {
         # Address is the address owning the account.
@@ -392,3 +387,31 @@ const schema string = `
         sendRawTransaction(data: Bytes!): Bytes32!
     }
 `
+
+// subscriptionSchema extends schema with the subscriptions served over
+// websocket. It is parsed on its own, because graphql-go resolves all root
+// operation types on the same resolver, where the logs query and subscription
+// would clash.
+const subscriptionSchema string = `
+    schema {
+        query: SubscriptionQuery
+        subscription: Subscription
+    }
+
+    # SubscriptionQuery is the query root of the websocket endpoint, which only
+    # serves subscriptions. Queries and mutations are served over HTTP.
+    type SubscriptionQuery {
+        # ChainID returns the current chain ID for transaction replay protection.
+        chainID: BigInt!
+    }
+
+    type Subscription {
+        # NewBlock fires for every block added to the canonical chain.
+        newBlock: Block!
+        # Logs fires for every new log entry matching the provided filter, with
+        # the same semantics as the logs subscription of the JSON-RPC API.
+        logs(filter: FilterCriteria!): Log!
+        # PendingTransaction fires for every transaction added to the pool.
+        pendingTransaction: Transaction!
+    }
+`
@@ -28,12 +28,16 @@ import (
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
 	"github.com/graph-gophers/graphql-go"
 	gqlErrors "github.com/graph-gophers/graphql-go/errors"
 )
 
 type handler struct {
-	Schema *graphql.Schema
+	Schema        *graphql.Schema
+	Subscriptions *graphql.Schema // Schema of the subscriptions served over websocket
+
+	costs *costAnalyzer
 }
 
 func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +50,14 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	// Reject the too complex queries before executing them
+	if err := h.costs.check(params.Query, params.OperationName); err != nil {
+		responseJSON, _ := json.Marshal(&graphql.Response{Errors: []*gqlErrors.QueryError{err}})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(responseJSON)
+		return
+	}
 
 	var (
 		ctx       = r.Context()
@@ -105,23 +117,44 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// New constructs a new GraphQL service instance.
-func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) error {
-	_, err := newHandler(stack, backend, filterSystem, cors, vhosts)
+// New constructs a new GraphQL service instance. The queries exceeding the
+// given limits are rejected.
+func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string, limits CostConfig) error {
+	_, err := newHandler(stack, backend, filterSystem, cors, vhosts, limits)
 	return err
 }
 
 // newHandler returns a new `http.Handler` that will answer GraphQL queries.
+// Websocket connections to the same endpoint are served subscriptions.
 // It additionally exports an interactive query browser on the / endpoint.
-func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) (*handler, error) {
+func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string, limits CostConfig) (*handler, error) {
 	q := Resolver{backend, filterSystem}
 
-	s, err := graphql.ParseSchema(schema, &q)
+	// The executor enforces the depth limit too, in case the analysis misses
+	s, err := graphql.ParseSchema(schema, &q, graphql.MaxDepth(limits.MaxDepth))
+	if err != nil {
+		return nil, err
+	}
+	subs, err := graphql.ParseSchema(schema+subscriptionSchema, &SubscriptionResolver{r: &q}, graphql.MaxDepth(limits.MaxDepth))
 	if err != nil {
 		return nil, err
 	}
-	h := handler{Schema: s}
-	handler := node.NewHTTPHandlerStack(h, cors, vhosts, nil)
+	h := handler{
+		Schema:        s,
+		Subscriptions: subs,
+		costs:         newCostAnalyzer(s.ASTSchema(), limits),
+	}
+	var (
+		httpHandler = node.NewHTTPHandlerStack(h, cors, vhosts, nil)
+		wsHandler   = node.NewWSHandlerStack(newWSHandler(subs, newCostAnalyzer(subs.ASTSchema(), limits), cors), nil)
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			wsHandler.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
 
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui/", GraphiQL{})
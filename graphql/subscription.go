@@ -0,0 +1,170 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// The sizes of the event buffers, matching the JSON-RPC subscriptions.
+const (
+	headersChanSize = 0
+	logsChanSize    = 0
+	txsChanSize     = 128
+)
+
+// SubscriptionResolver is the top-level object of the GraphQL subscriptions.
+// The events are delivered by the same event system as the JSON-RPC filters,
+// which is only started with the first subscription.
+type SubscriptionResolver struct {
+	r *Resolver
+
+	eventsOnce sync.Once
+	events     *filters.EventSystem
+}
+
+func (s *SubscriptionResolver) eventSystem() *filters.EventSystem {
+	s.eventsOnce.Do(func() {
+		s.events = filters.NewEventSystem(s.r.filterSystem, false)
+	})
+	return s.events
+}
+
+// ChainID returns the current chain ID for transaction replay protection.
+func (s *SubscriptionResolver) ChainID(ctx context.Context) (hexutil.Big, error) {
+	return s.r.ChainID(ctx)
+}
+
+// NewBlock streams the blocks added to the canonical chain.
+func (s *SubscriptionResolver) NewBlock(ctx context.Context) (<-chan *Block, error) {
+	var (
+		headers = make(chan *types.Header, headersChanSize)
+		sub     = s.eventSystem().SubscribeNewHeads(headers)
+		out     = make(chan *Block)
+	)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case header := <-headers:
+				numberOrHash := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+				block := &Block{
+					r:            s.r,
+					numberOrHash: &numberOrHash,
+					hash:         header.Hash(),
+					header:       header,
+				}
+				select {
+				case out <- block:
+				case <-ctx.Done():
+					return
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Logs streams the new logs matching the given filter.
+func (s *SubscriptionResolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria }) (<-chan *Log, error) {
+	var crit ethereum.FilterQuery
+	if args.Filter.FromBlock != nil {
+		crit.FromBlock = big.NewInt(int64(*args.Filter.FromBlock))
+	}
+	if args.Filter.ToBlock != nil {
+		crit.ToBlock = big.NewInt(int64(*args.Filter.ToBlock))
+	}
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	logs := make(chan []*types.Log, logsChanSize)
+	sub, err := s.eventSystem().SubscribeLogs(crit, logs)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan *Log)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case batch := <-logs:
+				for _, log := range batch {
+					select {
+					case out <- &Log{r: s.r, transaction: &Transaction{r: s.r, hash: log.TxHash}, log: log}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// PendingTransaction streams the transactions added to the pool.
+func (s *SubscriptionResolver) PendingTransaction(ctx context.Context) (<-chan *Transaction, error) {
+	var (
+		txs = make(chan []*types.Transaction, txsChanSize)
+		sub = s.eventSystem().SubscribePendingTxs(txs)
+		out = make(chan *Transaction)
+	)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case batch := <-txs:
+				for _, tx := range batch {
+					select {
+					case out <- &Transaction{r: s.r, hash: tx.Hash(), tx: tx}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
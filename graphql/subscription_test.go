@@ -0,0 +1,210 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// wsClient is a minimal graphql-ws client.
+type wsClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+}
+
+func dialGQLWebsocket(t *testing.T, endpoint string) *wsClient {
+	t.Helper()
+
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(endpoint, "http")+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("could not dial websocket: %v", err)
+	}
+	if conn.Subprotocol() != wsProtocol {
+		t.Fatalf("subprotocol mismatch: have %q, want %q", conn.Subprotocol(), wsProtocol)
+	}
+	c := &wsClient{t: t, conn: conn}
+	c.send("", gqlConnectionInit, nil)
+	c.expect("", gqlConnectionAck)
+	return c
+}
+
+func (c *wsClient) send(id string, typ string, payload interface{}) {
+	c.t.Helper()
+
+	msg := &wsMessage{ID: id, Type: typ}
+	if payload != nil {
+		blob, err := json.Marshal(payload)
+		if err != nil {
+			c.t.Fatalf("could not encode payload: %v", err)
+		}
+		msg.Payload = blob
+	}
+	if err := c.conn.WriteJSON(msg); err != nil {
+		c.t.Fatalf("could not send message: %v", err)
+	}
+}
+
+func (c *wsClient) start(id string, query string) {
+	c.t.Helper()
+	c.send(id, gqlStart, map[string]interface{}{"query": query})
+}
+
+// read returns the next message, skipping the keep-alives.
+func (c *wsClient) read() *wsMessage {
+	c.t.Helper()
+
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		msg := new(wsMessage)
+		if err := c.conn.ReadJSON(msg); err != nil {
+			c.t.Fatalf("could not read message: %v", err)
+		}
+		if msg.Type != gqlConnectionKeepAlive {
+			return msg
+		}
+	}
+}
+
+func (c *wsClient) expect(id string, typ string) *wsMessage {
+	c.t.Helper()
+
+	msg := c.read()
+	if msg.ID != id || msg.Type != typ {
+		c.t.Fatalf("unexpected message: have %s/%s %s, want %s/%s", msg.ID, msg.Type, msg.Payload, id, typ)
+	}
+	return msg
+}
+
+// sync waits for all previously sent messages to be processed, relying on the
+// in order processing of the messages of a connection.
+func (c *wsClient) sync() {
+	c.t.Helper()
+	c.start("sync", "subscription { unknown }")
+	c.expect("sync", gqlError)
+}
+
+func TestGraphQLSubscriptions(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		genesis = &core.Genesis{
+			Config:     params.AllEthashProtocolChanges,
+			GasLimit:   11500000,
+			Difficulty: big.NewInt(1048576),
+			Alloc:      core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	stack := createNode(t)
+	defer stack.Close()
+
+	ethBackend, err := eth.New(stack, &ethconfig.Config{
+		Genesis:        genesis,
+		NetworkId:      1337,
+		TrieCleanCache: 5,
+		TrieDirtyCache: 5,
+		TrieTimeout:    60 * time.Minute,
+		SnapshotCache:  5,
+	})
+	if err != nil {
+		t.Fatalf("could not create eth backend: %v", err)
+	}
+	chain, _ := core.GenerateChain(genesis.Config, ethBackend.BlockChain().Genesis(), ethash.NewFaker(), ethBackend.ChainDb(), 1, nil)
+
+	filterSystem := filters.NewFilterSystem(ethBackend.APIBackend, filters.Config{})
+	if _, err := newHandler(stack, ethBackend.APIBackend, filterSystem, []string{}, []string{}, CostConfig{}); err != nil {
+		t.Fatalf("could not create graphql service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	client := dialGQLWebsocket(t, stack.HTTPEndpoint())
+	defer client.conn.Close()
+
+	client.start("heads", "subscription { newBlock { number hash } }")
+	client.start("txs", "subscription { pendingTransaction { hash nonce } }")
+	client.sync()
+
+	// Import a block and check it is announced
+	if _, err := ethBackend.BlockChain().InsertChain(chain); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	msg := client.expect("heads", gqlData)
+	want := `{"data":{"newBlock":{"number":"0x1","hash":"` + chain[0].Hash().Hex() + `"}}}`
+	if string(msg.Payload) != want {
+		t.Errorf("new block mismatch:\nhave: %s\nwant: %s", msg.Payload, want)
+	}
+	// Add a transaction to the pool and check it is announced. Its fee is
+	// below the base fee, sparing its execution in the pending block.
+	tx, _ := types.SignTx(types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), types.LatestSigner(genesis.Config), key)
+	if err := ethBackend.APIBackend.SendTx(context.Background(), tx); err != nil {
+		t.Fatalf("could not send transaction: %v", err)
+	}
+	msg = client.expect("txs", gqlData)
+	want = `{"data":{"pendingTransaction":{"hash":"` + tx.Hash().Hex() + `","nonce":"0x0"}}}`
+	if string(msg.Payload) != want {
+		t.Errorf("pending transaction mismatch:\nhave: %s\nwant: %s", msg.Payload, want)
+	}
+	// Stopping a subscription completes it
+	client.send("heads", gqlStop, nil)
+	client.expect("heads", gqlComplete)
+
+	// Subscription ids may not be reused while running
+	client.start("txs", "subscription { pendingTransaction { hash } }")
+	client.expect("txs", gqlError)
+
+	// Queries are not served over websocket
+	client.start("query", "{ block { number } }")
+	client.expect("query", gqlError)
+}
+
+func TestGraphQLSubscriptionLimits(t *testing.T) {
+	stack := createNode(t)
+	defer stack.Close()
+
+	handler, _ := newGQLService(t, stack, false, &core.Genesis{Config: params.AllEthashProtocolChanges}, 0, nil)
+
+	// The logs subscription has the same topic limit as the JSON-RPC one
+	topics := strings.Repeat(`["0x0000000000000000000000000000000000000000000000000000000000000000"],`, 5)
+	query := "subscription { logs(filter: {topics: [" + topics + "]}) { data } }"
+
+	responses, err := handler.Subscriptions.Subscribe(context.Background(), query, "", nil)
+	if err != nil {
+		t.Fatalf("could not subscribe: %v", err)
+	}
+	res := (<-responses).(*graphql.Response)
+	if len(res.Errors) != 1 || !strings.Contains(res.Errors[0].Message, "exceed max topics") {
+		t.Fatalf("unexpected response: %v", res.Errors)
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// The limits of the websocket connections, matching those of the JSON-RPC
+// websocket server.
+const (
+	wsProtocol          = "graphql-ws"
+	wsReadBuffer        = 1024
+	wsWriteBuffer       = 1024
+	wsReadLimit         = 32 * 1024 * 1024
+	wsWriteTimeout      = 10 * time.Second
+	wsKeepAliveInterval = 30 * time.Second
+)
+
+// Message types of the graphql-ws protocol.
+const (
+	gqlConnectionInit      = "connection_init"
+	gqlConnectionAck       = "connection_ack"
+	gqlConnectionError     = "connection_error"
+	gqlConnectionKeepAlive = "ka"
+	gqlConnectionTerminate = "connection_terminate"
+	gqlStart               = "start"
+	gqlStop                = "stop"
+	gqlData                = "data"
+	gqlError               = "error"
+	gqlComplete            = "complete"
+)
+
+var (
+	errNotInitialized = errors.New("connection not initialized")
+	errDuplicateID    = errors.New("subscription id already in use")
+)
+
+// wsMessage is a message of the graphql-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsHandler serves GraphQL subscriptions over websocket, speaking the
+// graphql-ws protocol.
+type wsHandler struct {
+	schema   *graphql.Schema
+	costs    *costAnalyzer
+	upgrader websocket.Upgrader
+}
+
+func newWSHandler(schema *graphql.Schema, costs *costAnalyzer, allowedOrigins []string) *wsHandler {
+	return &wsHandler{
+		schema: schema,
+		costs:  costs,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  wsReadBuffer,
+			WriteBufferSize: wsWriteBuffer,
+			Subprotocols:    []string{wsProtocol},
+			CheckOrigin:     wsOriginValidator(allowedOrigins),
+		},
+	}
+}
+
+// wsOriginValidator accepts the connections of the origins allowed by the CORS
+// configuration, along with the same origin ones.
+func wsOriginValidator(allowedOrigins []string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, allowed := range allowedOrigins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		u, err := url.Parse(origin)
+		if err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		log.Warn("Rejected GraphQL WebSocket connection", "origin", origin)
+		return false
+	}
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("GraphQL WebSocket upgrade failed", "err", err)
+		return
+	}
+	if conn.Subprotocol() != wsProtocol {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseProtocolError, "unsupported subprotocol"), time.Now().Add(wsWriteTimeout))
+		conn.Close()
+		return
+	}
+	c := &wsConn{
+		conn:   conn,
+		schema: h.schema,
+		costs:  h.costs,
+		subs:   make(map[string]*wsSubscription),
+	}
+	c.serve()
+}
+
+// wsConn is a websocket connection serving subscriptions.
+type wsConn struct {
+	conn   *websocket.Conn
+	schema *graphql.Schema
+	costs  *costAnalyzer
+
+	writeMu sync.Mutex // protects writes to conn
+
+	mu   sync.Mutex
+	subs map[string]*wsSubscription
+	wg   sync.WaitGroup
+}
+
+// wsSubscription is a running subscription of a connection.
+type wsSubscription struct {
+	cancel context.CancelFunc
+}
+
+// serve reads the client messages until the connection is closed or terminated.
+func (c *wsConn) serve() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		c.conn.Close()
+		c.wg.Wait()
+	}()
+	c.conn.SetReadLimit(wsReadLimit)
+
+	initialized := false
+	for {
+		var msg wsMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case gqlConnectionInit:
+			if initialized {
+				continue
+			}
+			initialized = true
+			c.write(&wsMessage{Type: gqlConnectionAck})
+			c.write(&wsMessage{Type: gqlConnectionKeepAlive})
+
+			c.wg.Add(1)
+			go c.keepAlive(ctx)
+
+		case gqlStart:
+			if !initialized {
+				c.writeError(gqlConnectionError, "", errNotInitialized)
+				return
+			}
+			c.start(ctx, msg.ID, msg.Payload)
+
+		case gqlStop:
+			c.stop(msg.ID)
+
+		case gqlConnectionTerminate:
+			return
+
+		default:
+			c.writeError(gqlError, msg.ID, errors.New("unknown message type "+msg.Type))
+		}
+	}
+}
+
+// start runs a subscription, streaming its results until it ends or is stopped.
+func (c *wsConn) start(connCtx context.Context, id string, payload json.RawMessage) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.Unmarshal(payload, &params); err != nil {
+		c.writeError(gqlError, id, err)
+		return
+	}
+	if err := c.costs.check(params.Query, params.OperationName); err != nil {
+		payload, _ := json.Marshal(err)
+		c.write(&wsMessage{ID: id, Type: gqlError, Payload: payload})
+		return
+	}
+	c.mu.Lock()
+	if _, ok := c.subs[id]; ok {
+		c.mu.Unlock()
+		c.writeError(gqlError, id, errDuplicateID)
+		return
+	}
+	ctx, cancel := context.WithCancel(connCtx)
+	sub := &wsSubscription{cancel: cancel}
+	c.subs[id] = sub
+	c.mu.Unlock()
+
+	responses, err := c.schema.Subscribe(ctx, params.Query, params.OperationName, params.Variables)
+	if err != nil {
+		c.remove(id, sub)
+		c.writeError(gqlError, id, err)
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer c.remove(id, sub)
+
+		first := true
+		for response := range responses {
+			res := response.(*graphql.Response)
+
+			// Failures before the execution, like invalid queries, end the
+			// subscription with an error message.
+			if first && res.Data == nil && len(res.Errors) > 0 {
+				errs, _ := json.Marshal(res.Errors)
+				c.write(&wsMessage{ID: id, Type: gqlError, Payload: errs})
+				return
+			}
+			first = false
+
+			data, err := json.Marshal(res)
+			if err != nil {
+				c.writeError(gqlError, id, err)
+				return
+			}
+			if c.write(&wsMessage{ID: id, Type: gqlData, Payload: data}) != nil {
+				return
+			}
+		}
+		if connCtx.Err() == nil {
+			c.write(&wsMessage{ID: id, Type: gqlComplete})
+		}
+	}()
+}
+
+// stop cancels a subscription, if it is running.
+func (c *wsConn) stop(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if sub, ok := c.subs[id]; ok {
+		sub.cancel()
+		delete(c.subs, id)
+	}
+}
+
+// remove releases an ended subscription, unless its id was reused since.
+func (c *wsConn) remove(id string, sub *wsSubscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub.cancel()
+	if c.subs[id] == sub {
+		delete(c.subs, id)
+	}
+}
+
+// keepAlive periodically sends keep-alive messages.
+func (c *wsConn) keepAlive(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if c.write(&wsMessage{Type: gqlConnectionKeepAlive}) != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// write sends a message, closing the connection if the client is too slow to
+// receive it.
+func (c *wsConn) write(msg *wsMessage) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	err := c.conn.WriteJSON(msg)
+	if err != nil {
+		c.conn.Close()
+	}
+	return err
+}
+
+// writeError sends an error message with the given type.
+func (c *wsConn) writeError(typ string, id string, err error) error {
+	payload, _ := json.Marshal(&gqlErrors.QueryError{Message: err.Error()})
+	return c.write(&wsMessage{ID: id, Type: typ, Payload: payload})
+}
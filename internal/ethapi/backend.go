@@ -98,11 +98,21 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription
 	BloomStatus() (uint64, uint64)
+	LogIndexStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 }
 
+// GetAPIs returns the RPC services of the given backend. A nil backend yields
+// the services without any, only to be inspected for their methods, e.g. to
+// document them.
 func GetAPIs(apiBackend Backend) []rpc.API {
-	nonceLock := new(AddrLocker)
+	var (
+		nonceLock = new(AddrLocker)
+		am        *accounts.Manager
+	)
+	if apiBackend != nil {
+		am = apiBackend.AccountManager()
+	}
 	return []rpc.API{
 		{
 			Namespace: "eth",
@@ -121,7 +131,7 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Service:   NewDebugAPI(apiBackend),
 		}, {
 			Namespace: "eth",
-			Service:   NewEthereumAccountAPI(apiBackend.AccountManager()),
+			Service:   NewEthereumAccountAPI(am),
 		}, {
 			Namespace: "personal",
 			Service:   NewPersonalAccountAPI(apiBackend, nonceLock),
@@ -0,0 +1,561 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks that can be simulated
+	// in a single request.
+	maxSimulateBlocks = 256
+
+	// timestampIncrement is the default increment between block timestamps.
+	timestampIncrement = 12
+)
+
+// Error codes returned by eth_simulateV1, see the execution-apis specification.
+const (
+	errCodeNonceTooHigh            = -38011
+	errCodeNonceTooLow             = -38010
+	errCodeIntrinsicGas            = -38013
+	errCodeInsufficientFunds       = -38014
+	errCodeBlockGasLimitReached    = -38015
+	errCodeBlockNumberInvalid      = -38020
+	errCodeBlockTimestampInvalid   = -38021
+	errCodeSenderIsNotEOA          = -38024
+	errCodeMaxInitCodeSizeExceeded = -38025
+	errCodeClientLimitExceeded     = -38026
+	errCodeFeeCapTooLow            = -32005
+	errCodeInternalError           = -32603
+	errCodeInvalidParams           = -32602
+	errCodeReverted                = -32000
+	errCodeVMError                 = -32015
+)
+
+var (
+	// transferAddress is the address emitting the synthetic logs of ETH
+	// transfers, as proposed by ERC-7528.
+	transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+	// transferTopic is the topic of the synthetic transfer logs, identical to
+	// the ERC-20 Transfer event.
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+)
+
+// simError is an API error with a JSON error code, aborting the simulation.
+type simError struct {
+	code int
+	msg  string
+}
+
+func (e *simError) Error() string { return e.msg }
+
+// ErrorCode returns the JSON error code of the simulation error.
+func (e *simError) ErrorCode() int { return e.code }
+
+// txValidationError maps the errors of a call failing the consensus checks
+// to their JSON error codes.
+func txValidationError(err error) *simError {
+	code := errCodeInternalError
+	switch {
+	case errors.Is(err, core.ErrNonceTooHigh):
+		code = errCodeNonceTooHigh
+	case errors.Is(err, core.ErrNonceTooLow):
+		code = errCodeNonceTooLow
+	case errors.Is(err, core.ErrSenderNoEOA):
+		code = errCodeSenderIsNotEOA
+	case errors.Is(err, core.ErrFeeCapTooLow):
+		code = errCodeFeeCapTooLow
+	case errors.Is(err, core.ErrGasLimitReached):
+		code = errCodeBlockGasLimitReached
+	case errors.Is(err, core.ErrInsufficientFunds), errors.Is(err, core.ErrInsufficientFundsForTransfer):
+		code = errCodeInsufficientFunds
+	case errors.Is(err, core.ErrIntrinsicGas):
+		code = errCodeIntrinsicGas
+	case errors.Is(err, core.ErrMaxInitCodeSizeExceeded):
+		code = errCodeMaxInitCodeSizeExceeded
+	}
+	return &simError{code: code, msg: err.Error()}
+}
+
+// simOpts are the inputs to eth_simulateV1.
+type simOpts struct {
+	BlockStateCalls        []simBlock
+	TraceTransfers         bool
+	Validation             bool
+	ReturnFullTransactions bool
+}
+
+// simBlock is a batch of calls to be simulated sequentially in a block, on top
+// of the optionally overridden state and header fields.
+type simBlock struct {
+	BlockOverrides *BlockOverrides
+	StateOverrides *StateOverride
+	Calls          []TransactionArgs
+}
+
+// simCallResult is the result of a simulated call.
+type simCallResult struct {
+	ReturnValue hexutil.Bytes  `json:"returnData"`
+	Logs        []*types.Log   `json:"logs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	Status      hexutil.Uint64 `json:"status"`
+	Error       *callError     `json:"error,omitempty"`
+}
+
+// callError is the error of a simulated call failing during execution, which
+// doesn't abort the simulation.
+type callError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    string `json:"data,omitempty"`
+}
+
+// simulator simulates a series of blocks on top of a base state. It's not safe
+// for concurrent use.
+type simulator struct {
+	b              Backend
+	state          *state.StateDB
+	base           *types.Header
+	chainConfig    *params.ChainConfig
+	timeout        time.Duration
+	budget         uint64 // Gas left from the global gas cap for all calls
+	traceTransfers bool
+	validate       bool
+	fullTx         bool
+}
+
+// execute runs the simulation of the given blocks, returning the simulated
+// blocks along with the results of their calls.
+func (sim *simulator) execute(ctx context.Context, blocks []simBlock) ([]map[string]interface{}, error) {
+	// Setup context so it may be cancelled when the simulation has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if sim.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, sim.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	blocks, err := sim.sanitizeChain(blocks)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		results = make([]map[string]interface{}, len(blocks))
+		parent  = sim.base
+		chain   = &simChainContext{ChainContext: NewChainContext(ctx, sim.b), headers: make(map[common.Hash]*types.Header)}
+	)
+	for bi, block := range blocks {
+		result, senders, calls, err := sim.processBlock(ctx, &block, parent, chain)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := RPCMarshalBlock(ctx, result, true, sim.fullTx, sim.chainConfig, sim.b)
+		if err != nil {
+			return nil, err
+		}
+		// The simulated transactions are unsigned, fill in their senders
+		if sim.fullTx {
+			for i, tx := range enc["transactions"].([]interface{}) {
+				tx.(*RPCTransaction).From = senders[i]
+			}
+		}
+		enc["calls"] = calls
+		results[bi] = enc
+
+		parent = result.Header()
+		chain.headers[parent.Hash()] = parent
+	}
+	return results, nil
+}
+
+// processBlock executes the calls of a simulated block on top of the parent,
+// returning the assembled block, the senders and results of its calls.
+func (sim *simulator) processBlock(ctx context.Context, block *simBlock, parent *types.Header, chain *simChainContext) (*types.Block, []common.Address, []simCallResult, error) {
+	header := sim.makeHeader(block.BlockOverrides, parent)
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, nil, nil, err
+	}
+	var (
+		gasUsed  uint64
+		txs      = make([]*types.Transaction, len(block.Calls))
+		receipts = make([]*types.Receipt, len(block.Calls))
+		senders  = make([]common.Address, len(block.Calls))
+		results  = make([]simCallResult, len(block.Calls))
+		tracer   = newSimTracer(sim.traceTransfers, header.Number.Uint64())
+		vmConfig = &vm.Config{NoBaseFee: !sim.validate, Tracer: tracer}
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		blockCtx = core.NewEVMBlockContext(header, chain, nil, sim.chainConfig, sim.state)
+	)
+	if block.BlockOverrides.BlobBaseFee != nil {
+		blockCtx.BlobBaseFee = block.BlockOverrides.BlobBaseFee.ToInt()
+	}
+	for i, call := range block.Calls {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := sim.sanitizeCall(&call, gp); err != nil {
+			return nil, nil, nil, err
+		}
+		tx := call.ToTransaction()
+		txs[i], senders[i] = tx, call.from()
+
+		msg, err := call.ToMessage(0, header.BaseFee)
+		if err != nil {
+			return nil, nil, nil, &simError{code: errCodeInvalidParams, msg: err.Error()}
+		}
+		msg.Nonce = uint64(*call.Nonce)
+		msg.SkipAccountChecks = !sim.validate
+
+		sim.state.SetTxContext(tx.Hash(), i)
+		tracer.reset(tx.Hash(), uint(i))
+
+		evm, vmError := sim.b.GetEVM(ctx, msg, sim.state, header, vmConfig, &blockCtx)
+		stop := context.AfterFunc(ctx, evm.Cancel)
+		result, err := core.ApplyMessage(evm, msg, gp)
+		stop()
+
+		if evm.Cancelled() {
+			return nil, nil, nil, fmt.Errorf("execution aborted (timeout = %v)", sim.timeout)
+		}
+		if err == nil {
+			err = vmError()
+		}
+		if err != nil {
+			return nil, nil, nil, txValidationError(err)
+		}
+		sim.budget -= result.UsedGas
+
+		// Finalize the state like a real transaction and assemble the receipt
+		var root []byte
+		if sim.chainConfig.IsByzantium(header.Number) {
+			sim.state.Finalise(true)
+		} else {
+			root = sim.state.IntermediateRoot(sim.chainConfig.IsEIP158(header.Number)).Bytes()
+		}
+		gasUsed += result.UsedGas
+
+		logs := tracer.Logs()
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			PostState:         root,
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: gasUsed,
+			Logs:              logs,
+			TxHash:            tx.Hash(),
+			GasUsed:           result.UsedGas,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		if msg.To == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From, tx.Nonce())
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts[i] = receipt
+
+		results[i] = simCallResult{
+			ReturnValue: result.Return(),
+			Logs:        logs,
+			GasUsed:     hexutil.Uint64(result.UsedGas),
+			Status:      hexutil.Uint64(receipt.Status),
+		}
+		if result.Failed() {
+			if errors.Is(result.Err, vm.ErrExecutionReverted) {
+				revert := newRevertError(result)
+				results[i].Error = &callError{Message: revert.Error(), Code: errCodeReverted, Data: revert.reason}
+			} else {
+				results[i].Error = &callError{Message: result.Err.Error(), Code: errCodeVMError}
+			}
+		}
+	}
+	header.GasUsed = gasUsed
+	header.Root = sim.state.IntermediateRoot(sim.chainConfig.IsEIP158(header.Number))
+
+	var withdrawals []*types.Withdrawal
+	if sim.chainConfig.IsShanghai(header.Number, header.Time) {
+		withdrawals = make([]*types.Withdrawal, 0)
+	}
+	result := types.NewBlockWithWithdrawals(header, txs, nil, receipts, withdrawals, trie.NewStackTrie(nil))
+
+	// The block hash is only known now, fill it into the logs
+	hash := result.Hash()
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			log.BlockHash = hash
+		}
+	}
+	return result, senders, results, nil
+}
+
+// makeHeader assembles the header of a simulated block from the parent and the
+// overridden fields.
+func (sim *simulator) makeHeader(overrides *BlockOverrides, parent *types.Header) *types.Header {
+	header := overrides.MakeHeader(&types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: parent.Difficulty,
+		GasLimit:   parent.GasLimit,
+		MixDigest:  parent.MixDigest,
+	})
+	if header.BaseFee == nil && sim.chainConfig.IsLondon(header.Number) {
+		// Without validation, calls are free unless a base fee is requested
+		if sim.validate {
+			header.BaseFee = eip1559.CalcBaseFee(sim.chainConfig, parent, header.Time)
+		} else {
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if sim.chainConfig.IsCancun(header.Number, header.Time) {
+		var excess uint64
+		if parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+			excess = eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		}
+		header.ExcessBlobGas = &excess
+		header.BlobGasUsed = new(uint64)
+		header.ParentBeaconRoot = new(common.Hash)
+	}
+	return header
+}
+
+// sanitizeChain checks the numbers and timestamps of the simulated blocks, filling
+// in the missing ones and any gaps between the blocks with empty blocks.
+func (sim *simulator) sanitizeChain(blocks []simBlock) ([]simBlock, error) {
+	var (
+		res           = make([]simBlock, 0, len(blocks))
+		prevNumber    = sim.base.Number
+		prevTimestamp = sim.base.Time
+	)
+	for _, block := range blocks {
+		// Copy the overrides to avoid modifying the request
+		overrides := new(BlockOverrides)
+		if block.BlockOverrides != nil {
+			*overrides = *block.BlockOverrides
+		}
+		block.BlockOverrides = overrides
+
+		if overrides.Number == nil {
+			overrides.Number = (*hexutil.Big)(new(big.Int).Add(prevNumber, common.Big1))
+		}
+		number := overrides.Number.ToInt()
+		if number.Cmp(prevNumber) <= 0 {
+			return nil, &simError{code: errCodeBlockNumberInvalid, msg: fmt.Sprintf("block numbers must be in order: %d <= %d", number, prevNumber)}
+		}
+		if span := new(big.Int).Sub(number, sim.base.Number); span.Cmp(big.NewInt(maxSimulateBlocks)) > 0 {
+			return nil, &simError{code: errCodeClientLimitExceeded, msg: "too many blocks"}
+		}
+		// Fill the gap to the previous block with empty blocks
+		for n := new(big.Int).Add(prevNumber, common.Big1); n.Cmp(number) < 0; n = new(big.Int).Add(n, common.Big1) {
+			timestamp := hexutil.Uint64(prevTimestamp + timestampIncrement)
+			res = append(res, simBlock{BlockOverrides: &BlockOverrides{Number: (*hexutil.Big)(n), Time: &timestamp}})
+			prevTimestamp = uint64(timestamp)
+		}
+		if overrides.Time == nil {
+			timestamp := hexutil.Uint64(prevTimestamp + timestampIncrement)
+			overrides.Time = &timestamp
+		} else if uint64(*overrides.Time) <= prevTimestamp {
+			return nil, &simError{code: errCodeBlockTimestampInvalid, msg: fmt.Sprintf("block timestamps must be in order: %d <= %d", *overrides.Time, prevTimestamp)}
+		}
+		prevNumber, prevTimestamp = number, uint64(*overrides.Time)
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// sanitizeCall fills in the nonce and gas of a call if they're missing, and
+// ensures the call fits into the block and the global gas cap.
+func (sim *simulator) sanitizeCall(call *TransactionArgs, gp *core.GasPool) error {
+	if call.Nonce == nil {
+		nonce := sim.state.GetNonce(call.from())
+		call.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	// Let the call use all the gas left unless explicitly specified
+	if call.Gas == nil {
+		remaining := gp.Gas()
+		if sim.budget < remaining {
+			remaining = sim.budget
+		}
+		call.Gas = (*hexutil.Uint64)(&remaining)
+	}
+	if uint64(*call.Gas) > gp.Gas() {
+		return &simError{code: errCodeBlockGasLimitReached, msg: fmt.Sprintf("block gas limit reached: %d > %d", *call.Gas, gp.Gas())}
+	}
+	if uint64(*call.Gas) > sim.budget {
+		return &simError{code: errCodeClientLimitExceeded, msg: fmt.Sprintf("gas cap exceeded: %d > %d", *call.Gas, sim.budget)}
+	}
+	if call.ChainID == nil {
+		call.ChainID = (*hexutil.Big)(sim.chainConfig.ChainID)
+	}
+	return nil
+}
+
+// simChainContext extends the chain context with the blocks simulated so far,
+// so BLOCKHASH resolves them too.
+type simChainContext struct {
+	*ChainContext
+	headers map[common.Hash]*types.Header
+}
+
+func (c *simChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := c.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	return c.ChainContext.GetHeader(hash, number)
+}
+
+// simTracer collects the logs emitted by the simulated calls, including the
+// synthetic logs of ETH transfers if enabled. The logs of reverted call frames
+// are discarded.
+type simTracer struct {
+	frames         [][]*types.Log // Logs of the currently open call frames
+	traceTransfers bool
+	count          uint // Number of logs in the block so far
+
+	blockNumber uint64
+	txHash      common.Hash
+	txIndex     uint
+}
+
+func newSimTracer(traceTransfers bool, blockNumber uint64) *simTracer {
+	return &simTracer{traceTransfers: traceTransfers, blockNumber: blockNumber}
+}
+
+// reset prepares the tracer for the next call of the block.
+func (t *simTracer) reset(txHash common.Hash, txIndex uint) {
+	t.frames = t.frames[:0]
+	t.txHash, t.txIndex = txHash, txIndex
+}
+
+// Logs returns the logs of the last call, assigning their block-wide indices.
+func (t *simTracer) Logs() []*types.Log {
+	logs := []*types.Log{}
+	if len(t.frames) > 0 {
+		logs = append(logs, t.frames[0]...)
+	}
+	for _, log := range logs {
+		log.Index = t.count
+		t.count++
+	}
+	return logs
+}
+
+func (t *simTracer) addLog(address common.Address, topics []common.Hash, data []byte) {
+	if len(t.frames) == 0 {
+		return
+	}
+	t.frames[len(t.frames)-1] = append(t.frames[len(t.frames)-1], &types.Log{
+		Address:     address,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: t.blockNumber,
+		TxHash:      t.txHash,
+		TxIndex:     t.txIndex,
+	})
+}
+
+func (t *simTracer) captureTransfer(from, to common.Address, value *big.Int) {
+	if !t.traceTransfers || value == nil || value.Sign() <= 0 {
+		return
+	}
+	topics := []common.Hash{
+		transferTopic,
+		common.BytesToHash(from.Bytes()),
+		common.BytesToHash(to.Bytes()),
+	}
+	t.addLog(transferAddress, topics, common.BigToHash(value).Bytes())
+}
+
+func (t *simTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *simTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *simTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.frames = append(t.frames, nil)
+	t.captureTransfer(from, to, value)
+}
+
+func (t *simTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if err != nil && len(t.frames) > 0 {
+		t.frames[0] = nil
+	}
+}
+
+func (t *simTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.frames = append(t.frames, nil)
+	if typ != vm.DELEGATECALL {
+		t.captureTransfer(from, to, value)
+	}
+}
+
+func (t *simTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.frames) < 2 {
+		return
+	}
+	size := len(t.frames)
+	if err == nil {
+		t.frames[size-2] = append(t.frames[size-2], t.frames[size-1]...)
+	}
+	t.frames = t.frames[:size-1]
+}
+
+func (t *simTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil || op < vm.LOG0 || op > vm.LOG4 {
+		return
+	}
+	var (
+		stack  = scope.Stack
+		offset = stack.Back(0).Uint64()
+		size   = stack.Back(1).Uint64()
+		topics = make([]common.Hash, int(op-vm.LOG0))
+	)
+	for i := range topics {
+		topics[i] = common.Hash(stack.Back(2 + i).Bytes32())
+	}
+	// The memory is only expanded after the opcode is traced, zero-fill the
+	// missing part.
+	data := make([]byte, size)
+	if mem := scope.Memory.Data(); offset < uint64(len(mem)) {
+		copy(data, mem[offset:])
+	}
+	t.addLog(scope.Contract.Address(), topics, data)
+}
+
+func (t *simTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
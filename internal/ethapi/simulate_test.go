@@ -0,0 +1,224 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// simTestBackend is a test backend creating EVMs with the Arcology APIs set.
+type simTestBackend struct {
+	*testBackend
+}
+
+func (b simTestBackend) GetEVM(ctx context.Context, msg *core.Message, state *state.StateDB, header *types.Header, vmConfig *vm.Config, blockContext *vm.BlockContext) (*vm.EVM, func() error) {
+	evm, vmError := b.testBackend.GetEVM(ctx, msg, state, header, vmConfig, blockContext)
+	evm.ArcologyNetworkAPIs.APIs = vm.NoopArcologyAPIs{}
+	return evm, vmError
+}
+
+var (
+	// simLogCode emits a log with topic 0x01 and 32 bytes of zero data.
+	simLogCode = common.FromHex("0x600160206000a100")
+
+	// simRevertCode reverts unconditionally.
+	simRevertCode = common.FromHex("0x60006000fd")
+
+	// simBlockHashCode returns the hash of block 1.
+	simBlockHashCode = common.FromHex("0x60014060005260206000f3")
+)
+
+func newSimTestAPI(t *testing.T, accounts []Account, contracts ...common.Address) *BlockChainAPI {
+	alloc := make(core.GenesisAlloc)
+	for _, acc := range accounts {
+		alloc[acc.addr] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	codes := [][]byte{simLogCode, simRevertCode, simBlockHashCode}
+	for i, addr := range contracts {
+		alloc[addr] = core.GenesisAccount{Balance: new(big.Int), Code: codes[i]}
+	}
+	genesis := &core.Genesis{Config: params.TestChainConfig, Alloc: alloc}
+	return NewBlockChainAPI(simTestBackend{newTestBackend(t, 0, genesis, ethash.NewFaker(), nil)})
+}
+
+// Tests that calls are simulated across blocks with the state carried over, and
+// the logs and transfers are reported.
+func TestSimulateV1(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		logger   = common.HexToAddress("0x1000")
+		reverter = common.HexToAddress("0x2000")
+		hasher   = common.HexToAddress("0x3000")
+		api      = newSimTestAPI(t, accounts, logger, reverter, hasher)
+		value    = (*hexutil.Big)(big.NewInt(1000))
+	)
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{
+			{
+				Calls: []TransactionArgs{
+					{From: &accounts[0].addr, To: &accounts[1].addr, Value: value},
+					{From: &accounts[0].addr, To: &logger},
+					{From: &accounts[0].addr, To: &reverter, Value: value},
+				},
+			},
+			{
+				Calls: []TransactionArgs{
+					{From: &accounts[0].addr, To: &hasher},
+				},
+			},
+		},
+		TraceTransfers:         true,
+		ReturnFullTransactions: true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to simulate: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Block count mismatch: have %d, want 2", len(results))
+	}
+	calls := results[0]["calls"].([]simCallResult)
+
+	// Plain transfer, only the synthetic log is emitted
+	if calls[0].Status != 1 || len(calls[0].Logs) != 1 {
+		t.Fatalf("Transfer result mismatch: status %d, logs %d", calls[0].Status, len(calls[0].Logs))
+	}
+	if log := calls[0].Logs[0]; log.Address != transferAddress || log.Topics[0] != transferTopic ||
+		log.Topics[2] != common.BytesToHash(accounts[1].addr.Bytes()) || new(big.Int).SetBytes(log.Data).Cmp(value.ToInt()) != 0 {
+		t.Fatalf("Transfer log mismatch: %+v", log)
+	}
+	// Contract log, indexed after the transfer log
+	if calls[1].Status != 1 || len(calls[1].Logs) != 1 {
+		t.Fatalf("Log result mismatch: status %d, logs %d", calls[1].Status, len(calls[1].Logs))
+	}
+	if log := calls[1].Logs[0]; log.Address != logger || log.Index != 1 || len(log.Data) != 32 || log.BlockHash != results[0]["hash"].(common.Hash) {
+		t.Fatalf("Contract log mismatch: %+v", log)
+	}
+	// Reverted call, the transfer log must be dropped
+	if calls[2].Status != 0 || len(calls[2].Logs) != 0 || calls[2].Error == nil || calls[2].Error.Code != errCodeReverted {
+		t.Fatalf("Revert result mismatch: %+v", calls[2])
+	}
+	// The nonce carries over to the next block, and the hash of the first
+	// simulated block is accessible
+	txs := results[1]["transactions"].([]interface{})
+	if tx := txs[0].(*RPCTransaction); tx.From != accounts[0].addr || tx.Nonce != 3 {
+		t.Fatalf("Transaction mismatch: from %x, nonce %d", tx.From, tx.Nonce)
+	}
+	ret := results[1]["calls"].([]simCallResult)[0].ReturnValue
+	if common.BytesToHash(ret) != results[0]["hash"].(common.Hash) {
+		t.Fatalf("Block hash mismatch: have %x, want %x", ret, results[0]["hash"])
+	}
+	if parent := results[1]["parentHash"].(common.Hash); parent != results[0]["hash"].(common.Hash) {
+		t.Fatalf("Parent hash mismatch: have %x, want %x", parent, results[0]["hash"])
+	}
+}
+
+// Tests that the block numbers and timestamps are sanitized, gaps are filled
+// with empty blocks and invalid chains are rejected.
+func TestSimulateV1Chain(t *testing.T) {
+	t.Parallel()
+
+	api := newSimTestAPI(t, newAccounts(1))
+	number := func(n int64) *BlockOverrides {
+		return &BlockOverrides{Number: (*hexutil.Big)(big.NewInt(n))}
+	}
+	results, err := api.SimulateV1(context.Background(), simOpts{
+		BlockStateCalls: []simBlock{{}, {BlockOverrides: number(4)}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to simulate: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Block count mismatch: have %d, want 4", len(results))
+	}
+	for i, result := range results {
+		if n := result["number"].(*hexutil.Big).ToInt().Int64(); n != int64(i+1) {
+			t.Errorf("Block %d number mismatch: have %d", i, n)
+		}
+		if ts := uint64(result["timestamp"].(hexutil.Uint64)); ts != uint64(i+1)*timestampIncrement {
+			t.Errorf("Block %d timestamp mismatch: have %d", i, ts)
+		}
+	}
+	tests := []struct {
+		blocks []simBlock
+		code   int
+	}{
+		{nil, errCodeInvalidParams},
+		{[]simBlock{{BlockOverrides: number(2)}, {BlockOverrides: number(2)}}, errCodeBlockNumberInvalid},
+		{[]simBlock{{BlockOverrides: number(maxSimulateBlocks + 1)}}, errCodeClientLimitExceeded},
+		{[]simBlock{{}, {BlockOverrides: &BlockOverrides{Time: new(hexutil.Uint64)}}}, errCodeBlockTimestampInvalid},
+	}
+	for i, tt := range tests {
+		_, err := api.SimulateV1(context.Background(), simOpts{BlockStateCalls: tt.blocks}, nil)
+		var simErr *simError
+		if !errors.As(err, &simErr) || simErr.code != tt.code {
+			t.Errorf("Test %d: error mismatch: have %v, want code %d", i, err, tt.code)
+		}
+	}
+}
+
+// Tests that the nonces and fees are only checked with validation enabled.
+func TestSimulateV1Validation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		api      = newSimTestAPI(t, accounts)
+		latest   = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		nonce    = hexutil.Uint64(5)
+	)
+	call := TransactionArgs{From: &accounts[0].addr, To: &accounts[1].addr, Nonce: &nonce}
+	opts := simOpts{BlockStateCalls: []simBlock{{Calls: []TransactionArgs{call}}}}
+
+	if _, err := api.SimulateV1(context.Background(), opts, &latest); err != nil {
+		t.Fatalf("Failed to simulate without validation: %v", err)
+	}
+	opts.Validation = true
+	_, err := api.SimulateV1(context.Background(), opts, &latest)
+	if simErr := new(simError); !errors.As(err, &simErr) || simErr.code != errCodeNonceTooHigh {
+		t.Fatalf("Nonce error mismatch: have %v, want code %d", err, errCodeNonceTooHigh)
+	}
+	// With a valid nonce, the zero fee cap must be below the base fee
+	opts.BlockStateCalls[0].Calls[0].Nonce = nil
+	_, err = api.SimulateV1(context.Background(), opts, &latest)
+	if simErr := new(simError); !errors.As(err, &simErr) || simErr.code != errCodeFeeCapTooLow {
+		t.Fatalf("Fee error mismatch: have %v, want code %d", err, errCodeFeeCapTooLow)
+	}
+	fee := (*hexutil.Big)(big.NewInt(params.GWei))
+	opts.BlockStateCalls[0].Calls[0].MaxFeePerGas = fee
+	results, err := api.SimulateV1(context.Background(), opts, &latest)
+	if err != nil {
+		t.Fatalf("Failed to simulate with validation: %v", err)
+	}
+	if base := results[0]["baseFeePerGas"].(*hexutil.Big); base.ToInt().Sign() == 0 {
+		t.Fatal("Base fee not set with validation")
+	}
+}
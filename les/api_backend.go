@@ -314,6 +314,11 @@ func (b *LesApiBackend) BloomStatus() (uint64, uint64) {
 	return params.BloomBitsBlocksClient, sections
 }
 
+// LogIndexStatus returns no sections, as light clients don't maintain a log index.
+func (b *LesApiBackend) LogIndexStatus() (uint64, uint64) {
+	return params.LogIndexBlocks, 0
+}
+
 func (b *LesApiBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
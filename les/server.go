@@ -150,6 +150,13 @@ func NewLesServer(node *node.Node, e ethBackend, config *ethconfig.Config) (*Les
 }
 
 func (s *LesServer) APIs() []rpc.API {
+	return ServerAPIs(s)
+}
+
+// ServerAPIs returns the RPC services of the given light server. A nil server
+// yields the services without any, only to be inspected for their methods, e.g.
+// to document them.
+func ServerAPIs(s *LesServer) []rpc.API {
 	return []rpc.API{
 		{
 			Namespace: "les",
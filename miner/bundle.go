@@ -0,0 +1,143 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errBundleReverted is returned if a transaction of a bundle reverted without
+// being allowed to.
+var errBundleReverted = errors.New("bundle transaction reverted")
+
+// simulatedBundle is a bundle along with the outcome of its execution on top
+// of the pending state.
+type simulatedBundle struct {
+	bundle  *txpool.Bundle
+	gasUsed uint64   // Gas used by all the transactions of the bundle
+	profit  *big.Int // Increase of the coinbase balance caused by the bundle
+}
+
+// price returns the payment of the bundle to the coinbase per unit of gas.
+func (b *simulatedBundle) price() *big.Int {
+	if b.gasUsed == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(b.profit, new(big.Int).SetUint64(b.gasUsed))
+}
+
+// sortBundles orders simulated bundles by descending price, breaking ties by
+// the bundle hash to keep the order deterministic.
+func sortBundles(bundles []*simulatedBundle) {
+	sort.SliceStable(bundles, func(i, j int) bool {
+		if cmp := bundles[i].price().Cmp(bundles[j].price()); cmp != 0 {
+			return cmp > 0
+		}
+		hi, hj := bundles[i].bundle.Hash(), bundles[j].bundle.Hash()
+		return hi.Cmp(hj) < 0
+	})
+}
+
+// commitBundles includes the bundles of the pool into the block being built.
+// Every bundle is first simulated against the pending state to rank them by
+// their payment to the coinbase, then they are committed atomically one by one
+// in that order, each on top of the previously included ones.
+func (w *worker) commitBundles(env *environment, interrupt *atomic.Int32) error {
+	bundles := w.eth.TxPool().Bundles(env.header.Number.Uint64(), env.header.Time)
+	if len(bundles) == 0 {
+		return nil
+	}
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	sims := make([]*simulatedBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		cpy := env.copy()
+		sim, err := w.applyBundle(cpy, bundle)
+		cpy.discard()
+		if err != nil {
+			log.Trace("Bundle simulation failed", "hash", bundle.Hash(), "err", err)
+			continue
+		}
+		sims = append(sims, sim)
+	}
+	sortBundles(sims)
+
+	for _, sim := range sims {
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		if env.gasPool.Gas() < sim.gasUsed {
+			log.Trace("Not enough gas left for bundle", "hash", sim.bundle.Hash(), "left", env.gasPool.Gas(), "needed", sim.gasUsed)
+			continue
+		}
+		// Earlier bundles may invalidate later ones, so execute again on top of
+		// the current state and only adopt the result if all went well
+		cpy := env.copy()
+		if _, err := w.applyBundle(cpy, sim.bundle); err != nil {
+			log.Debug("Bundle failed, skipped", "hash", sim.bundle.Hash(), "err", err)
+			cpy.discard()
+			continue
+		}
+		env.discard()
+		*env = *cpy
+	}
+	return nil
+}
+
+// applyBundle executes all the transactions of a bundle in order on top of env.
+// If any of them fails, or reverts without being allowed to, an error is returned
+// and env is left in an undefined state: callers must run it on a copy.
+func (w *worker) applyBundle(env *environment, bundle *txpool.Bundle) (*simulatedBundle, error) {
+	var (
+		before  = new(big.Int).Set(env.state.GetBalance(env.coinbase))
+		gasUsed = env.header.GasUsed
+	)
+	for i, tx := range bundle.Txs {
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			return nil, fmt.Errorf("bundle tx %d: replay protected before EIP-155", i)
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+
+		receipt, err := w.applyTransaction(env, tx)
+		if err != nil {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !bundle.CanRevert(tx.Hash()) {
+			return nil, fmt.Errorf("bundle tx %d: %w", i, errBundleReverted)
+		}
+		env.txs = append(env.txs, tx)
+		env.receipts = append(env.receipts, receipt)
+		env.tcount++
+	}
+	return &simulatedBundle{
+		bundle:  bundle,
+		gasUsed: env.header.GasUsed - gasUsed,
+		profit:  new(big.Int).Sub(env.state.GetBalance(env.coinbase), before),
+	}, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that simulated bundles are ranked by their payment per unit of gas.
+func TestSortBundles(t *testing.T) {
+	signer := types.HomesteadSigner{}
+	key, _ := crypto.GenerateKey()
+
+	newBundle := func(nonce uint64, gasUsed uint64, profit int64) *simulatedBundle {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, [20]byte{}, new(big.Int), 21000, big.NewInt(1), nil), signer, key)
+		return &simulatedBundle{
+			bundle:  &txpool.Bundle{Txs: types.Transactions{tx}},
+			gasUsed: gasUsed,
+			profit:  big.NewInt(profit),
+		}
+	}
+	var (
+		cheap  = newBundle(0, 21000, 21000)     // 1 wei per gas
+		rich   = newBundle(1, 100000, 1000000)  // 10 wei per gas
+		dense  = newBundle(2, 21000, 2100000)   // 100 wei per gas
+		free   = newBundle(3, 21000, 0)         // nothing
+		sparse = newBundle(4, 1000000, 2000000) // 2 wei per gas
+	)
+	bundles := []*simulatedBundle{cheap, rich, free, dense, sparse}
+	sortBundles(bundles)
+
+	want := []*simulatedBundle{dense, rich, sparse, cheap, free}
+	for i := range want {
+		if bundles[i] != want[i] {
+			t.Errorf("bundle %d mismatch: have price %v, want %v", i, bundles[i].price(), want[i].price())
+		}
+	}
+}
+
+// bundleTestBackend is a worker backend whose transaction pool only tracks
+// bundles.
+type bundleTestBackend struct {
+	chain *core.BlockChain
+	pool  *txpool.TxPool
+}
+
+func (b *bundleTestBackend) BlockChain() *core.BlockChain { return b.chain }
+func (b *bundleTestBackend) TxPool() *txpool.TxPool       { return b.pool }
+
+var (
+	bundleTestKeys   = make([]*ecdsa.PrivateKey, 3)
+	bundleTestRevert = common.HexToAddress("0xdead") // Contract reverting every call
+)
+
+func init() {
+	for i := range bundleTestKeys {
+		bundleTestKeys[i], _ = crypto.GenerateKey()
+	}
+}
+
+// newBundleTestWorker creates a worker on top of a genesis funding all but the
+// last test key, and an environment for building the first block.
+func newBundleTestWorker(t *testing.T) (*worker, *environment, *bundlepool.BundlePool) {
+	t.Helper()
+
+	alloc := core.GenesisAlloc{
+		bundleTestRevert: {Code: common.FromHex("0x60006000fd")}, // PUSH1 0 PUSH1 0 REVERT
+	}
+	for _, key := range bundleTestKeys[:len(bundleTestKeys)-1] {
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{Balance: testBankFunds}
+	}
+	gspec := &core.Genesis{Config: params.TestChainConfig, Alloc: alloc, GasLimit: params.GenesisGasLimit}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{ArcologyAPIs: vm.NoopArcologyAPIs{}}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	t.Cleanup(chain.Stop)
+
+	bundles := bundlepool.New(bundlepool.DefaultConfig, chain)
+	pool, err := txpool.New(new(big.Int), chain, []txpool.SubPool{bundles})
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	t.Cleanup(func() { pool.Close() })
+
+	w := &worker{
+		chainConfig: gspec.Config,
+		chain:       chain,
+		eth:         &bundleTestBackend{chain: chain, pool: pool},
+	}
+	parent := chain.CurrentBlock()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 1,
+		Difficulty: big.NewInt(1),
+		BaseFee:    eip1559.CalcBaseFee(gspec.Config, parent, parent.Time+1),
+	}
+	env, err := w.makeEnv(parent, header, testUserAddress)
+	if err != nil {
+		t.Fatalf("failed to create environment: %v", err)
+	}
+	t.Cleanup(env.discard)
+	return w, env, bundles
+}
+
+// newBundleTestTx creates a transaction from the i-th test key, paying the
+// coinbase the given tip per gas.
+func newBundleTestTx(t *testing.T, i int, nonce uint64, to common.Address, gas uint64, tip int64) *types.Transaction {
+	t.Helper()
+
+	tx, err := types.SignNewTx(bundleTestKeys[i], types.LatestSigner(params.TestChainConfig), &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Gas:       gas,
+		GasFeeCap: big.NewInt(10 * params.InitialBaseFee),
+		GasTipCap: big.NewInt(tip),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	return tx
+}
+
+// Tests that bundles are included atomically: either all their transactions make
+// it into the block, or none of them, leaving no trace in the state or the gas
+// pool.
+func TestCommitBundles(t *testing.T) {
+	w, env, pool := newBundleTestWorker(t)
+
+	var (
+		good = &txpool.Bundle{BlockNumber: 1, Txs: types.Transactions{
+			newBundleTestTx(t, 0, 0, testUserAddress, params.TxGas, 2),
+			newBundleTestTx(t, 0, 1, testUserAddress, params.TxGas, 2),
+		}}
+		// Same nonce as the first transaction of the good, but paying less, so it
+		// is invalidated by the good one
+		stale = &txpool.Bundle{BlockNumber: 1, Txs: types.Transactions{
+			newBundleTestTx(t, 0, 0, common.Address{0x01}, params.TxGas, 1),
+		}}
+		// Funded first transaction, unfunded second one
+		unfunded = &txpool.Bundle{BlockNumber: 1, Txs: types.Transactions{
+			newBundleTestTx(t, 1, 0, testUserAddress, params.TxGas, 100),
+			newBundleTestTx(t, 2, 0, testUserAddress, params.TxGas, 100),
+		}}
+		// Transfer followed by a reverting call not allowed to revert
+		reverting = &txpool.Bundle{BlockNumber: 1, Txs: types.Transactions{
+			newBundleTestTx(t, 1, 0, testUserAddress, params.TxGas, 50),
+			newBundleTestTx(t, 1, 1, bundleTestRevert, 100000, 50),
+		}}
+	)
+	for _, bundle := range []*txpool.Bundle{good, stale, unfunded, reverting} {
+		if err := pool.AddBundle(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	if err := w.commitBundles(env, nil); err != nil {
+		t.Fatalf("failed to commit bundles: %v", err)
+	}
+	// Only the good bundle must have been included
+	if len(env.txs) != len(good.Txs) || env.tcount != len(good.Txs) {
+		t.Fatalf("included transaction count mismatch: have %d (tcount %d), want %d", len(env.txs), env.tcount, len(good.Txs))
+	}
+	for i, tx := range good.Txs {
+		if env.txs[i].Hash() != tx.Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, env.txs[i].Hash(), tx.Hash())
+		}
+	}
+	// The failed bundles must not have left anything behind
+	if used := uint64(len(good.Txs)) * params.TxGas; env.header.GasUsed != used {
+		t.Errorf("gas used mismatch: have %d, want %d", env.header.GasUsed, used)
+	}
+	if left := env.header.GasLimit - env.header.GasUsed; env.gasPool.Gas() != left {
+		t.Errorf("gas pool mismatch: have %d, want %d", env.gasPool.Gas(), left)
+	}
+	if nonce := env.state.GetNonce(crypto.PubkeyToAddress(bundleTestKeys[1].PublicKey)); nonce != 0 {
+		t.Errorf("rolled back sender nonce mismatch: have %d, want 0", nonce)
+	}
+	if nonce := env.state.GetNonce(crypto.PubkeyToAddress(bundleTestKeys[0].PublicKey)); nonce != 2 {
+		t.Errorf("included sender nonce mismatch: have %d, want 2", nonce)
+	}
+}
+
+// Tests that a bundle with a reverting transaction is only included if the
+// transaction is explicitly allowed to revert.
+func TestCommitBundlesAllowedRevert(t *testing.T) {
+	w, env, pool := newBundleTestWorker(t)
+
+	revert := newBundleTestTx(t, 0, 0, bundleTestRevert, 100000, 1)
+	bundle := &txpool.Bundle{BlockNumber: 1, Txs: types.Transactions{revert}, RevertingTxHashes: []common.Hash{revert.Hash()}}
+	if err := pool.AddBundle(bundle); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if err := w.commitBundles(env, nil); err != nil {
+		t.Fatalf("failed to commit bundles: %v", err)
+	}
+	if len(env.txs) != 1 || len(env.receipts) != 1 {
+		t.Fatalf("included transaction count mismatch: have %d, want 1", len(env.txs))
+	}
+	if env.receipts[0].Status != types.ReceiptStatusFailed {
+		t.Errorf("receipt status mismatch: have %d, want %d", env.receipts[0].Status, types.ReceiptStatusFailed)
+	}
+}
@@ -61,6 +61,9 @@ type Config struct {
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
 
 	RollupComputePendingBlock bool // Compute the pending block from tx-pool, instead of copying the latest-block
+
+	OrderingPolicy  string          `toml:",omitempty"` // Name of the transaction ordering policy, price-and-time if empty
+	AccessPredictor AccessPredictor `toml:"-"`          // Predictor of transaction storage accesses, learning from executions if nil
 }
 
 // DefaultConfig contains default settings for miner.
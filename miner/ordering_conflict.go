@@ -0,0 +1,375 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"container/heap"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// conflictScanLimit is the maximum number of account heads inspected when
+// looking for a transaction which does not conflict with the current batch.
+// Beyond it the batch is closed, bounding the cost of a single pick.
+const conflictScanLimit = 32
+
+var (
+	payloadBatchesHist     = metrics.NewRegisteredHistogram("miner/parallelism/batches", nil, metrics.NewExpDecaySample(1028, 0.015))
+	payloadParallelismHist = metrics.NewRegisteredHistogram("miner/parallelism/ratio", nil, metrics.NewExpDecaySample(1028, 0.015)) // Transactions per batch, scaled by 100
+	payloadUnknownMeter    = metrics.NewRegisteredMeter("miner/parallelism/unknown", nil)                                           // Transactions without a predicted access set
+)
+
+// AccessPredictor predicts the storage a transaction is going to touch before
+// it is executed, allowing the miner to group non-conflicting transactions.
+type AccessPredictor interface {
+	// PredictAccess returns the predicted access list of the transaction and
+	// whether a prediction could be made at all. Transactions without one are
+	// assumed to conflict with everything.
+	PredictAccess(tx *types.Transaction) (types.AccessList, bool)
+}
+
+// AccessObserver is an optional interface for an AccessPredictor which learns
+// from the storage accessed by transactions executed by the miner.
+type AccessObserver interface {
+	ObserveAccess(tx *types.Transaction, accessed types.AccessList)
+}
+
+// DeclaredAccessPredictor predicts accesses from the access lists declared by
+// the transactions themselves. Plain value transfers are known to touch no
+// storage; contract interactions without an access list are unpredictable.
+type DeclaredAccessPredictor struct{}
+
+// PredictAccess implements AccessPredictor.
+func (DeclaredAccessPredictor) PredictAccess(tx *types.Transaction) (types.AccessList, bool) {
+	if list := tx.AccessList(); len(list) > 0 {
+		return list, true
+	}
+	return nil, tx.To() != nil && len(tx.Data()) == 0
+}
+
+// accessKey identifies the contract entry point a learned access list belongs to.
+type accessKey struct {
+	to       common.Address
+	selector [4]byte
+}
+
+// LearningAccessPredictor falls back to the storage accessed by previous
+// executions of the same contract method if a transaction declares no access
+// list of its own.
+type LearningAccessPredictor struct {
+	declared DeclaredAccessPredictor
+	learned  *lru.Cache[accessKey, types.AccessList]
+}
+
+// NewLearningAccessPredictor creates a predictor remembering the accesses of
+// the given number of contract methods.
+func NewLearningAccessPredictor(size int) *LearningAccessPredictor {
+	return &LearningAccessPredictor{learned: lru.NewCache[accessKey, types.AccessList](size)}
+}
+
+// PredictAccess implements AccessPredictor.
+func (p *LearningAccessPredictor) PredictAccess(tx *types.Transaction) (types.AccessList, bool) {
+	if list, ok := p.declared.PredictAccess(tx); ok {
+		return list, true
+	}
+	if key, ok := accessKeyOf(tx); ok {
+		return p.learned.Get(key)
+	}
+	return nil, false
+}
+
+// ObserveAccess implements AccessObserver, remembering the storage slots the
+// transaction accessed. Accounts accessed without storage are dropped, they
+// are either warm by default or only read.
+func (p *LearningAccessPredictor) ObserveAccess(tx *types.Transaction, accessed types.AccessList) {
+	key, ok := accessKeyOf(tx)
+	if !ok {
+		return
+	}
+	list := make(types.AccessList, 0, len(accessed))
+	for _, tuple := range accessed {
+		if len(tuple.StorageKeys) > 0 {
+			list = append(list, tuple)
+		}
+	}
+	p.learned.Add(key, list)
+}
+
+// accessKeyOf returns the contract method called by tx, if any.
+func accessKeyOf(tx *types.Transaction) (accessKey, bool) {
+	if tx.To() == nil || len(tx.Data()) < 4 {
+		return accessKey{}, false
+	}
+	key := accessKey{to: *tx.To()}
+	copy(key.selector[:], tx.Data())
+	return key, true
+}
+
+// accessSlot is a single storage slot, or an account if the slot is nil.
+type accessSlot struct {
+	addr common.Address
+	slot *common.Hash
+}
+
+// accessFootprint is the set of state items a transaction is expected to
+// write. Access lists don't distinguish reads from writes, so every declared
+// slot is assumed written. The sender account is written by the fee payment,
+// the recipient account only if value is transferred to it.
+type accessFootprint struct {
+	known bool
+	items []accessSlot
+}
+
+// newAccessFootprint assembles the footprint of a transaction.
+func newAccessFootprint(predictor AccessPredictor, tx *types.Transaction, from common.Address) *accessFootprint {
+	list, known := predictor.PredictAccess(tx)
+	fp := &accessFootprint{known: known, items: []accessSlot{{addr: from}}}
+	if to := tx.To(); to != nil && tx.Value().Sign() > 0 {
+		fp.items = append(fp.items, accessSlot{addr: *to})
+	}
+	for _, tuple := range list {
+		for i := range tuple.StorageKeys {
+			fp.items = append(fp.items, accessSlot{addr: tuple.Address, slot: &tuple.StorageKeys[i]})
+		}
+	}
+	return fp
+}
+
+// accessSlotKey is the comparable identity of an accessSlot.
+type accessSlotKey [common.AddressLength + common.HashLength + 1]byte
+
+// key returns the comparable identity of an access item.
+func (s accessSlot) key() accessSlotKey {
+	var k accessSlotKey
+	copy(k[:], s.addr[:])
+	if s.slot != nil {
+		k[common.AddressLength] = 1
+		copy(k[common.AddressLength+1:], s.slot[:])
+	}
+	return k
+}
+
+// accessBatch is the union of the footprints of the transactions grouped into
+// the same parallel batch.
+type accessBatch struct {
+	items   map[accessSlotKey]struct{}
+	serial  bool // Whether the batch holds an unpredictable transaction
+	members int
+}
+
+func newAccessBatch() *accessBatch {
+	return &accessBatch{items: make(map[accessSlotKey]struct{})}
+}
+
+// conflicts reports whether the footprint can't be executed in parallel with
+// the transactions already in the batch.
+func (b *accessBatch) conflicts(fp *accessFootprint) bool {
+	if b.members == 0 {
+		return false
+	}
+	if b.serial || !fp.known {
+		return true
+	}
+	for _, item := range fp.items {
+		if _, ok := b.items[item.key()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// add merges the footprint into the batch.
+func (b *accessBatch) add(fp *accessFootprint) {
+	for _, item := range fp.items {
+		b.items[item.key()] = struct{}{}
+	}
+	b.serial = b.serial || !fp.known
+	b.members++
+}
+
+// transactionsByConflict represents a set of transactions which returns them
+// grouped into batches of mutually non-conflicting transactions. Within a
+// batch transactions are picked in fee order, the next batch is only started
+// once no head within the scan limit fits the current one. Nonce order is kept
+// as only the head transaction of every account is ever considered.
+type transactionsByConflict struct {
+	txs       map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
+	heads     txByPriceAndTime                             // Next transaction for each unique account (price heap)
+	signer    types.Signer                                 // Signer for the set of transactions
+	baseFee   *big.Int                                     // Current base fee
+	predictor AccessPredictor                              // Access predictor to detect conflicts with
+
+	batch   *accessBatch                     // Footprint of the batch being filled
+	next    *txWithMinerFee                  // Transaction picked by the last Peek
+	prints  map[common.Hash]*accessFootprint // Footprints of the inspected transactions
+	batches int                              // Number of batches started so far
+}
+
+// newTransactionsByConflict creates a transaction set that can retrieve price
+// sorted, conflict grouped transactions in a nonce-honouring way.
+//
+// Note, the input map is reowned so the caller should not interact any more with
+// if after providing it to the constructor.
+func newTransactionsByConflict(signer types.Signer, txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, predictor AccessPredictor) *transactionsByConflict {
+	set := newTransactionsByPriceAndNonce(signer, txs, baseFee)
+	return &transactionsByConflict{
+		txs:       set.txs,
+		heads:     set.heads,
+		signer:    signer,
+		baseFee:   baseFee,
+		predictor: predictor,
+		batch:     newAccessBatch(),
+		prints:    make(map[common.Hash]*accessFootprint),
+	}
+}
+
+// footprint returns the cached footprint of a transaction, resolving it from
+// the pool if needed. Evicted transactions are reported as unpredictable, the
+// miner drops them anyway once it tries to include them.
+func (t *transactionsByConflict) footprint(wrapped *txWithMinerFee) *accessFootprint {
+	if fp, ok := t.prints[wrapped.tx.Hash]; ok {
+		return fp
+	}
+	fp := &accessFootprint{}
+	if tx := wrapped.tx.Resolve(); tx != nil {
+		fp = newAccessFootprint(t.predictor, tx, wrapped.from)
+	}
+	t.prints[wrapped.tx.Hash] = fp
+	return fp
+}
+
+// Peek returns the next transaction to include.
+func (t *transactionsByConflict) Peek() *txpool.LazyTransaction {
+	if t.next == nil && len(t.heads) > 0 {
+		t.next = t.pick()
+	}
+	if t.next == nil {
+		return nil
+	}
+	return t.next.tx
+}
+
+// pick removes the best head fitting into the current batch from the heap,
+// starting a new batch with the best head overall if none fits.
+func (t *transactionsByConflict) pick() *txWithMinerFee {
+	var (
+		skipped []*txWithMinerFee
+		picked  *txWithMinerFee
+	)
+	for len(t.heads) > 0 && len(skipped) < conflictScanLimit {
+		head := heap.Pop(&t.heads).(*txWithMinerFee)
+		if !t.batch.conflicts(t.footprint(head)) {
+			picked = head
+			break
+		}
+		skipped = append(skipped, head)
+	}
+	if picked == nil {
+		picked, skipped = skipped[0], skipped[1:]
+		t.batch = newAccessBatch()
+	}
+	if t.batch.members == 0 {
+		t.batches++
+	}
+	for _, head := range skipped {
+		heap.Push(&t.heads, head)
+	}
+	return picked
+}
+
+// Shift adds the peeked transaction to the current batch and replaces it with
+// the next one from the same account.
+func (t *transactionsByConflict) Shift() {
+	if t.next == nil {
+		t.Peek()
+	}
+	acc := t.next.from
+	t.batch.add(t.footprint(t.next))
+	delete(t.prints, t.next.tx.Hash)
+	t.next = nil
+
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
+			t.txs[acc] = txs[1:]
+			heap.Push(&t.heads, wrapped)
+		}
+	}
+}
+
+// Pop removes the peeked transaction, *not* replacing it with the next one
+// from the same account. This should be used when a transaction cannot be
+// executed and hence all subsequent ones should be discarded from the same
+// account.
+func (t *transactionsByConflict) Pop() {
+	if t.next == nil {
+		t.Peek()
+	}
+	delete(t.prints, t.next.tx.Hash)
+	t.next = nil
+}
+
+// parallelism computes the number of sequential batches needed to execute the
+// transactions in the given order if all non-conflicting transactions run in
+// parallel. Every transaction is scheduled into the batch after the last one it
+// conflicts with, unpredictable transactions act as barriers. It returns the
+// batch count and the number of unpredictable transactions.
+func parallelism(signer types.Signer, predictor AccessPredictor, txs []*types.Transaction) (batches int, unknown int) {
+	var (
+		levels = make(map[accessSlotKey]int)
+		floor  int // Batch of the last unpredictable transaction
+	)
+	for _, tx := range txs {
+		from, _ := types.Sender(signer, tx)
+		fp := newAccessFootprint(predictor, tx, from)
+		if !fp.known {
+			unknown++
+			batches++
+			floor = batches
+			continue
+		}
+		level := floor + 1
+		for _, item := range fp.items {
+			if l := levels[item.key()]; l >= level {
+				level = l + 1
+			}
+		}
+		for _, item := range fp.items {
+			levels[item.key()] = level
+		}
+		if level > batches {
+			batches = level
+		}
+	}
+	return batches, unknown
+}
+
+// reportParallelism updates the metrics on the expected parallel execution of
+// a built payload.
+func reportParallelism(signer types.Signer, predictor AccessPredictor, txs []*types.Transaction) {
+	if !metrics.Enabled || len(txs) == 0 {
+		return
+	}
+	batches, unknown := parallelism(signer, predictor, txs)
+	payloadBatchesHist.Update(int64(batches))
+	payloadParallelismHist.Update(int64(100 * len(txs) / batches))
+	payloadUnknownMeter.Mark(int64(unknown))
+}
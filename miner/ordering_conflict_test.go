@@ -0,0 +1,136 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newAccessListTx signs a transaction calling the hot contract, declaring an
+// access to the given slot.
+func newAccessListTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, nonce uint64, price int64, slot byte) *txpool.LazyTransaction {
+	t.Helper()
+
+	contract := common.HexToAddress("0xc0de")
+	tx, err := types.SignTx(types.NewTx(&types.AccessListTx{
+		ChainID:    signer.ChainID(),
+		Nonce:      nonce,
+		To:         &contract,
+		Gas:        100000,
+		GasPrice:   big.NewInt(price),
+		Data:       []byte{0x01, 0x02, 0x03, 0x04},
+		AccessList: types.AccessList{{Address: contract, StorageKeys: []common.Hash{{slot}}}},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	return &txpool.LazyTransaction{
+		Hash:      tx.Hash(),
+		Tx:        tx,
+		Time:      tx.Time(),
+		GasFeeCap: tx.GasFeeCap(),
+		GasTipCap: tx.GasTipCap(),
+		Gas:       tx.Gas(),
+	}
+}
+
+// Tests that the conflict-aware ordering defers transactions touching slots
+// already written in the current batch, while keeping fee and nonce order.
+func TestTransactionConflictSort(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	addrs := make([]common.Address, 4)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	signer := types.LatestSignerForChainID(common.Big1)
+
+	var (
+		a0 = newAccessListTx(t, signer, keys[0], 0, 40, 1)
+		a1 = newAccessListTx(t, signer, keys[0], 1, 50, 4)
+		b0 = newAccessListTx(t, signer, keys[1], 0, 30, 1) // Conflicts with a0
+		c0 = newAccessListTx(t, signer, keys[2], 0, 20, 2)
+		d0 = newAccessListTx(t, signer, keys[3], 0, 10, 3)
+	)
+	groups := map[common.Address][]*txpool.LazyTransaction{
+		addrs[0]: {a0, a1},
+		addrs[1]: {b0},
+		addrs[2]: {c0},
+		addrs[3]: {d0},
+	}
+	txset := newTransactionsByConflict(signer, groups, nil, DeclaredAccessPredictor{})
+
+	var txs []*types.Transaction
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx.Tx)
+		txset.Shift()
+	}
+	want := []*txpool.LazyTransaction{a0, c0, d0, a1, b0}
+	if len(txs) != len(want) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if tx.Hash() != want[i].Hash {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, tx.Hash(), want[i].Hash)
+		}
+	}
+	if txset.batches != 2 {
+		t.Errorf("batch count mismatch: have %d, want %d", txset.batches, 2)
+	}
+	if batches, unknown := parallelism(signer, DeclaredAccessPredictor{}, txs); batches != 2 || unknown != 0 {
+		t.Errorf("parallelism mismatch: have %d batches, %d unknown, want 2, 0", batches, unknown)
+	}
+}
+
+// Tests that the learning predictor reuses the storage accessed by earlier
+// calls of the same contract method.
+func TestLearningAccessPredictor(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		signer    = types.LatestSignerForChainID(common.Big1)
+		contract  = common.HexToAddress("0xc0de")
+		predictor = NewLearningAccessPredictor(16)
+	)
+	tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+		To:       &contract,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Data:     []byte{0x01, 0x02, 0x03, 0x04, 0xff},
+	}), signer, key)
+
+	if _, known := predictor.PredictAccess(tx); known {
+		t.Fatal("unexpected prediction before any observation")
+	}
+	predictor.ObserveAccess(tx, types.AccessList{
+		{Address: crypto.PubkeyToAddress(key.PublicKey)},
+		{Address: contract, StorageKeys: []common.Hash{{1}}},
+	})
+	list, known := predictor.PredictAccess(tx)
+	if !known {
+		t.Fatal("missing prediction after observation")
+	}
+	if len(list) != 1 || list[0].Address != contract || len(list[0].StorageKeys) != 1 {
+		t.Errorf("prediction mismatch: have %v", list)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Names of the built-in ordering policies.
+const (
+	PriceAndTimeOrdering  = "price-and-time" // Highest effective tip first, earliest seen on ties (default)
+	FIFOOrdering          = "fifo"           // Earliest seen first
+	HashOrdering          = "hash"           // Lowest transaction hash first, reproducible across nodes
+	ConflictAwareOrdering = "conflict-aware" // Fee ordered batches of non-conflicting transactions
+)
+
+// TransactionSet is a set of transactions which returns them in the order they
+// should be included into a block. Whatever the order, a transaction must only
+// be returned after all lower nonce ones of the same account.
+type TransactionSet interface {
+	// Peek returns the next transaction to include, nil if the set is exhausted.
+	Peek() *txpool.LazyTransaction
+
+	// Shift replaces the peeked transaction with the next one from the same account.
+	Shift()
+
+	// Pop removes the peeked transaction along with all subsequent ones from
+	// the same account.
+	Pop()
+}
+
+// OrderingContext holds the block-level information available to an ordering
+// policy.
+type OrderingContext struct {
+	Signer    types.Signer    // Signer for the set of transactions
+	Header    *types.Header   // Header of the block being built
+	Predictor AccessPredictor // Predictor of the storage accessed by transactions
+}
+
+// OrderingPolicy decides the order in which the miner attempts to include the
+// pending transactions into a block.
+type OrderingPolicy interface {
+	// Order creates the transaction set to fill a block from. The per account
+	// transaction lists are nonce sorted; the map is reowned by the policy.
+	Order(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet
+
+	// Global reports whether the policy defines a deterministic order over all
+	// pending transactions. Prioritising local transactions would break that
+	// order, so the policy is applied to the merged set of local and remote ones.
+	Global() bool
+
+	// LearnsAccesses reports whether the policy relies on the access predictor
+	// of the context, which is then taught the storage accessed by every
+	// transaction included into a block.
+	LearnsAccesses() bool
+}
+
+// OrderingPolicyFunc is an adapter to allow the use of ordinary functions as
+// ordering policies. Local transactions are prioritised and no accesses are
+// learned.
+type OrderingPolicyFunc func(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet
+
+// Order implements OrderingPolicy.
+func (f OrderingPolicyFunc) Order(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+	return f(ctx, txs)
+}
+
+// Global implements OrderingPolicy.
+func (f OrderingPolicyFunc) Global() bool { return false }
+
+// LearnsAccesses implements OrderingPolicy.
+func (f OrderingPolicyFunc) LearnsAccesses() bool { return false }
+
+// orderingPolicy is an ordering policy function along with its properties.
+type orderingPolicy struct {
+	OrderingPolicyFunc
+	global   bool
+	learning bool
+}
+
+// Global implements OrderingPolicy.
+func (p orderingPolicy) Global() bool { return p.global }
+
+// LearnsAccesses implements OrderingPolicy.
+func (p orderingPolicy) LearnsAccesses() bool { return p.learning }
+
+var (
+	orderingPolicies     = make(map[string]OrderingPolicy)
+	orderingPoliciesLock sync.RWMutex
+)
+
+func init() {
+	RegisterOrderingPolicy(PriceAndTimeOrdering, OrderingPolicyFunc(func(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+		return newTransactionsByPriceAndNonce(ctx.Signer, txs, ctx.Header.BaseFee)
+	}))
+	RegisterOrderingPolicy(FIFOOrdering, orderingPolicy{global: true, OrderingPolicyFunc: func(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+		return newTransactionsByOrder(txs, ctx.Header.BaseFee, func(a, b *txWithMinerFee) bool {
+			if a.tx.Time.Equal(b.tx.Time) {
+				return bytes.Compare(a.tx.Hash[:], b.tx.Hash[:]) < 0
+			}
+			return a.tx.Time.Before(b.tx.Time)
+		})
+	}})
+	RegisterOrderingPolicy(HashOrdering, orderingPolicy{global: true, OrderingPolicyFunc: func(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+		return newTransactionsByOrder(txs, ctx.Header.BaseFee, func(a, b *txWithMinerFee) bool {
+			return bytes.Compare(a.tx.Hash[:], b.tx.Hash[:]) < 0
+		})
+	}})
+	RegisterOrderingPolicy(ConflictAwareOrdering, orderingPolicy{learning: true, OrderingPolicyFunc: func(ctx *OrderingContext, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+		return newTransactionsByConflict(ctx.Signer, txs, ctx.Header.BaseFee, ctx.Predictor)
+	}})
+}
+
+// RegisterOrderingPolicy makes an ordering policy selectable by name through
+// Config.OrderingPolicy. It fails if the name is already taken.
+func RegisterOrderingPolicy(name string, policy OrderingPolicy) error {
+	orderingPoliciesLock.Lock()
+	defer orderingPoliciesLock.Unlock()
+
+	if _, ok := orderingPolicies[name]; ok {
+		return fmt.Errorf("ordering policy %q already registered", name)
+	}
+	orderingPolicies[name] = policy
+	return nil
+}
+
+// LookupOrderingPolicy returns the ordering policy registered under name, the
+// default one if name is empty.
+func LookupOrderingPolicy(name string) (OrderingPolicy, error) {
+	if name == "" {
+		name = PriceAndTimeOrdering
+	}
+	orderingPoliciesLock.RLock()
+	defer orderingPoliciesLock.RUnlock()
+
+	policy, ok := orderingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ordering policy %q", name)
+	}
+	return policy, nil
+}
+
+// OrderingPolicies returns the sorted names of all registered ordering policies.
+func OrderingPolicies() []string {
+	orderingPoliciesLock.RLock()
+	defer orderingPoliciesLock.RUnlock()
+
+	names := make([]string, 0, len(orderingPolicies))
+	for name := range orderingPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// txsByOrder is a heap of account head transactions sorted by an arbitrary
+// ordering function.
+type txsByOrder struct {
+	heads []*txWithMinerFee
+	less  func(a, b *txWithMinerFee) bool
+}
+
+func (s *txsByOrder) Len() int           { return len(s.heads) }
+func (s *txsByOrder) Less(i, j int) bool { return s.less(s.heads[i], s.heads[j]) }
+func (s *txsByOrder) Swap(i, j int)      { s.heads[i], s.heads[j] = s.heads[j], s.heads[i] }
+
+func (s *txsByOrder) Push(x interface{}) {
+	s.heads = append(s.heads, x.(*txWithMinerFee))
+}
+
+func (s *txsByOrder) Pop() interface{} {
+	old := s.heads
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	s.heads = old[0 : n-1]
+	return x
+}
+
+// transactionsByOrder represents a set of transactions returned according to
+// a custom ordering of the account heads, while honouring nonces. Transactions
+// unable to pay the base fee are dropped along with their successors.
+type transactionsByOrder struct {
+	txs     map[common.Address][]*txpool.LazyTransaction // Per account nonce-sorted list of transactions
+	heads   *txsByOrder                                  // Next transaction for each unique account
+	baseFee *big.Int                                     // Current base fee
+}
+
+// newTransactionsByOrder creates a transaction set returning the account heads
+// in the order defined by less.
+//
+// Note, the input map is reowned so the caller should not interact any more with
+// if after providing it to the constructor.
+func newTransactionsByOrder(txs map[common.Address][]*txpool.LazyTransaction, baseFee *big.Int, less func(a, b *txWithMinerFee) bool) *transactionsByOrder {
+	heads := &txsByOrder{heads: make([]*txWithMinerFee, 0, len(txs)), less: less}
+	for from, accTxs := range txs {
+		wrapped, err := newTxWithMinerFee(accTxs[0], from, baseFee)
+		if err != nil {
+			delete(txs, from)
+			continue
+		}
+		heads.heads = append(heads.heads, wrapped)
+		txs[from] = accTxs[1:]
+	}
+	heap.Init(heads)
+
+	return &transactionsByOrder{
+		txs:     txs,
+		heads:   heads,
+		baseFee: baseFee,
+	}
+}
+
+// Peek returns the next transaction in order.
+func (t *transactionsByOrder) Peek() *txpool.LazyTransaction {
+	if t.heads.Len() == 0 {
+		return nil
+	}
+	return t.heads.heads[0].tx
+}
+
+// Shift replaces the current head with the next one from the same account.
+func (t *transactionsByOrder) Shift() {
+	acc := t.heads.heads[0].from
+	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
+		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
+			t.heads.heads[0], t.txs[acc] = wrapped, txs[1:]
+			heap.Fix(t.heads, 0)
+			return
+		}
+	}
+	heap.Pop(t.heads)
+}
+
+// Pop removes the current head, *not* replacing it with the next one from the
+// same account.
+func (t *transactionsByOrder) Pop() {
+	heap.Pop(t.heads)
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newOrderingTestGroups creates a few accounts with a couple of transactions
+// each, with arrival times ascending in the order of creation.
+func newOrderingTestGroups(t *testing.T, signer types.Signer) map[common.Address][]*txpool.LazyTransaction {
+	t.Helper()
+
+	var (
+		groups = make(map[common.Address][]*txpool.LazyTransaction)
+		now    = time.Now()
+		seen   = 0
+	)
+	for i := 0; i < 5; i++ {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			groups[addr] = append(groups[addr], newOrderingTestTx(t, signer, key, nonce, now.Add(time.Duration(seen)*time.Second)))
+			seen++
+		}
+	}
+	return groups
+}
+
+func newOrderingTestTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, nonce uint64, seen time.Time) *txpool.LazyTransaction {
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(1), 21000, big.NewInt(int64(nonce+1)), nil), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	return &txpool.LazyTransaction{
+		Hash:      tx.Hash(),
+		Tx:        tx,
+		Time:      seen,
+		GasFeeCap: tx.GasFeeCap(),
+		GasTipCap: tx.GasTipCap(),
+		Gas:       tx.Gas(),
+	}
+}
+
+// drainOrdering orders the groups with the named policy and returns the
+// transactions in inclusion order, checking nonce ordering along the way.
+func drainOrdering(t *testing.T, name string, signer types.Signer, groups map[common.Address][]*txpool.LazyTransaction) []*txpool.LazyTransaction {
+	t.Helper()
+
+	policy, err := LookupOrderingPolicy(name)
+	if err != nil {
+		t.Fatalf("failed to look up policy: %v", err)
+	}
+	ctx := &OrderingContext{Signer: signer, Header: &types.Header{}, Predictor: DeclaredAccessPredictor{}}
+	set := policy.Order(ctx, groups)
+
+	var (
+		txs    []*txpool.LazyTransaction
+		nonces = make(map[common.Address]uint64)
+	)
+	for tx := set.Peek(); tx != nil; tx = set.Peek() {
+		from, _ := types.Sender(signer, tx.Tx)
+		if tx.Tx.Nonce() != nonces[from] {
+			t.Fatalf("%s: nonce gap for %x: have %d, want %d", name, from, tx.Tx.Nonce(), nonces[from])
+		}
+		nonces[from]++
+		txs = append(txs, tx)
+		set.Shift()
+	}
+	return txs
+}
+
+func TestOrderingPolicyFIFO(t *testing.T) {
+	signer := types.HomesteadSigner{}
+	txs := drainOrdering(t, FIFOOrdering, signer, newOrderingTestGroups(t, signer))
+	if len(txs) != 15 {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), 15)
+	}
+	for i := 1; i < len(txs); i++ {
+		if !txs[i-1].Time.Before(txs[i].Time) {
+			t.Errorf("transaction %d seen before its predecessor", i)
+		}
+	}
+}
+
+func TestOrderingPolicyHash(t *testing.T) {
+	signer := types.HomesteadSigner{}
+	groups := newOrderingTestGroups(t, signer)
+
+	// Copy the groups as the policy reowns them
+	cpy := make(map[common.Address][]*txpool.LazyTransaction, len(groups))
+	for addr, txs := range groups {
+		cpy[addr] = append([]*txpool.LazyTransaction(nil), txs...)
+	}
+	// The first transaction is the lowest hash among all account heads
+	var lowest common.Hash
+	for _, txs := range groups {
+		if lowest == (common.Hash{}) || bytes.Compare(txs[0].Hash[:], lowest[:]) < 0 {
+			lowest = txs[0].Hash
+		}
+	}
+	first := drainOrdering(t, HashOrdering, signer, groups)
+	second := drainOrdering(t, HashOrdering, signer, cpy)
+	if len(first) != 15 || len(first) != len(second) {
+		t.Fatalf("transaction count mismatch: have %d and %d, want %d", len(first), len(second), 15)
+	}
+	for i := range first {
+		if first[i].Hash != second[i].Hash {
+			t.Fatalf("ordering not reproducible at %d", i)
+		}
+	}
+	if first[0].Hash != lowest {
+		t.Errorf("first transaction mismatch: have %x, want %x", first[0].Hash, lowest)
+	}
+}
+
+func TestOrderingPolicyRegistry(t *testing.T) {
+	if _, err := LookupOrderingPolicy("no-such-policy"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+	if policy, err := LookupOrderingPolicy(""); err != nil || policy == nil {
+		t.Fatalf("failed to look up default policy: %v", err)
+	}
+	if err := RegisterOrderingPolicy(PriceAndTimeOrdering, OrderingPolicyFunc(nil)); err == nil {
+		t.Fatal("expected error for duplicate registration")
+	}
+	names := OrderingPolicies()
+	for _, want := range []string{ConflictAwareOrdering, FIFOOrdering, HashOrdering, PriceAndTimeOrdering} {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("missing built-in policy %q in %v", want, names)
+		}
+	}
+}
+
+// Tests that the built-in policies declare how the worker applies them.
+func TestOrderingPolicyProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		global   bool
+		learning bool
+	}{
+		{PriceAndTimeOrdering, false, false},
+		{FIFOOrdering, true, false},
+		{HashOrdering, true, false},
+		{ConflictAwareOrdering, false, true},
+	}
+	for _, tt := range tests {
+		policy, err := LookupOrderingPolicy(tt.name)
+		if err != nil {
+			t.Fatalf("%s: failed to look up policy: %v", tt.name, err)
+		}
+		if policy.Global() != tt.global {
+			t.Errorf("%s: global mismatch: have %v, want %v", tt.name, policy.Global(), tt.global)
+		}
+		if policy.LearnsAccesses() != tt.learning {
+			t.Errorf("%s: access learning mismatch: have %v, want %v", tt.name, policy.LearnsAccesses(), tt.learning)
+		}
+	}
+}
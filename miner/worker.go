@@ -56,6 +56,10 @@ const (
 	// resubmitAdjustChanSize is the size of resubmitting interval adjustment channel.
 	resubmitAdjustChanSize = 10
 
+	// accessPredictorSize is the number of contract methods the default access
+	// predictor remembers the storage accesses of.
+	accessPredictorSize = 4096
+
 	// minRecommitInterval is the minimal time interval to recreate the sealing block with
 	// any newly arrived transactions.
 	minRecommitInterval = 100 * time.Millisecond
@@ -233,6 +237,17 @@ type worker struct {
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering is the policy deciding the order transactions are included in.
+	// Global orderings are applied to all pending transactions at once instead
+	// of to the local and remote ones separately.
+	ordering OrderingPolicy
+
+	// predictor forecasts the storage accessed by transactions, used by the
+	// ordering policy and to estimate the parallelism of payloads. The observer
+	// is only set if the ordering policy makes use of learned accesses.
+	predictor AccessPredictor
+	observer  AccessObserver
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -290,6 +305,20 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	ordering, err := LookupOrderingPolicy(config.OrderingPolicy)
+	if err != nil {
+		log.Warn("Falling back to default transaction ordering", "err", err)
+		ordering, _ = LookupOrderingPolicy(PriceAndTimeOrdering)
+	}
+	worker.ordering = ordering
+
+	worker.predictor = config.AccessPredictor
+	if worker.predictor == nil {
+		worker.predictor = NewLearningAccessPredictor(accessPredictorSize)
+	}
+	if ordering.LearnsAccesses() {
+		worker.observer, _ = worker.predictor.(AccessObserver)
+	}
 	worker.wg.Add(4)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
@@ -583,7 +612,7 @@ func (w *worker) mainLoop() {
 						BlobGas:   tx.BlobGas(),
 					})
 				}
-				txset := newTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee)
+				txset := w.newOrderedTransactions(w.current, txs)
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, txset, nil)
 
@@ -832,7 +861,17 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+// newOrderedTransactions creates the transaction set used to fill env according
+// to the configured ordering policy.
+func (w *worker) newOrderedTransactions(env *environment, txs map[common.Address][]*txpool.LazyTransaction) TransactionSet {
+	return w.ordering.Order(&OrderingContext{
+		Signer:    env.signer,
+		Header:    env.header,
+		Predictor: w.predictor,
+	}, txs)
+}
+
+func (w *worker) commitTransactions(env *environment, txs TransactionSet, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -901,6 +940,11 @@ func (w *worker) commitTransactions(env *environment, txs *transactionsByPriceAn
 			env.tcount++
 			txs.Shift()
 
+			// Feed the accessed storage back into the predictor if it learns
+			if w.observer != nil {
+				w.observer.ObserveAccess(tx, env.state.AccessList())
+			}
+
 		default:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
@@ -1030,12 +1074,22 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	return env, nil
 }
 
-// fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// fillTransactions retrieves the bundles and pending transactions from the txpool
+// and fills them into the given sealing block. Bundles are included first, then
+// local transactions before remote ones, each group ordered by the configured
+// ordering policy. Global ordering policies are applied to all pending
+// transactions at once, without prioritising the local ones.
 func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) error {
+	if err := w.commitBundles(env, interrupt); err != nil {
+		return err
+	}
 	pending := w.eth.TxPool().Pending(true)
-
+	if w.ordering.Global() {
+		if len(pending) == 0 {
+			return nil
+		}
+		return w.commitTransactions(env, w.newOrderedTransactions(env, pending), interrupt)
+	}
 	// Split the pending transactions into locals and remotes.
 	localTxs, remoteTxs := make(map[common.Address][]*txpool.LazyTransaction), pending
 	for _, account := range w.eth.TxPool().Locals() {
@@ -1047,13 +1101,13 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 
 	// Fill the block with all available pending transactions.
 	if len(localTxs) > 0 {
-		txs := newTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
+		txs := w.newOrderedTransactions(env, localTxs)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := newTransactionsByPriceAndNonce(env.signer, remoteTxs, env.header.BaseFee)
+		txs := w.newOrderedTransactions(env, remoteTxs)
 		if err := w.commitTransactions(env, txs, interrupt); err != nil {
 			return err
 		}
@@ -1097,6 +1151,7 @@ func (w *worker) generateWork(genParams *generateParams) *newPayloadResult {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(w.newpayloadTimeout))
 		}
 	}
+	reportParallelism(work.signer, w.predictor, work.txs)
 	block, err := w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, nil, work.receipts, genParams.withdrawals)
 	if err != nil {
 		return &newPayloadResult{err: err}
@@ -190,6 +190,18 @@ type Config struct {
 	// Requests using ip address directly are not affected
 	GraphQLVirtualHosts []string `toml:",omitempty"`
 
+	// GraphQLMaxDepth is the maximum nesting depth of the fields selected by a
+	// GraphQL query. Zero disables the limit.
+	GraphQLMaxDepth int `toml:",omitempty"`
+
+	// GraphQLMaxCost is the maximum static cost of a GraphQL query, computed from
+	// the costs of the selected fields. Zero disables the limit.
+	GraphQLMaxCost uint64 `toml:",omitempty"`
+
+	// GraphQLFieldCosts overrides the costs of the GraphQL fields, keyed by
+	// Type.field.
+	GraphQLFieldCosts map[string]uint64 `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -204,6 +216,10 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCRateLimit configures the per-client budgets of the calls served over the
+	// public HTTP and WebSocket endpoints. Nil disables the limits.
+	RPCRateLimit *rpc.RateLimitConfig `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
@@ -211,6 +227,10 @@ type Config struct {
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
+
+	// DBAncientCodec is the compression codec of the bodies and receipts tables
+	// in newly created chain freezers. Empty means snappy.
+	DBAncientCodec string `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
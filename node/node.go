@@ -55,14 +55,15 @@ type Node struct {
 	state         int           // Tracks state of node lifecycle
 
 	lock          sync.Mutex
-	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	http          *httpServer //
-	ws            *httpServer //
-	httpAuth      *httpServer //
-	wsAuth        *httpServer //
-	ipc           *ipcServer  // Stores information about the ipc http server
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	lifecycles    []Lifecycle      // All registered backends, services, and auxiliary services that have a lifecycle
+	rpcAPIs       []rpc.API        // List of APIs currently provided by the node
+	http          *httpServer      //
+	ws            *httpServer      //
+	httpAuth      *httpServer      //
+	wsAuth        *httpServer      //
+	ipc           *ipcServer       // Stores information about the ipc http server
+	inprocHandler *rpc.Server      // In-process RPC request handler to process the API requests
+	rateLimiter   *rpc.RateLimiter // Per-client call budgets shared by the public endpoints
 
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
@@ -112,6 +113,9 @@ func New(conf *Config) (*Node, error) {
 		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
 	}
+	if conf.RPCRateLimit != nil {
+		node.rateLimiter = rpc.NewRateLimiter(*conf.RPCRateLimit)
+	}
 
 	// Register built-in APIs.
 	node.rpcAPIs = append(node.rpcAPIs, node.apis()...)
@@ -377,17 +381,11 @@ func (n *Node) obtainJWTSecret(cliParam string) ([]byte, error) {
 // startup. It's not meant to be called at any time afterwards as it makes certain
 // assumptions about the state of the node.
 func (n *Node) startRPC() error {
-	// Filter out personal api
-	var apis []rpc.API
-	for _, api := range n.rpcAPIs {
+	apis := n.servedAPIs()
+	for _, api := range apis {
 		if api.Namespace == "personal" {
-			if n.config.EnablePersonal {
-				log.Warn("Deprecated personal namespace activated")
-			} else {
-				continue
-			}
+			log.Warn("Deprecated personal namespace activated")
 		}
-		apis = append(apis, api)
 	}
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -407,6 +405,7 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		rateLimiter:            n.rateLimiter,
 	}
 
 	initHttp := func(server *httpServer, port int) error {
@@ -591,6 +590,28 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// servedAPIs returns the registered APIs, without the personal namespace unless
+// explicitly enabled.
+func (n *Node) servedAPIs() []rpc.API {
+	var apis []rpc.API
+	for _, api := range n.rpcAPIs {
+		if api.Namespace == "personal" && !n.config.EnablePersonal {
+			continue
+		}
+		apis = append(apis, api)
+	}
+	return apis
+}
+
+// RPCAPIs returns the APIs served by the node over its RPC endpoints, subject
+// to the module filters of the individual endpoints.
+func (n *Node) RPCAPIs() []rpc.API {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.servedAPIs()
+}
+
 // getAPIs return two sets of APIs, both the ones that do not require
 // authentication, and the complete set
 func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
@@ -763,6 +784,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 			Cache:             cache,
 			Handles:           handles,
 			ReadOnly:          readonly,
+			AncientCodec:      n.config.DBAncientCodec,
 		})
 	}
 
@@ -772,6 +794,40 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	return db, err
 }
 
+// OpenShardedDatabase opens an existing sharded database with the given name (or
+// creates one if no previous can be found) from within the node's instance
+// directory. Each shard is tracked individually, so the node closes them all on
+// shutdown. If the node is ephemeral, an in-memory sharded database is returned.
+func (n *Node) OpenShardedDatabase(name string, cache, handles int, namespace string, readonly bool) (*rawdb.ShardedDatabase, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.state == closedState {
+		return nil, ErrNodeStopped
+	}
+	var db *rawdb.ShardedDatabase
+	var err error
+	if n.config.DataDir == "" {
+		db = rawdb.NewMemoryShardedDatabase()
+	} else {
+		db, err = rawdb.OpenSharded(rawdb.OpenOptions{
+			Type:      n.config.DBEngine,
+			Directory: n.ResolvePath(name),
+			Namespace: namespace,
+			Cache:     cache,
+			Handles:   handles,
+			ReadOnly:  readonly,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	shards := db.Shards()
+	for i := range shards {
+		shards[i] = n.wrapDatabase(shards[i])
+	}
+	return rawdb.NewShardedDatabase(db.Manifest(), shards)
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.ResolvePath(x)
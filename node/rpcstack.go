@@ -56,6 +56,7 @@ type rpcEndpointConfig struct {
 	jwtSecret              []byte // optional JWT secret
 	batchItemLimit         int
 	batchResponseSizeLimit int
+	rateLimiter            *rpc.RateLimiter // optional per-client call budgets
 }
 
 type rpcHandler struct {
@@ -200,6 +201,14 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if ws != nil && isWebsocket(r) {
 		if checkPath(r, h.wsConfig.prefix) {
 			ws.ServeHTTP(w, r)
+			return
+		}
+		// Websocket requests to other paths may be served by the handlers
+		// registered via Node.RegisterHandler.
+		if h.httpHandler.Load().(*rpcHandler) != nil {
+			if muxHandler, pattern := h.mux.Handler(r); pattern != "" {
+				muxHandler.ServeHTTP(w, r)
+			}
 		}
 		return
 	}
@@ -304,6 +313,7 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRateLimiter(config.rateLimiter)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -336,6 +346,7 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetRateLimiter(config.rateLimiter)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -368,6 +368,12 @@ func TestJWT(t *testing.T) {
 				"bar": "baz",
 			}))
 		},
+		func() string {
+			return fmt.Sprintf("Bearer %v", issueToken(secret, nil, testClaim{
+				"iat": time.Now().Unix(),
+				"id":  "client",
+			}))
+		},
 	}
 	for i, tokenFn := range expOk {
 		token := tokenFn()
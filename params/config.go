@@ -378,6 +378,10 @@ type ChainConfig struct {
 
 	// Optimism config, nil if not active
 	Optimism *OptimismConfig `json:"optimism,omitempty"`
+
+	// Precompiles schedules the custom precompiled contracts registered in the
+	// vm package, keyed by contract address. Unscheduled addresses stay inactive.
+	Precompiles map[common.Address]*PrecompileActivation `json:"precompiles,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -814,6 +818,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if err := c.checkPrecompilesCompatible(newcfg, headNumber, headTimestamp); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -972,6 +979,8 @@ type Rules struct {
 	IsVerkle                                                bool
 	IsOptimismBedrock, IsOptimismRegolith                   bool
 	IsOptimismCanyon                                        bool
+
+	CustomPrecompiles []common.Address // Activated custom precompiles, sorted by address
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1001,5 +1010,7 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOptimismBedrock:  c.IsOptimismBedrock(num),
 		IsOptimismRegolith: c.IsOptimismRegolith(timestamp),
 		IsOptimismCanyon:   c.IsOptimismCanyon(timestamp),
+		// Custom precompiles
+		CustomPrecompiles: c.ActivePrecompiles(num, timestamp),
 	}
 }
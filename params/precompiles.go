@@ -0,0 +1,96 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PrecompileActivation is the fork schedule of a custom precompiled contract.
+// The contract is active once either its block or its timestamp is reached.
+type PrecompileActivation struct {
+	Block *big.Int `json:"block,omitempty"` // Activation block (nil = not scheduled by block, 0 = active from genesis)
+	Time  *uint64  `json:"time,omitempty"`  // Activation time (nil = not scheduled by time, 0 = active from genesis)
+}
+
+// IsActive returns whether the precompile is active at the given block and time.
+func (a *PrecompileActivation) IsActive(num *big.Int, time uint64) bool {
+	if a == nil {
+		return false
+	}
+	return isBlockForked(a.Block, num) || isTimestampForked(a.Time, time)
+}
+
+// IsPrecompileActive returns whether the custom precompile at addr is scheduled
+// and active at the given block and time.
+func (c *ChainConfig) IsPrecompileActive(addr common.Address, num *big.Int, time uint64) bool {
+	return c.Precompiles[addr].IsActive(num, time)
+}
+
+// ActivePrecompiles returns the addresses of all custom precompiles active at
+// the given block and time, sorted in ascending order for determinism.
+func (c *ChainConfig) ActivePrecompiles(num *big.Int, time uint64) []common.Address {
+	var active []common.Address
+	for addr, activation := range c.Precompiles {
+		if activation.IsActive(num, time) {
+			active = append(active, addr)
+		}
+	}
+	slices.SortFunc(active, func(a, b common.Address) int { return a.Cmp(b) })
+	return active
+}
+
+// checkPrecompilesCompatible verifies that rescheduling custom precompiles
+// doesn't alter an activation which already took place.
+func (c *ChainConfig) checkPrecompilesCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	addrs := make(map[common.Address]struct{})
+	for addr := range c.Precompiles {
+		addrs[addr] = struct{}{}
+	}
+	for addr := range newcfg.Precompiles {
+		addrs[addr] = struct{}{}
+	}
+	sorted := make([]common.Address, 0, len(addrs))
+	for addr := range addrs {
+		sorted = append(sorted, addr)
+	}
+	slices.SortFunc(sorted, func(a, b common.Address) int { return a.Cmp(b) })
+
+	for _, addr := range sorted {
+		var (
+			stored = c.Precompiles[addr]
+			next   = newcfg.Precompiles[addr]
+		)
+		if stored == nil {
+			stored = new(PrecompileActivation)
+		}
+		if next == nil {
+			next = new(PrecompileActivation)
+		}
+		if isForkBlockIncompatible(stored.Block, next.Block, headNumber) {
+			return newBlockCompatError(fmt.Sprintf("Precompile %v activation block", addr), stored.Block, next.Block)
+		}
+		if isForkTimestampIncompatible(stored.Time, next.Time, headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("Precompile %v activation timestamp", addr), stored.Time, next.Time)
+		}
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -88,6 +89,10 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter
+	interceptors         []ServerInterceptor
+	notifyInterceptors   []NotificationInterceptor
+	clientInterceptors   []ClientInterceptor
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -120,6 +125,9 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.rateLimiter = c.rateLimiter
+	handler.interceptors = c.interceptors
+	handler.notifyInterceptors = c.notifyInterceptors
 	return &clientConn{conn, handler}
 }
 
@@ -247,6 +255,10 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		rateLimiter:          cfg.rateLimiter,
+		interceptors:         cfg.interceptors,
+		notifyInterceptors:   cfg.notifyInterceptors,
+		clientInterceptors:   cfg.clientInterceptors,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -335,6 +347,17 @@ func (c *Client) Call(result interface{}, method string, args ...interface{}) er
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
 func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if len(c.clientInterceptors) == 0 {
+		return c.call(ctx, result, method, args...)
+	}
+	invoke := chainInvoker(c.clientInterceptors, func(ctx context.Context, call *ClientCall) error {
+		return c.call(ctx, call.Result, call.Method, call.Args...)
+	})
+	return invoke(ctx, &ClientCall{Method: method, Args: args, Result: result})
+}
+
+// call sends a method call and waits for its result.
+func (c *Client) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
@@ -505,6 +528,26 @@ func (c *Client) ShhSubscribe(ctx context.Context, channel interface{}, args ...
 // ErrSubscriptionQueueOverflow. Use a sufficiently large buffer on the channel or ensure
 // that the channel usually has at least one reader to prevent this issue.
 func (c *Client) Subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
+	if len(c.clientInterceptors) == 0 {
+		return c.subscribe(ctx, namespace, channel, args...)
+	}
+	var sub *ClientSubscription
+	invoke := chainInvoker(c.clientInterceptors, func(ctx context.Context, call *ClientCall) error {
+		var err error
+		sub, err = c.subscribe(ctx, strings.TrimSuffix(call.Method, subscribeMethodSuffix), call.Result, call.Args...)
+		return err
+	})
+	if err := invoke(ctx, &ClientCall{Method: namespace + subscribeMethodSuffix, Args: args, Result: channel}); err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, errors.New("subscription request intercepted")
+	}
+	return sub, nil
+}
+
+// subscribe sends a subscription request and waits for it to be confirmed.
+func (c *Client) subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
 	// Check type of channel first.
 	chanVal := reflect.ValueOf(channel)
 	if chanVal.Kind() != reflect.Chan || chanVal.Type().ChanDir()&reflect.SendDir == 0 {
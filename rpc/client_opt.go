@@ -41,6 +41,12 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *RateLimiter
+	interceptors       []ServerInterceptor
+	notifyInterceptors []NotificationInterceptor
+
+	// Client call options
+	clientInterceptors []ClientInterceptor
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -142,3 +148,12 @@ func WithBatchResponseSizeLimit(sizeLimit int) ClientOption {
 		cfg.batchResponseLimit = sizeLimit
 	})
 }
+
+// WithInterceptors appends interceptors to the chain wrapping the calls and
+// subscription requests sent by the client, the first interceptor being the
+// outermost. Batch calls are not intercepted.
+func WithInterceptors(interceptors ...ClientInterceptor) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.clientInterceptors = append(cfg.clientInterceptors, interceptors...)
+	})
+}
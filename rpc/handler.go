@@ -62,6 +62,9 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	rateLimiter          *RateLimiter // optional per-client budgets of method calls
+	interceptors         []ServerInterceptor
+	notifyInterceptors   []NotificationInterceptor
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -489,8 +492,36 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	}
 }
 
-// handleCall processes method calls.
+// handleCall processes method calls, passing them through the interceptors.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if len(h.interceptors) == 0 {
+		return h.serveCall(cp, msg)
+	}
+	serve := chainCallHandler(h.interceptors, func(ctx context.Context, call *Call) (interface{}, error) {
+		req := *msg
+		req.Method, req.Params = call.Method, call.Params
+
+		// The call proc is shared by the calls of a batch, restore its context
+		// for the subsequent ones.
+		parent := cp.ctx
+		cp.ctx = ctx
+		answer := h.serveCall(cp, &req)
+		cp.ctx = parent
+
+		if answer.Error != nil {
+			return nil, answer.Error
+		}
+		return answer.Result, nil
+	})
+	result, err := serve(cp.ctx, &Call{Method: msg.Method, Params: msg.Params, Peer: PeerInfoFromContext(cp.ctx)})
+	if err != nil {
+		return msg.errorResponse(err)
+	}
+	return msg.response(result)
+}
+
+// serveCall dispatches method calls to the registered callbacks.
+func (h *handler) serveCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -503,6 +534,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if callb != h.unsubscribeCb {
+		if err := h.chargeCall(cp.ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
@@ -543,6 +579,9 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if err := h.chargeCall(cp.ctx, msg.Method); err != nil {
+		return msg.errorResponse(err)
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
@@ -560,6 +599,15 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	return h.runMethod(ctx, msg, callb, args)
 }
 
+// chargeCall charges the cost of a method call to the budget of the client, if
+// rate limiting is enabled.
+func (h *handler) chargeCall(ctx context.Context, method string) error {
+	if h.rateLimiter == nil {
+		return nil
+	}
+	return h.rateLimiter.Allow(PeerInfoFromContext(ctx), method)
+}
+
 // runMethod runs the Go callback for an RPC method.
 func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *callback, args []reflect.Value) *jsonrpcMessage {
 	result, err := callb.call(ctx, msg.Method, args)
@@ -0,0 +1,119 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Call is a method call served by a Server, as seen by the interceptors.
+//
+// Interceptors may modify the method and parameters before passing the call on
+// to the next handler, which then serves the modified call.
+type Call struct {
+	Method string          // Name of the method, e.g. "eth_call"
+	Params json.RawMessage // JSON encoded positional parameters
+	Peer   PeerInfo        // Information about the calling client
+}
+
+// IsSubscribe returns whether the call creates a subscription.
+func (c *Call) IsSubscribe() bool {
+	return strings.HasSuffix(c.Method, subscribeMethodSuffix)
+}
+
+// CallHandler serves a method call, returning its result or error. The result
+// returned by the server itself is the JSON encoded json.RawMessage, but any
+// value encodable to JSON may be returned by the interceptors instead.
+type CallHandler func(ctx context.Context, call *Call) (interface{}, error)
+
+// ServerInterceptor intercepts the method calls served by a Server, including
+// subscription requests. It may observe or modify the call and its result by
+// invoking next, or short-circuit the call by returning without doing so.
+//
+// Errors implementing the Error and DataError interfaces are returned to the
+// client with their code and data.
+type ServerInterceptor func(ctx context.Context, call *Call, next CallHandler) (interface{}, error)
+
+// Notification is a subscription notification sent by a Server. Only the result
+// of a notification may be modified by the interceptors.
+type Notification struct {
+	Namespace    string      // Namespace of the subscription, e.g. "eth"
+	Subscription ID          // ID of the subscription
+	Result       interface{} // Payload of the notification
+}
+
+// NotificationHandler sends a subscription notification.
+type NotificationHandler func(ctx context.Context, n *Notification) error
+
+// NotificationInterceptor intercepts the subscription notifications sent by a
+// Server. It may observe or modify the notification by invoking next, or drop
+// it by returning without doing so. Errors returned are passed to the Notify
+// call of the subscription.
+type NotificationInterceptor func(ctx context.Context, n *Notification, next NotificationHandler) error
+
+// ClientCall is a method call sent by a Client, as seen by the interceptors.
+type ClientCall struct {
+	Method string        // Name of the method, e.g. "eth_call"
+	Args   []interface{} // Arguments of the call
+	Result interface{}   // Destination of the result, the notification channel for subscriptions
+}
+
+// Invoker sends a method call and waits for its result.
+type Invoker func(ctx context.Context, call *ClientCall) error
+
+// ClientInterceptor intercepts the calls and subscription requests sent by a
+// Client. It may observe or modify the call and its result by invoking next,
+// or short-circuit the call by returning without doing so.
+type ClientInterceptor func(ctx context.Context, call *ClientCall, next Invoker) error
+
+// chainCallHandler wraps the handler into the interceptors, the first one of
+// them being the outermost.
+func chainCallHandler(interceptors []ServerInterceptor, handler CallHandler) CallHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, call *Call) (interface{}, error) {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return handler
+}
+
+// chainNotificationHandler wraps the handler into the interceptors, the first
+// one of them being the outermost.
+func chainNotificationHandler(interceptors []NotificationInterceptor, handler NotificationHandler) NotificationHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, n *Notification) error {
+			return interceptor(ctx, n, next)
+		}
+	}
+	return handler
+}
+
+// chainInvoker wraps the invoker into the interceptors, the first one of them
+// being the outermost.
+func chainInvoker(interceptors []ClientInterceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, call *ClientCall) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoker
+}
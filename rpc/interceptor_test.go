@@ -0,0 +1,226 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests that the server interceptors are run in order and can modify calls and
+// results, or short-circuit them.
+func TestServerInterceptors(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	var (
+		trace []string
+		lock  sync.Mutex
+	)
+	record := func(name string) ServerInterceptor {
+		return func(ctx context.Context, call *Call, next CallHandler) (interface{}, error) {
+			lock.Lock()
+			trace = append(trace, name+":"+call.Method)
+			lock.Unlock()
+			return next(ctx, call)
+		}
+	}
+	server.UseInterceptors(
+		record("outer"),
+		// Deny the methods of the nftest namespace
+		func(ctx context.Context, call *Call, next CallHandler) (interface{}, error) {
+			if strings.HasPrefix(call.Method, "nftest_") {
+				return nil, testError{}
+			}
+			return next(ctx, call)
+		},
+		// Redirect test_alias to test_echo with rewritten parameters and result
+		func(ctx context.Context, call *Call, next CallHandler) (interface{}, error) {
+			if call.Method != "test_alias" {
+				return next(ctx, call)
+			}
+			call.Method, call.Params = "test_echo", json.RawMessage(`["rewritten", 2]`)
+			result, err := next(ctx, call)
+			if err != nil {
+				return nil, err
+			}
+			var echo echoResult
+			if err := json.Unmarshal(result.(json.RawMessage), &echo); err != nil {
+				return nil, err
+			}
+			echo.Int *= 10
+			return echo, nil
+		},
+		record("inner"),
+	)
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_alias", "x", 1); err != nil {
+		t.Fatalf("Failed to call alias: %v", err)
+	}
+	if want := (echoResult{"rewritten", 20, nil}); !reflect.DeepEqual(result, want) {
+		t.Fatalf("Result mismatch: have %+v, want %+v", result, want)
+	}
+	err := client.Call(nil, "nftest_echo", 1)
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != 444 {
+		t.Fatalf("Denied call error mismatch: %v", err)
+	}
+	if data := err.(DataError).ErrorData(); data != "testError data" {
+		t.Fatalf("Denied call data mismatch: %v", data)
+	}
+	// Errors of the services must be passed through unchanged
+	err = client.Call(nil, "test_returnError")
+	if rpcErr, ok := err.(Error); !ok || rpcErr.ErrorCode() != 444 {
+		t.Fatalf("Service error mismatch: %v", err)
+	}
+	want := []string{"outer:test_alias", "inner:test_echo", "outer:nftest_echo", "outer:test_returnError", "inner:test_returnError"}
+	if !reflect.DeepEqual(trace, want) {
+		t.Fatalf("Trace mismatch:\nhave %v\nwant %v", trace, want)
+	}
+}
+
+// Tests that the interceptors apply to every call of a batch separately.
+func TestServerInterceptorsBatch(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	server.UseInterceptors(func(ctx context.Context, call *Call, next CallHandler) (interface{}, error) {
+		if call.Method == "test_repeat" {
+			return "intercepted", nil
+		}
+		return next(ctx, call)
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	batch := []BatchElem{
+		{Method: "test_repeat", Args: []interface{}{"x", 2}, Result: new(string)},
+		{Method: "test_echo", Args: []interface{}{"x", 1}, Result: new(echoResult)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatalf("Failed to send batch: %v", err)
+	}
+	if batch[0].Error != nil || *batch[0].Result.(*string) != "intercepted" {
+		t.Fatalf("Intercepted batch element mismatch: %v %v", batch[0].Error, *batch[0].Result.(*string))
+	}
+	if batch[1].Error != nil || batch[1].Result.(*echoResult).String != "x" {
+		t.Fatalf("Passed batch element mismatch: %v %v", batch[1].Error, batch[1].Result)
+	}
+}
+
+// Tests that notification interceptors can modify and drop notifications.
+func TestNotificationInterceptors(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	server.UseNotificationInterceptors(func(ctx context.Context, n *Notification, next NotificationHandler) error {
+		if n.Namespace != "nftest" {
+			t.Errorf("Namespace mismatch: %s", n.Namespace)
+		}
+		// Drop the odd values and double the even ones
+		if v := n.Result.(int); v%2 == 0 {
+			n.Result = v * 2
+			return next(ctx, n)
+		}
+		return nil
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", ch, "someSubscription", 6, 0)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	for _, want := range []int{0, 4, 8} {
+		select {
+		case have := <-ch:
+			if have != want {
+				t.Fatalf("Notification mismatch: have %d, want %d", have, want)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("Subscription failed: %v", err)
+		case <-time.After(time.Second):
+			t.Fatal("Notification timeout")
+		}
+	}
+}
+
+// Tests that the client interceptors wrap calls and subscription requests.
+func TestClientInterceptors(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+
+	var methods []string
+	interceptor := func(ctx context.Context, call *ClientCall, next Invoker) error {
+		methods = append(methods, call.Method)
+		switch call.Method {
+		case "test_cached":
+			*call.Result.(*string) = "cached"
+			return nil
+		case "test_echo":
+			call.Args = []interface{}{"intercepted", 1}
+		}
+		return next(ctx, call)
+	}
+	httpsrv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpsrv.Close()
+
+	client, err := DialOptions(context.Background(), "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"), WithInterceptors(interceptor))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var cached string
+	if err := client.Call(&cached, "test_cached"); err != nil || cached != "cached" {
+		t.Fatalf("Short-circuited call mismatch: %q, %v", cached, err)
+	}
+	var echo echoResult
+	if err := client.Call(&echo, "test_echo", "x", 1); err != nil || echo.String != "intercepted" {
+		t.Fatalf("Modified call mismatch: %+v, %v", echo, err)
+	}
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", ch, "someSubscription", 1, 7)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+	if v := <-ch; v != 7 {
+		t.Fatalf("Notification mismatch: have %d, want 7", v)
+	}
+	if want := []string{"test_cached", "test_echo", "nftest_subscribe"}; !reflect.DeepEqual(methods, want) {
+		t.Fatalf("Intercepted methods mismatch: have %v, want %v", methods, want)
+	}
+	// Subscriptions swallowed by the interceptors must be reported
+	client.clientInterceptors = []ClientInterceptor{func(ctx context.Context, call *ClientCall, next Invoker) error {
+		return nil
+	}}
+	if _, err := client.Subscribe(context.Background(), "nftest", ch, "someSubscription", 1, 7); err == nil {
+		t.Fatal("Intercepted subscription returned no error")
+	}
+}
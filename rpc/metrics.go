@@ -32,6 +32,14 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	// costMeterName is the prefix of the per-method cost meters.
+	costMeterName = "rpc/cost"
+
+	rpcCostMeter          = metrics.NewRegisteredMeter("rpc/cost/all", nil)
+	rateLimitedMeter      = metrics.NewRegisteredMeter("rpc/ratelimit/rejected", nil)
+	quotaExceededMeter    = metrics.NewRegisteredMeter("rpc/ratelimit/quota", nil)
+	rateLimitClientsGauge = metrics.NewRegisteredGauge("rpc/ratelimit/clients", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
@@ -48,3 +56,9 @@ func updateServeTimeHistogram(method string, success bool, elapsed time.Duration
 	}
 	metrics.GetOrRegisterHistogramLazy(h, nil, sampler).Update(elapsed.Microseconds())
 }
+
+// updateMethodCost tracks the cost units spent on a remote RPC call.
+func updateMethodCost(method string, cost uint64) {
+	rpcCostMeter.Mark(int64(cost))
+	metrics.GetOrRegisterMeter(fmt.Sprintf("%s/%s", costMeterName, method), nil).Mark(int64(cost))
+}
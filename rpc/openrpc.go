@@ -0,0 +1,522 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"go/doc"
+	"math/big"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification the generated
+// documents conform to.
+const OpenRPCVersion = "1.2.6"
+
+// OpenRPCDocument is an OpenRPC document describing the methods of a server.
+// Subscriptions are listed separately, as the specification has no notion of
+// them.
+type OpenRPCDocument struct {
+	OpenRPC       string             `json:"openrpc"`
+	Info          OpenRPCInfo        `json:"info"`
+	Methods       []*OpenRPCMethod   `json:"methods"`
+	Subscriptions []*OpenRPCMethod   `json:"x-subscriptions,omitempty"`
+	Components    *OpenRPCComponents `json:"components,omitempty"`
+}
+
+// OpenRPCInfo is the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a method, or a subscription if Subscription is set.
+type OpenRPCMethod struct {
+	Name           string                      `json:"name"`
+	Subscription   string                      `json:"x-subscription,omitempty"`
+	Summary        string                      `json:"summary,omitempty"`
+	Description    string                      `json:"description,omitempty"`
+	ParamStructure string                      `json:"paramStructure"`
+	Params         []*OpenRPCContentDescriptor `json:"params"`
+	Result         *OpenRPCContentDescriptor   `json:"result"`
+}
+
+// OpenRPCContentDescriptor describes a parameter or result of a method.
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenRPCComponents holds the schemas of the named types referenced by the
+// methods of a document.
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas,omitempty"`
+}
+
+// JSONSchema is the subset of JSON Schema used to describe method parameters and
+// results. The empty schema matches any value.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+// JSONSchemaProvider may be implemented by types with a custom JSON encoding to
+// describe it. The method is invoked on the zero value of the type.
+type JSONSchemaProvider interface {
+	JSONSchema() *JSONSchema
+}
+
+// MethodDoc is the documentation of a service method.
+type MethodDoc struct {
+	Doc    string   // Doc comment of the method
+	Params []string // Names of the parameters, excluding the context
+}
+
+// MethodDocs holds the documentation of service methods, keyed by the package
+// name, receiver type and method name, e.g. "ethapi.BlockChainAPI.GetBalance".
+// It can be extracted from the source code using ParseMethodDocs.
+type MethodDocs map[string]MethodDoc
+
+const (
+	hexQuantityPattern = "^0x(0|[1-9a-fA-F][0-9a-fA-F]*)$"
+	hexDataPattern     = "^0x([0-9a-fA-F]{2})*$"
+)
+
+var (
+	quantitySchema    = &JSONSchema{Title: "hex encoded unsigned integer", Type: "string", Pattern: hexQuantityPattern}
+	blockNumberSchema = &JSONSchema{
+		Title: "block number or tag",
+		OneOf: []*JSONSchema{
+			quantitySchema,
+			{Title: "block tag", Type: "string", Enum: []string{"earliest", "latest", "pending", "finalized", "safe"}},
+		},
+	}
+	hashSchema = &JSONSchema{Title: "32 byte hex value", Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"}
+
+	// knownSchemas are the schemas of the types with custom encodings used
+	// throughout the APIs.
+	knownSchemas = map[reflect.Type]*JSONSchema{
+		reflect.TypeOf(hexutil.Big{}):     quantitySchema,
+		reflect.TypeOf(hexutil.Uint64(0)): quantitySchema,
+		reflect.TypeOf(hexutil.Uint(0)):   quantitySchema,
+		reflect.TypeOf(hexutil.Bytes{}):   {Title: "hex encoded bytes", Type: "string", Pattern: hexDataPattern},
+		reflect.TypeOf(common.Hash{}):     hashSchema,
+		reflect.TypeOf(common.Address{}):  {Title: "hex encoded address", Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+		reflect.TypeOf(big.Int{}):         {Type: "integer"},
+		reflect.TypeOf(BlockNumber(0)):    blockNumberSchema,
+		reflect.TypeOf(BlockNumberOrHash{}): {
+			Title: "block number, tag or hash",
+			OneOf: []*JSONSchema{
+				blockNumberSchema,
+				hashSchema,
+				{
+					Type: "object",
+					Properties: map[string]*JSONSchema{
+						"blockNumber":      blockNumberSchema,
+						"blockHash":        hashSchema,
+						"requireCanonical": {Type: "boolean"},
+					},
+				},
+			},
+		},
+		reflect.TypeOf(ID("")):            {Title: "subscription identifier", Type: "string"},
+		reflect.TypeOf(json.RawMessage{}): {},
+	}
+
+	jsonSchemaProviderType = reflect.TypeOf((*JSONSchemaProvider)(nil)).Elem()
+	jsonMarshalerType      = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// coreTypesPath is the import path of the core data structures of the APIs.
+const coreTypesPath = "github.com/ethereum/go-ethereum/core/types"
+
+// gencodecOverrides describes the types whose JSON encoding is generated by
+// gencodec, keyed by their package path and name. The values mirror the field
+// override types passed to gencodec: their fields replace the types of the
+// same-named fields of the type, and tagged ones missing from the type are added
+// to the encoding. This package can't depend on core/types, so its overrides are
+// copied here and must be kept in sync with the gencodec directives.
+var gencodecOverrides = map[string]reflect.Type{
+	coreTypesPath + ".Header": reflect.TypeOf(struct {
+		Difficulty    *hexutil.Big
+		Number        *hexutil.Big
+		GasLimit      hexutil.Uint64
+		GasUsed       hexutil.Uint64
+		Time          hexutil.Uint64
+		Extra         hexutil.Bytes
+		BaseFee       *hexutil.Big
+		Hash          common.Hash `json:"hash"`
+		BlobGasUsed   *hexutil.Uint64
+		ExcessBlobGas *hexutil.Uint64
+	}{}),
+	coreTypesPath + ".Receipt": reflect.TypeOf(struct {
+		Type                  hexutil.Uint64
+		PostState             hexutil.Bytes
+		Status                hexutil.Uint64
+		CumulativeGasUsed     hexutil.Uint64
+		GasUsed               hexutil.Uint64
+		EffectiveGasPrice     *hexutil.Big
+		BlobGasUsed           hexutil.Uint64
+		BlobGasPrice          *hexutil.Big
+		BlockNumber           *hexutil.Big
+		TransactionIndex      hexutil.Uint
+		L1GasPrice            *hexutil.Big
+		L1GasUsed             *hexutil.Big
+		L1Fee                 *hexutil.Big
+		FeeScalar             *big.Float
+		DepositNonce          *hexutil.Uint64
+		DepositReceiptVersion *hexutil.Uint64
+	}{}),
+	coreTypesPath + ".Log": reflect.TypeOf(struct {
+		Data        hexutil.Bytes
+		BlockNumber hexutil.Uint64
+		TxIndex     hexutil.Uint
+		Index       hexutil.Uint
+	}{}),
+	coreTypesPath + ".Withdrawal": reflect.TypeOf(struct {
+		Index     hexutil.Uint64
+		Validator hexutil.Uint64
+		Amount    hexutil.Uint64
+	}{}),
+	// Transactions are encoded by hand, all the fields of the encoding are
+	// added by the overrides.
+	coreTypesPath + ".Transaction": reflect.TypeOf(struct {
+		Type                 hexutil.Uint64  `json:"type"`
+		ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+		Nonce                *hexutil.Uint64 `json:"nonce"`
+		To                   *common.Address `json:"to"`
+		Gas                  *hexutil.Uint64 `json:"gas"`
+		GasPrice             *hexutil.Big    `json:"gasPrice"`
+		MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+		MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+		MaxFeePerBlobGas     *hexutil.Big    `json:"maxFeePerBlobGas,omitempty"`
+		Value                *hexutil.Big    `json:"value"`
+		Input                *hexutil.Bytes  `json:"input"`
+		AccessList           *[]struct {
+			Address     common.Address `json:"address"`
+			StorageKeys []common.Hash  `json:"storageKeys"`
+		} `json:"accessList,omitempty"`
+		BlobVersionedHashes []common.Hash   `json:"blobVersionedHashes,omitempty"`
+		V                   *hexutil.Big    `json:"v"`
+		R                   *hexutil.Big    `json:"r"`
+		S                   *hexutil.Big    `json:"s"`
+		YParity             *hexutil.Uint64 `json:"yParity,omitempty"`
+		SourceHash          *common.Hash    `json:"sourceHash,omitempty"`
+		From                *common.Address `json:"from,omitempty"`
+		Mint                *hexutil.Big    `json:"mint,omitempty"`
+		IsSystemTx          *bool           `json:"isSystemTx,omitempty"`
+		Hash                common.Hash     `json:"hash"`
+	}{}),
+}
+
+// SetMethodDocs sets the documentation included in the OpenRPC document of the
+// server.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetMethodDocs(docs MethodDocs) {
+	s.docs = docs
+}
+
+// OpenRPC generates an OpenRPC document describing the methods and subscriptions
+// registered on the server.
+func (s *Server) OpenRPC() *OpenRPCDocument {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	var (
+		gen = newSchemaGenerator()
+		d   = &OpenRPCDocument{
+			OpenRPC: OpenRPCVersion,
+			Info:    OpenRPCInfo{Title: "Ethereum JSON-RPC", Version: "1.0.0"},
+			Methods: []*OpenRPCMethod{},
+		}
+	)
+	// Iterate in a stable order, so the names of the schemas are deterministic
+	for _, ns := range sortedKeys(s.services.services) {
+		svc := s.services.services[ns]
+		for _, name := range sortedKeys(svc.callbacks) {
+			cb := svc.callbacks[name]
+			d.Methods = append(d.Methods, gen.method(ns+serviceMethodSeparator+name, cb, s.docs[docKey(cb, name)]))
+		}
+		for _, name := range sortedKeys(svc.subscriptions) {
+			cb := svc.subscriptions[name]
+			m := gen.method(ns+subscribeMethodSuffix, cb, s.docs[docKey(cb, name)])
+			m.Subscription = name
+			d.Subscriptions = append(d.Subscriptions, m)
+		}
+	}
+	if len(gen.schemas) > 0 {
+		d.Components = &OpenRPCComponents{Schemas: gen.schemas}
+	}
+	return d
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// docKey returns the key of a callback in the method documentation.
+func docKey(cb *callback, name string) string {
+	typ := cb.rcvr.Type()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	method := []rune(name)
+	method[0] = unicode.ToUpper(method[0])
+	return path.Base(typ.PkgPath()) + "." + typ.Name() + "." + string(method)
+}
+
+// schemaGenerator derives JSON schemas from Go types, collecting the schemas of
+// the named struct types as components to reference.
+type schemaGenerator struct {
+	schemas map[string]*JSONSchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*JSONSchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// method describes a callback.
+func (g *schemaGenerator) method(name string, cb *callback, docs MethodDoc) *OpenRPCMethod {
+	m := &OpenRPCMethod{
+		Name:           name,
+		Description:    docs.Doc,
+		ParamStructure: "by-position",
+		Params:         []*OpenRPCContentDescriptor{},
+	}
+	if docs.Doc != "" {
+		m.Summary = new(doc.Package).Synopsis(docs.Doc)
+	}
+	for i, typ := range cb.argTypes {
+		// Trailing pointer arguments may be omitted, see parsePositionalArguments
+		param := &OpenRPCContentDescriptor{
+			Name:     fmt.Sprintf("arg%d", i),
+			Required: typ.Kind() != reflect.Ptr,
+			Schema:   g.schema(typ),
+		}
+		if len(docs.Params) == len(cb.argTypes) {
+			param.Name = docs.Params[i]
+		}
+		m.Params = append(m.Params, param)
+	}
+	m.Result = &OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}}
+	switch {
+	case cb.isSubscribe:
+		m.Result.Schema = g.schema(reflect.TypeOf(ID("")))
+	case cb.errPos != 0 && cb.fn.Type().NumOut() > 0:
+		m.Result.Schema = g.schema(cb.fn.Type().Out(0))
+	}
+	return m
+}
+
+// schema derives the JSON schema of the JSON encoding of a type.
+func (g *schemaGenerator) schema(typ reflect.Type) *JSONSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if schema, ok := knownSchemas[typ]; ok {
+		return schema
+	}
+	// Types with custom encodings describe themselves, or are described by
+	// what little is known about their encoding.
+	switch {
+	case typ.Implements(jsonSchemaProviderType):
+		return reflect.Zero(typ).Interface().(JSONSchemaProvider).JSONSchema()
+	case reflect.PointerTo(typ).Implements(jsonSchemaProviderType):
+		return reflect.New(typ).Interface().(JSONSchemaProvider).JSONSchema()
+	case typ.Kind() == reflect.Struct && jsonMarshaling(typ) != nil:
+		return g.ref(typ)
+	case typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType):
+		return &JSONSchema{Title: typeName(typ)}
+	case typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType):
+		return &JSONSchema{Title: typeName(typ), Type: "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Title: "base64 encoded bytes", Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Array:
+		return &JSONSchema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return g.object(typ)
+		}
+		return g.ref(typ)
+	default:
+		// Interfaces, or types not encodable at all
+		return &JSONSchema{}
+	}
+}
+
+// ref returns a reference to the component schema of a named struct type,
+// generating it first if needed.
+func (g *schemaGenerator) ref(typ reflect.Type) *JSONSchema {
+	name, ok := g.names[typ]
+	if !ok {
+		name = typ.Name()
+		if _, taken := g.schemas[name]; taken {
+			name = path.Base(typ.PkgPath()) + "." + name
+		}
+		// Register the name before generating the schema, for recursive types
+		g.names[typ] = name
+		g.schemas[name] = nil
+		g.schemas[name] = g.object(typ)
+	}
+	return &JSONSchema{Ref: "#/components/schemas/" + name}
+}
+
+// object describes the JSON object encoding of a struct type, following the
+// field rules of encoding/json, or of gencodec if the type declares its field
+// overrides.
+func (g *schemaGenerator) object(typ reflect.Type) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	if overrides := jsonMarshaling(typ); overrides != nil {
+		g.overrideFields(typ, overrides, schema.Properties)
+	} else {
+		g.fields(typ, schema.Properties)
+	}
+	return schema
+}
+
+// jsonMarshaling returns the gencodec field override type of a struct type, or
+// nil if it's not encoded by gencodec.
+func jsonMarshaling(typ reflect.Type) reflect.Type {
+	if typ.Name() == "" {
+		return nil
+	}
+	return gencodecOverrides[typ.PkgPath()+"."+typ.Name()]
+}
+
+// overrideFields describes the fields of a struct type encoded by gencodec,
+// with the types of the fields replaced or added by the override type.
+func (g *schemaGenerator) overrideFields(typ, overrides reflect.Type, props map[string]*JSONSchema) {
+	jsonName := func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		return name
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		ftyp := field.Type
+		if override, ok := overrides.FieldByName(field.Name); ok {
+			ftyp = override.Type
+		}
+		props[jsonName(field)] = g.schema(ftyp)
+	}
+	for i := 0; i < overrides.NumField(); i++ {
+		field := overrides.Field(i)
+		if _, ok := typ.FieldByName(field.Name); ok || field.Tag.Get("json") == "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		props[jsonName(field)] = g.schema(field.Type)
+	}
+}
+
+func (g *schemaGenerator) fields(typ reflect.Type, props map[string]*JSONSchema) {
+	// Fields of embedded structs are promoted, unless shadowed by the outer ones
+	var embedded []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ftyp := field.Type
+			if ftyp.Kind() == reflect.Ptr {
+				ftyp = ftyp.Elem()
+			}
+			if ftyp.Kind() == reflect.Struct {
+				embedded = append(embedded, ftyp)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := props[name]; ok {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",string,") {
+			props[name] = &JSONSchema{Type: "string"}
+		} else {
+			props[name] = g.schema(field.Type)
+		}
+	}
+	for _, ftyp := range embedded {
+		g.fields(ftyp, props)
+	}
+}
+
+// typeName returns the qualified name of a type, e.g. "types.Header".
+func typeName(typ reflect.Type) string {
+	if typ.PkgPath() == "" {
+		return typ.String()
+	}
+	return path.Base(typ.PkgPath()) + "." + typ.Name()
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ParseMethodDocs extracts the documentation of the exported methods declared in
+// the Go packages below the given directories.
+func ParseMethodDocs(dirs ...string) (MethodDocs, error) {
+	var (
+		docs = make(MethodDocs)
+		fset = token.NewFileSet()
+	)
+	for _, root := range dirs {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return err
+			}
+			if name := d.Name(); path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			notest := func(info fs.FileInfo) bool {
+				return !strings.HasSuffix(info.Name(), "_test.go")
+			}
+			pkgs, err := parser.ParseDir(fset, path, notest, parser.ParseComments)
+			if err != nil {
+				return err
+			}
+			for _, pkg := range pkgs {
+				for _, file := range pkg.Files {
+					collectMethodDocs(docs, pkg.Name, file)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// collectMethodDocs adds the documentation of the exported methods declared in a
+// file.
+func collectMethodDocs(docs MethodDocs, pkg string, file *ast.File) {
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || !fn.Name.IsExported() {
+			continue
+		}
+		recv := receiverName(fn.Recv.List[0].Type)
+		if recv == "" {
+			continue
+		}
+		var params []string
+		for i, field := range fn.Type.Params.List {
+			names := field.Names
+			if i == 0 && isContextType(field.Type) && len(names) > 0 {
+				names = names[1:] // the context isn't a parameter of the method call
+			}
+			for _, name := range names {
+				params = append(params, name.Name)
+			}
+		}
+		docs[pkg+"."+recv+"."+fn.Name.Name] = MethodDoc{
+			Doc:    strings.TrimSpace(fn.Doc.Text()),
+			Params: params,
+		}
+	}
+}
+
+// receiverName returns the type name of a method receiver.
+func receiverName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// isContextType reports whether the expression is context.Context.
+func isContextType(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type openrpcTestService struct{}
+
+type openrpcBase struct {
+	Kind  string `json:"kind"`
+	Shade int    `json:"shade"`
+}
+
+type openrpcNode struct {
+	openrpcBase
+	Shade    string         `json:"shade"` // shadows the embedded field
+	Value    *hexutil.Big   `json:"value"`
+	Gas      uint64         `json:"gas,string"`
+	Children []*openrpcNode `json:"children,omitempty"`
+	Ignored  bool           `json:"-"`
+}
+
+type openrpcCustom struct{}
+
+func (openrpcCustom) MarshalJSON() ([]byte, error) { return []byte(`"custom"`), nil }
+func (openrpcCustom) JSONSchema() *JSONSchema {
+	return &JSONSchema{Type: "string", Enum: []string{"custom"}}
+}
+
+type openrpcGenerated struct {
+	Number uint64 `json:"number"`
+	Data   []byte `json:"data"`
+	Secret int    `json:"-"`
+}
+
+type openrpcGeneratedMarshaling struct {
+	Number hexutil.Uint64
+	Data   hexutil.Bytes
+	Hash   common.Hash `json:"hash"`
+}
+
+func (openrpcGenerated) MarshalJSON() ([]byte, error) { return []byte(`{}`), nil }
+
+func (s *openrpcTestService) GetNode(ctx context.Context, addr common.Address, block BlockNumberOrHash, full *bool) (*openrpcNode, error) {
+	return nil, nil
+}
+
+func (s *openrpcTestService) Custom(data hexutil.Bytes) openrpcCustom {
+	return openrpcCustom{}
+}
+
+func (s *openrpcTestService) Nothing() error {
+	return nil
+}
+
+func (s *openrpcTestService) Nodes(ctx context.Context, from hexutil.Uint64) (*Subscription, error) {
+	return nil, nil
+}
+
+// Tests that the schemas are derived from the Go types of the methods, and the
+// document is served by rpc_discover.
+func TestOpenRPC(t *testing.T) {
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", new(openrpcTestService)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetMethodDocs(MethodDocs{
+		"rpc.openrpcTestService.GetNode": {
+			Doc:    "GetNode returns a node. It may be missing.",
+			Params: []string{"addr", "block", "full"},
+		},
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc *OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatalf("Failed to discover: %v", err)
+	}
+	var names []string
+	for _, m := range doc.Methods {
+		names = append(names, m.Name)
+	}
+	if want := []string{"rpc_discover", "rpc_modules", "test_custom", "test_getNode", "test_nothing"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Method mismatch: have %v, want %v", names, want)
+	}
+	// Documented method with known types
+	get := doc.Methods[3]
+	if get.Summary != "GetNode returns a node." || get.Description != "GetNode returns a node. It may be missing." {
+		t.Errorf("Documentation mismatch: %q, %q", get.Summary, get.Description)
+	}
+	if len(get.Params) != 3 || get.Params[0].Name != "addr" || !get.Params[1].Required || get.Params[2].Required {
+		t.Fatalf("Parameter mismatch: %+v", get.Params)
+	}
+	if !reflect.DeepEqual(get.Params[0].Schema, knownSchemas[reflect.TypeOf(common.Address{})]) {
+		t.Errorf("Address schema mismatch: %+v", get.Params[0].Schema)
+	}
+	if len(get.Params[1].Schema.OneOf) != 3 || get.Params[2].Schema.Type != "boolean" {
+		t.Errorf("Parameter schema mismatch: %+v, %+v", get.Params[1].Schema, get.Params[2].Schema)
+	}
+	// Struct results are referenced components, recursion included
+	if get.Result.Schema.Ref != "#/components/schemas/openrpcNode" {
+		t.Fatalf("Result reference mismatch: %q", get.Result.Schema.Ref)
+	}
+	node := doc.Components.Schemas["openrpcNode"]
+	want := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"kind":     {Type: "string"},
+			"shade":    {Type: "string"},
+			"value":    quantitySchema,
+			"gas":      {Type: "string"},
+			"children": {Type: "array", Items: &JSONSchema{Ref: "#/components/schemas/openrpcNode"}},
+		},
+	}
+	if !reflect.DeepEqual(node, want) {
+		t.Errorf("Component schema mismatch:\nhave %+v\nwant %+v", node, want)
+	}
+	// Custom marshalers describing themselves, and methods without results
+	if custom := doc.Methods[2].Result.Schema; custom.Type != "string" || len(custom.Enum) != 1 {
+		t.Errorf("Custom schema mismatch: %+v", custom)
+	}
+	if result := doc.Methods[4].Result.Schema; result.Type != "null" {
+		t.Errorf("Empty result schema mismatch: %+v", result)
+	}
+	// Subscriptions are listed separately
+	if len(doc.Subscriptions) != 1 {
+		t.Fatalf("Subscription count mismatch: %d", len(doc.Subscriptions))
+	}
+	if sub := doc.Subscriptions[0]; sub.Name != "test_subscribe" || sub.Subscription != "nodes" || len(sub.Params) != 1 || sub.Result.Schema.Type != "string" {
+		t.Errorf("Subscription mismatch: %+v", sub)
+	}
+}
+
+// Tests that types with gencodec encodings are described by their fields with
+// the declared overrides applied.
+func TestOpenRPCFieldOverrides(t *testing.T) {
+	key := "github.com/ethereum/go-ethereum/rpc.openrpcGenerated"
+	gencodecOverrides[key] = reflect.TypeOf(openrpcGeneratedMarshaling{})
+	defer delete(gencodecOverrides, key)
+
+	gen := newSchemaGenerator()
+	if ref := gen.schema(reflect.TypeOf(&openrpcGenerated{})); ref.Ref != "#/components/schemas/openrpcGenerated" {
+		t.Fatalf("Reference mismatch: %+v", ref)
+	}
+	want := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"number": quantitySchema,
+			"data":   knownSchemas[reflect.TypeOf(hexutil.Bytes{})],
+			"hash":   hashSchema,
+		},
+	}
+	if have := gen.schemas["openrpcGenerated"]; !reflect.DeepEqual(have, want) {
+		t.Errorf("Schema mismatch:\nhave %+v\nwant %+v", have, want)
+	}
+}
+
+// Tests that the mirrored gencodec overrides of the core types describe all the
+// fields of their actual encodings.
+func TestOpenRPCCoreTypes(t *testing.T) {
+	to := common.Address{1}
+	values := []interface{}{
+		&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), BaseFee: big.NewInt(1), BlobGasUsed: new(uint64), ExcessBlobGas: new(uint64)},
+		&types.Receipt{Logs: []*types.Log{}},
+		&types.Log{},
+		&types.Withdrawal{},
+		types.NewTx(&types.BlobTx{}),
+		types.NewTx(&types.DepositTx{To: &to, Mint: big.NewInt(1)}),
+	}
+	for _, v := range values {
+		enc, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%T: failed to encode: %v", v, err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			t.Fatalf("%T: failed to decode: %v", v, err)
+		}
+		typ := reflect.TypeOf(v).Elem()
+		gen := newSchemaGenerator()
+		gen.schema(typ)
+		schema := gen.schemas[typ.Name()]
+		if schema == nil {
+			t.Fatalf("%T: not described by its fields", v)
+		}
+		for name := range fields {
+			if _, ok := schema.Properties[name]; !ok {
+				t.Errorf("%T: field %q missing from the schema", v, name)
+			}
+		}
+	}
+}
+
+// Tests that the method documentation is extracted from the source code.
+func TestParseMethodDocs(t *testing.T) {
+	dir := t.TempDir()
+	src := `package api
+
+import "context"
+
+type API struct{}
+
+// GetThing returns a thing.
+func (api *API) GetThing(ctx context.Context, id, kind string, full bool) string { return "" }
+
+// Plain has unnamed parameters.
+func (API) Plain(int) {}
+
+// unexported is skipped.
+func (api *API) unexported() {}
+`
+	if err := os.MkdirAll(filepath.Join(dir, "api", "testdata"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "api.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "testdata", "broken.go"), []byte("not go"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	docs, err := ParseMethodDocs(dir)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	want := MethodDocs{
+		"api.API.GetThing": {Doc: "GetThing returns a thing.", Params: []string{"id", "kind", "full"}},
+		"api.API.Plain":    {Doc: "Plain has unnamed parameters."},
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Fatalf("Docs mismatch:\nhave %+v\nwant %+v", docs, want)
+	}
+}
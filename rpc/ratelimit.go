@@ -0,0 +1,226 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
+
+const (
+	// defaultRateLimitClients is the default number of clients tracked by a
+	// rate limiter.
+	defaultRateLimitClients = 10000
+
+	// defaultQuotaPeriod is the default period of the client quotas.
+	defaultQuotaPeriod = time.Hour
+)
+
+// RateLimitConfig configures the request budgets of the clients of a server.
+//
+// Every method call costs a number of units, as configured by its weight. The
+// units are drawn from a per-client token bucket, replenished at a steady rate,
+// and optionally counted against a quota over a longer period.
+type RateLimitConfig struct {
+	// Rate is the number of cost units replenished per second for each client.
+	// Zero disables the rate limit.
+	Rate float64
+
+	// Burst is the maximum number of cost units a client can accumulate. It
+	// defaults to the rate if unset. Calls costing more than the burst are only
+	// allowed with a full bucket, leaving the client indebted afterwards.
+	Burst float64 `toml:",omitempty"`
+
+	// Quota is the maximum number of cost units a client may spend within a
+	// quota period. Zero disables the quota.
+	Quota uint64 `toml:",omitempty"`
+
+	// QuotaPeriod is the length of the quota periods, an hour by default.
+	QuotaPeriod time.Duration `toml:",omitempty"`
+
+	// DefaultCost is the cost of methods without a configured weight. It
+	// defaults to 1 if unset.
+	DefaultCost uint64 `toml:",omitempty"`
+
+	// MethodCosts assigns cost weights to methods. Besides full method names,
+	// such as "eth_getLogs", the keys may be namespace wildcards, such as
+	// "debug_*". A zero weight exempts the method from the limits.
+	MethodCosts map[string]uint64 `toml:",omitempty"`
+
+	// MaxClients is the maximum number of clients tracked, the least recently
+	// active clients are forgotten beyond it.
+	MaxClients int `toml:",omitempty"`
+
+	// ClientKey derives the key to track the budget of a client by. It defaults
+	// to the identifier of authenticated clients, otherwise their IP address.
+	ClientKey func(PeerInfo) string `toml:"-"`
+}
+
+// DefaultClientKey keys the client budgets on the identifier of authenticated
+// clients, or their IP address without the port otherwise. Credentials which
+// weren't verified are ignored, so clients can't escape their budget by making
+// up new ones.
+func DefaultClientKey(info PeerInfo) string {
+	if info.HTTP.AuthClient != "" {
+		return "auth:" + info.HTTP.AuthClient
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		host = info.RemoteAddr
+	}
+	return info.Transport + ":" + host
+}
+
+// RateLimitError is returned for calls exceeding the budget of a client.
+type RateLimitError struct {
+	Method     string
+	Quota      bool          // Whether the quota is exhausted, not the rate limit
+	RetryAfter time.Duration // Time until the call would be allowed
+}
+
+func (e *RateLimitError) ErrorCode() int { return errcodeLimitExceeded }
+
+func (e *RateLimitError) Error() string {
+	limit := "rate limit"
+	if e.Quota {
+		limit = "quota"
+	}
+	return fmt.Sprintf("%s exceeded for %s, retry in %v", limit, e.Method, e.RetryAfter)
+}
+
+// ErrorData returns the retry hint, in whole seconds.
+func (e *RateLimitError) ErrorData() interface{} {
+	return map[string]interface{}{
+		"retryAfter": int64(math.Ceil(e.RetryAfter.Seconds())),
+	}
+}
+
+// clientBudget is the remaining budget of a client.
+type clientBudget struct {
+	tokens  float64        // Units left in the token bucket, negative if indebted
+	updated mclock.AbsTime // Time of the last bucket refill
+	spent   uint64         // Units spent in the current quota period
+	period  mclock.AbsTime // Start of the current quota period
+}
+
+// RateLimiter enforces the per-client budgets configured by a RateLimitConfig.
+// It may be shared by multiple servers, the budgets then span all of them.
+type RateLimiter struct {
+	config  RateLimitConfig
+	clock   mclock.Clock
+	lock    sync.Mutex
+	clients lru.BasicLRU[string, *clientBudget]
+}
+
+// NewRateLimiter creates a rate limiter with the given configuration.
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	if config.Burst <= 0 {
+		config.Burst = config.Rate
+	}
+	if config.QuotaPeriod <= 0 {
+		config.QuotaPeriod = defaultQuotaPeriod
+	}
+	if config.DefaultCost == 0 {
+		config.DefaultCost = 1
+	}
+	if config.MaxClients <= 0 {
+		config.MaxClients = defaultRateLimitClients
+	}
+	if config.ClientKey == nil {
+		config.ClientKey = DefaultClientKey
+	}
+	return &RateLimiter{
+		config:  config,
+		clock:   mclock.System{},
+		clients: lru.NewBasicLRU[string, *clientBudget](config.MaxClients),
+	}
+}
+
+// Cost returns the cost weight of a method.
+func (l *RateLimiter) Cost(method string) uint64 {
+	if cost, ok := l.config.MethodCosts[method]; ok {
+		return cost
+	}
+	if i := strings.Index(method, serviceMethodSeparator); i > 0 {
+		if cost, ok := l.config.MethodCosts[method[:i+1]+"*"]; ok {
+			return cost
+		}
+	}
+	return l.config.DefaultCost
+}
+
+// Allow charges the cost of a method call to the budget of the client, or
+// returns a RateLimitError if the budget is exhausted.
+func (l *RateLimiter) Allow(info PeerInfo, method string) error {
+	cost := l.Cost(method)
+	if cost == 0 || (l.config.Rate <= 0 && l.config.Quota == 0) {
+		return nil
+	}
+	key := l.config.ClientKey(info)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+	budget, ok := l.clients.Get(key)
+	if !ok {
+		budget = &clientBudget{tokens: l.config.Burst, updated: now, period: now}
+		l.clients.Add(key, budget)
+		rateLimitClientsGauge.Update(int64(l.clients.Len()))
+	}
+	// Refill the bucket and roll the quota period over
+	if l.config.Rate > 0 {
+		elapsed := time.Duration(now - budget.updated)
+		budget.tokens = math.Min(l.config.Burst, budget.tokens+elapsed.Seconds()*l.config.Rate)
+		budget.updated = now
+	}
+	if l.config.Quota > 0 {
+		if elapsed := time.Duration(now - budget.period); elapsed >= l.config.QuotaPeriod {
+			budget.period = budget.period.Add(elapsed / l.config.QuotaPeriod * l.config.QuotaPeriod)
+			budget.spent = 0
+		}
+		if budget.spent+cost > l.config.Quota {
+			quotaExceededMeter.Mark(1)
+			return &RateLimitError{
+				Method:     method,
+				Quota:      true,
+				RetryAfter: time.Duration(budget.period.Add(l.config.QuotaPeriod) - now),
+			}
+		}
+	}
+	if l.config.Rate > 0 {
+		// Calls above the burst size are allowed with a full bucket
+		if need := math.Min(float64(cost), l.config.Burst); budget.tokens < need {
+			rateLimitedMeter.Mark(1)
+			return &RateLimitError{
+				Method:     method,
+				RetryAfter: time.Duration((need - budget.tokens) / l.config.Rate * float64(time.Second)),
+			}
+		}
+		budget.tokens -= float64(cost)
+	}
+	budget.spent += cost
+	updateMethodCost(method, cost)
+	return nil
+}
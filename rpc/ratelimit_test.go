@@ -0,0 +1,165 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
+
+func newTestRateLimiter(config RateLimitConfig) (*RateLimiter, *mclock.Simulated) {
+	clock := new(mclock.Simulated)
+	limiter := NewRateLimiter(config)
+	limiter.clock = clock
+	return limiter, clock
+}
+
+// Tests that the token bucket is drained by the method costs and refilled over
+// time, with the retry hints matching the refill.
+func TestRateLimiterRate(t *testing.T) {
+	limiter, clock := newTestRateLimiter(RateLimitConfig{
+		Rate:        10,
+		Burst:       20,
+		MethodCosts: map[string]uint64{"eth_getLogs": 15, "debug_*": 50, "eth_chainId": 0},
+	})
+	peer := PeerInfo{Transport: "http", RemoteAddr: "1.2.3.4:5678"}
+
+	if err := limiter.Allow(peer, "eth_getLogs"); err != nil {
+		t.Fatalf("First call rejected: %v", err)
+	}
+	// 5 units left, a second expensive call must wait 1s for the missing 10
+	err := limiter.Allow(peer, "eth_getLogs")
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) || rlErr.Quota || rlErr.RetryAfter != time.Second {
+		t.Fatalf("Second call error mismatch: %v", err)
+	}
+	// Free methods are always allowed
+	if err := limiter.Allow(peer, "eth_chainId"); err != nil {
+		t.Fatalf("Free call rejected: %v", err)
+	}
+	// Other clients have their own budget, even from the same host
+	if err := limiter.Allow(PeerInfo{Transport: "http", RemoteAddr: "4.3.2.1:5678"}, "eth_getLogs"); err != nil {
+		t.Fatalf("Other client rejected: %v", err)
+	}
+	clock.Run(time.Second)
+	if err := limiter.Allow(PeerInfo{Transport: "http", RemoteAddr: "1.2.3.4:1111"}, "eth_getLogs"); err != nil {
+		t.Fatalf("Call after refill rejected: %v", err)
+	}
+	// Calls above the burst need a full bucket and leave the client indebted
+	clock.Run(2 * time.Second)
+	if err := limiter.Allow(peer, "debug_traceTransaction"); err != nil {
+		t.Fatalf("Call above burst rejected: %v", err)
+	}
+	if err := limiter.Allow(peer, "eth_blockNumber"); !errors.As(err, &rlErr) || rlErr.RetryAfter != 3100*time.Millisecond {
+		t.Fatalf("Indebted call error mismatch: %v", err)
+	}
+}
+
+// Tests that quotas limit the spending over a period, independent of the rate.
+func TestRateLimiterQuota(t *testing.T) {
+	limiter, clock := newTestRateLimiter(RateLimitConfig{Quota: 3, QuotaPeriod: time.Minute})
+	peer := PeerInfo{Transport: "ws", RemoteAddr: "1.2.3.4:5678"}
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Allow(peer, "eth_call"); err != nil {
+			t.Fatalf("Call %d rejected: %v", i, err)
+		}
+	}
+	clock.Run(20 * time.Second)
+	err := limiter.Allow(peer, "eth_call")
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) || !rlErr.Quota || rlErr.RetryAfter != 40*time.Second {
+		t.Fatalf("Quota error mismatch: %v", err)
+	}
+	clock.Run(100 * time.Second)
+	if err := limiter.Allow(peer, "eth_call"); err != nil {
+		t.Fatalf("Call in next period rejected: %v", err)
+	}
+}
+
+// Tests that the server rejects calls over the budget with the retry hint, and
+// keys the budgets on the authenticated clients, ignoring unverified tokens.
+func TestServerRateLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetRateLimiter(NewRateLimiter(RateLimitConfig{Rate: 0.001, Burst: 1}))
+
+	// Authenticate the clients by a test header, standing in for a JWT handler
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get("X-Test-Client"); id != "" {
+			r = r.WithContext(WithAuthClient(r.Context(), id))
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpsrv.Close()
+
+	dial := func(header, value string) *Client {
+		var opts []ClientOption
+		if header != "" {
+			opts = append(opts, WithHeader(header, value))
+		}
+		client, err := DialOptions(context.Background(), httpsrv.URL, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	checkLimited := func(name string, client *Client) {
+		t.Helper()
+
+		var result echoResult
+		err := client.Call(&result, "test_echo", "x", 1)
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeLimitExceeded {
+			t.Fatalf("Client %q: error mismatch: %v", name, err)
+		}
+		data, ok := err.(DataError).ErrorData().(map[string]interface{})
+		if !ok || data["retryAfter"].(float64) <= 0 {
+			t.Fatalf("Client %q: retry hint missing: %v", name, err.(DataError).ErrorData())
+		}
+	}
+	// Unverified bearer tokens share the budget of the address
+	anon := dial("", "")
+	defer anon.Close()
+
+	var result echoResult
+	if err := anon.Call(&result, "test_echo", "x", 1); err != nil {
+		t.Fatalf("First call failed: %v", err)
+	}
+	checkLimited("anonymous", anon)
+
+	token := dial("Authorization", "Bearer alice")
+	defer token.Close()
+	checkLimited("bearer", token)
+
+	// Authenticated clients have their own budgets
+	for _, id := range []string{"alice", "bob"} {
+		client := dial("X-Test-Client", id)
+		defer client.Close()
+
+		if err := client.Call(&result, "test_echo", "x", 1); err != nil {
+			t.Fatalf("Client %q: first call failed: %v", id, err)
+		}
+		checkLimited(id, client)
+	}
+}
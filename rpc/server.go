@@ -51,6 +51,10 @@ type Server struct {
 	run                atomic.Bool
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *RateLimiter
+	interceptors       []ServerInterceptor
+	notifyInterceptors []NotificationInterceptor
+	docs               MethodDocs
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -78,6 +82,33 @@ func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
 	s.batchResponseLimit = maxResponseSize
 }
 
+// SetRateLimiter sets the limiter enforcing the per-client budgets of method calls.
+// The limiter may be shared with other servers. A nil limiter disables the limits.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// UseInterceptors appends interceptors to the chain wrapping the method calls served,
+// the first interceptor being the outermost. See ServerInterceptor for details.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) UseInterceptors(interceptors ...ServerInterceptor) {
+	s.interceptors = append(s.interceptors, interceptors...)
+}
+
+// UseNotificationInterceptors appends interceptors to the chain wrapping the subscription
+// notifications sent, the first interceptor being the outermost.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) UseNotificationInterceptors(interceptors ...NotificationInterceptor) {
+	s.notifyInterceptors = append(s.notifyInterceptors, interceptors...)
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -103,6 +134,9 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		rateLimiter:        s.rateLimiter,
+		interceptors:       s.interceptors,
+		notifyInterceptors: s.notifyInterceptors,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -138,6 +172,8 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
+	h.interceptors = s.interceptors
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
@@ -188,6 +224,11 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// Discover returns an OpenRPC document describing the methods of the server.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	return s.server.OpenRPC()
+}
+
 // PeerInfo contains information about the remote end of the network connection.
 //
 // This is available within RPC method handlers through the context. Call
@@ -209,11 +250,30 @@ type PeerInfo struct {
 		UserAgent string
 		Origin    string
 		Host      string
+		// Identifier of the client, as authenticated by the HTTP handler stack
+		// in front of the server. It's empty for unauthenticated clients.
+		AuthClient string
 	}
 }
 
 type peerInfoContextKey struct{}
 
+type authClientContextKey struct{}
+
+// WithAuthClient returns a copy of the request context, marking the client as
+// authenticated under the given identifier. It's meant to be used by handlers
+// which verified the credentials of a request before passing it to the server.
+func WithAuthClient(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, authClientContextKey{}, id)
+}
+
+// authClientFromContext returns the identifier of the authenticated client set
+// by WithAuthClient, if any.
+func authClientFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(authClientContextKey{}).(string)
+	return id
+}
+
 // PeerInfoFromContext returns information about the client's network connection.
 // Use this with the context passed to RPC method handler functions.
 //
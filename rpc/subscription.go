@@ -130,6 +130,17 @@ func (n *Notifier) CreateSubscription() *Subscription {
 // Notify sends a notification to the client with the given data as payload.
 // If an error occurs the RPC connection is closed and the error is returned.
 func (n *Notifier) Notify(id ID, data interface{}) error {
+	if len(n.h.notifyInterceptors) == 0 {
+		return n.notify(id, data)
+	}
+	send := chainNotificationHandler(n.h.notifyInterceptors, func(ctx context.Context, notif *Notification) error {
+		return n.notify(id, notif.Result)
+	})
+	return send(n.h.rootCtx, &Notification{Namespace: n.namespace, Subscription: id, Result: data})
+}
+
+// notify encodes the notification payload and sends or buffers it.
+func (n *Notifier) notify(id ID, data interface{}) error {
 	enc, err := json.Marshal(data)
 	if err != nil {
 		return err
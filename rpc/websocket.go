@@ -60,7 +60,8 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			log.Debug("WebSocket upgrade failed", "err", err)
 			return
 		}
-		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit).(*websocketCodec)
+		codec.info.HTTP.AuthClient = authClientFromContext(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
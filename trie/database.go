@@ -207,14 +207,21 @@ func (db *Database) Preimage(hash common.Hash) []byte {
 //
 // It's only supported by hash-based database and will return an error for others.
 func (db *Database) Cap(limit common.StorageSize) error {
-	hdb, ok := db.backend.(*hashdb.Database)
-	if !ok {
+	var capper interface {
+		Cap(common.StorageSize) error
+	}
+	switch b := db.backend.(type) {
+	case *hashdb.Database:
+		capper = b
+	case *parahashdb.Database:
+		capper = b
+	default:
 		return errors.New("not supported")
 	}
 	if db.preimages != nil {
 		db.preimages.commit(false)
 	}
-	return hdb.Cap(limit)
+	return capper.Cap(limit)
 }
 
 // Reference adds a new reference from a parent node to a child node. This function
@@ -223,22 +230,28 @@ func (db *Database) Cap(limit common.StorageSize) error {
 //
 // It's only supported by hash-based database and will return an error for others.
 func (db *Database) Reference(root common.Hash, parent common.Hash) error {
-	hdb, ok := db.backend.(*hashdb.Database)
-	if !ok {
+	switch b := db.backend.(type) {
+	case *hashdb.Database:
+		b.Reference(root, parent)
+	case *parahashdb.Database:
+		b.Reference(root, parent)
+	default:
 		return errors.New("not supported")
 	}
-	hdb.Reference(root, parent)
 	return nil
 }
 
 // Dereference removes an existing reference from a root node. It's only
 // supported by hash-based database and will return an error for others.
 func (db *Database) Dereference(root common.Hash) error {
-	hdb, ok := db.backend.(*hashdb.Database)
-	if !ok {
+	switch b := db.backend.(type) {
+	case *hashdb.Database:
+		b.Dereference(root)
+	case *parahashdb.Database:
+		b.Dereference(root)
+	default:
 		return errors.New("not supported")
 	}
-	hdb.Dereference(root)
 	return nil
 }
 
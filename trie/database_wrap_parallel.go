@@ -3,6 +3,7 @@ package trie
 import (
 	"github.com/VictoriaMetrics/fastcache"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/walbatch"
 	hashdb "github.com/ethereum/go-ethereum/trie/triedb/parahashdb"
 	parahashdb "github.com/ethereum/go-ethereum/trie/triedb/parahashdb"
 )
@@ -15,6 +16,17 @@ func NewParallelDatabase(diskdbs [16]ethdb.Database, config *Config) *Database {
 	return dbs
 }
 
+// NewParallelDatabaseWithWAL creates a parallel trie database like
+// NewParallelDatabase, but persists the nodes into all the shards atomically
+// through the given write-ahead log opened on the same shards.
+func NewParallelDatabaseWithWAL(diskdbs [16]ethdb.Database, wal *walbatch.Database, config *Config) *Database {
+	dbs := NewDatabase(diskdbs[0], config) // For preimage
+
+	dbConfig := &hashdb.Config{CleanCacheSize: 1024 * 1024 * 10}
+	dbs.backend = parahashdb.NewWithWAL(diskdbs, wal, mptResolver{}, dbConfig)
+	return dbs
+}
+
 func NewParallelDatabaseWithSharedCache(diskdbs [16]ethdb.Database, cleanCache *fastcache.Cache, config *Config) *Database {
 	dbs := NewDatabase(diskdbs[0], config) // For preimage
 	dbs.backend = parahashdb.NewWithCache(diskdbs, config, mptResolver{}, cleanCache, nil)
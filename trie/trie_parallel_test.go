@@ -532,3 +532,55 @@ func TestMptPerformance(t *testing.T) {
 	trie.ParallelGet(keys)
 	fmt.Println("ParallelThreadSafeGet ", len(keys), " entries in ", time.Since(t0))
 }
+
+// Tests that the nodes committed or flushed through the write-ahead log of a
+// sharded database land in their shards and can be read back after reopening.
+func TestParallelDatabaseWAL(t *testing.T) {
+	keys := make([][]byte, 256)
+	data := make([][]byte, len(keys))
+	for i := 0; i < len(data); i++ {
+		keys[i] = crypto.Keccak256([]byte(fmt.Sprint(i)))
+		data[i] = []byte(fmt.Sprint(i))
+	}
+	for _, flush := range []string{"commit", "cap"} {
+		shards := rawdb.NewMemoryShardedDatabase()
+		paraDB := NewParallelDatabaseWithWAL(shards.Shards(), shards.WAL(), nil)
+		paraTrie := NewEmptyParallel(paraDB)
+		paraTrie.ParallelUpdate(keys, data)
+
+		root, nodes, err := paraTrie.Commit(false)
+		if err != nil {
+			t.Fatalf("%s: failed to commit trie: %v", flush, err)
+		}
+		if err := paraDB.Update(root, types.EmptyRootHash, 0, trienode.NewWithNodeSet(nodes), &triestate.Set{}); err != nil {
+			t.Fatalf("%s: failed to update database: %v", flush, err)
+		}
+		if flush == "commit" {
+			err = paraDB.Commit(root, false)
+		} else {
+			err = paraDB.Cap(0)
+		}
+		if err != nil {
+			t.Fatalf("%s: failed to persist trie: %v", flush, err)
+		}
+		if _, dirty, _ := paraDB.Size(); dirty != 0 {
+			t.Fatalf("%s: dirty nodes left in memory: %v", flush, dirty)
+		}
+		// All the nodes must be on disk with the log cleaned up
+		it := shards.Shards()[0].NewIterator([]byte("wal-"), nil)
+		if it.Next() {
+			t.Fatalf("%s: log record left behind: %x", flush, it.Key())
+		}
+		it.Release()
+
+		reopened, err := New(TrieID(root), NewParallelDatabaseWithWAL(shards.Shards(), shards.WAL(), nil))
+		if err != nil {
+			t.Fatalf("%s: failed to reopen trie: %v", flush, err)
+		}
+		for i, k := range keys {
+			if v, err := reopened.Get(k); err != nil || !bytes.Equal(v, data[i]) {
+				t.Fatalf("%s: value %d mismatch: have %x, want %x, err %v", flush, i, v, data[i], err)
+			}
+		}
+	}
+}
@@ -365,6 +365,33 @@ func (db *database) Cap(limit common.StorageSize) error {
 	nodes, storage, start := len(db.dirties), db.dirtiesSize, time.Now()
 	batch := db.diskdb.NewBatch()
 
+	oldest, err := db.flushList(batch, limit, func() error {
+		// If we exceeded the ideal batch size, commit and reset
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to write flush list to disk", "err", err)
+		return err
+	}
+	// Flush out any remainder data from the last batch
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write flush list to disk", "err", err)
+		return err
+	}
+	db.uncacheFlushed(oldest, nodes, storage, start)
+	return nil
+}
+
+// flushList writes the oldest referenced nodes into the writer until the total
+// memory usage would go below the given threshold, returning the first node not
+// written. The optional flush callback is invoked after every node.
+func (db *database) flushList(w ethdb.KeyValueWriter, limit common.StorageSize, flush func() error) (common.Hash, error) {
 	// db.dirtiesSize only contains the useful data in the cache, but when reporting
 	// the total memory consumption, the maintenance metadata is also needed to be
 	// counted.
@@ -376,15 +403,12 @@ func (db *database) Cap(limit common.StorageSize) error {
 	for size > limit && oldest != (common.Hash{}) {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
-		rawdb.WriteLegacyTrieNode(batch, oldest, node.node)
+		rawdb.WriteLegacyTrieNode(w, oldest, node.node)
 
-		// If we exceeded the ideal batch size, commit and reset
-		if batch.ValueSize() >= ethdb.IdealBatchSize {
-			if err := batch.Write(); err != nil {
-				log.Error("Failed to write flush list to disk", "err", err)
-				return err
+		if flush != nil {
+			if err := flush(); err != nil {
+				return common.Hash{}, err
 			}
-			batch.Reset()
 		}
 		// Iterate to the next flush item, or abort if the size cap was achieved. Size
 		// is the total size, including the useful cached data (hash -> blob), the
@@ -395,12 +419,12 @@ func (db *database) Cap(limit common.StorageSize) error {
 		}
 		oldest = node.flushNext
 	}
-	// Flush out any remainder data from the last batch
-	if err := batch.Write(); err != nil {
-		log.Error("Failed to write flush list to disk", "err", err)
-		return err
-	}
-	// Write successful, clear out the flushed data
+	return oldest, nil
+}
+
+// uncacheFlushed clears out the nodes persisted by flushList, up to the oldest
+// one left in memory.
+func (db *database) uncacheFlushed(oldest common.Hash, nodes int, storage common.StorageSize, start time.Time) {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -427,8 +451,6 @@ func (db *database) Cap(limit common.StorageSize) error {
 
 	log.Debug("Persisted nodes from memory database", "nodes", nodes-len(db.dirties), "size", storage-db.dirtiesSize, "time", time.Since(start),
 		"flushnodes", db.flushnodes, "flushsize", db.flushsize, "flushtime", db.flushtime, "livenodes", len(db.dirties), "livesize", db.dirtiesSize)
-
-	return nil
 }
 
 // Commit iterates over all the children of a particular node, writes them out
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/walbatch"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/trie/trienode"
 	"github.com/ethereum/go-ethereum/trie/triestate"
@@ -15,6 +16,7 @@ import (
 
 type Database struct {
 	dbs [16]*database
+	wal *walbatch.Database // Write-ahead log updating the shards atomically, nil if unused
 }
 
 // diskdbs, db.cleans, mptResolver{}
@@ -36,6 +38,15 @@ func New(diskdb interface{}, _ interface{}, resolver ChildResolver, config *Conf
 	return nil
 }
 
+// NewWithWAL creates a parallel database on top of the given shards, persisting
+// the nodes of each Cap and Commit into all of them atomically through the write
+// ahead log. The log must have been opened on the same shards, in the same order.
+func NewWithWAL(diskdbs [16]ethdb.Database, wal *walbatch.Database, resolver ChildResolver, config *Config) *Database {
+	db := New(diskdbs, nil, resolver, config)
+	db.wal = wal
+	return db
+}
+
 func NewWithCache(diskdb interface{}, _ interface{}, resolver ChildResolver, sharedCleanCache *fastcache.Cache, config *Config) *Database {
 	db := &Database{}
 	if ddb, ok := diskdb.(ethdb.Database); ok {
@@ -107,10 +118,8 @@ func (this *Database) Initialized(genesisRoot common.Hash) bool {
 func (this *Database) Size() (common.StorageSize, common.StorageSize) {
 	total := common.StorageSize(0)
 	for i := 0; i < len(this.dbs); i++ {
-		this.dbs[i].lock.Lock()
-		_, size := this.dbs[i].Size()
+		_, size := this.dbs[i].Size() // Locks the shard itself
 		total += size
-		this.dbs[i].lock.Unlock()
 	}
 	return 0, total
 }
@@ -140,11 +149,17 @@ func (this *Database) Commit(hash common.Hash, report bool) error {
 		return err
 	}
 
-	children := []common.Hash{hash}
+	// Commit the subtries before the root, so an interrupted commit never leaves
+	// a root on disk without its children.
+	var children []common.Hash
 	this.shard(hash[:]).resolver.ForEach(encodedNode, func(child common.Hash) {
 		children = append(children, child)
 	})
+	children = append(children, hash)
 
+	if this.wal != nil {
+		return this.commitAtomic(children, report)
+	}
 	for i := 0; i < len(children); i++ {
 		if shard, _, err := this.Find(children[i]); shard != nil {
 			if err := shard.Commit(children[i], report); err != nil {
@@ -167,6 +182,9 @@ func (this *Database) Close() error {
 }
 
 func (this *Database) Cap(limit common.StorageSize) error {
+	if this.wal != nil {
+		return this.capAtomic(limit)
+	}
 	for i := 0; i < len(this.dbs); i++ {
 		if err := this.dbs[i].Cap(limit / common.StorageSize(len(this.dbs))); err != nil {
 			return err
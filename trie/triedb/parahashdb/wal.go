@@ -0,0 +1,179 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hashdb
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/walbatch"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// commitAtomic writes the dirty subtries of the given nodes into their shards
+// through a single write-ahead logged batch, which is only flushed early if it
+// grows too large. The nodes are ordered children first, so an early flush can
+// never persist a root without its subtries.
+func (this *Database) commitAtomic(hashes []common.Hash, report bool) error {
+	var (
+		start          = time.Now()
+		batch          = this.wal.NewBatch()
+		batches        [16]*shardBatch
+		nodes, storage = this.dirties()
+	)
+	for i := range batches {
+		batches[i] = &shardBatch{batch: batch, writer: batch.Store(i)}
+	}
+	for _, hash := range hashes {
+		shard, _, err := this.Find(hash)
+		if shard == nil {
+			return err
+		}
+		if err := shard.commit(hash, batches[this.index(shard)], &cleaner{shard}); err != nil {
+			log.Error("Failed to commit trie from trie database", "err", err)
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write trie to disk", "err", err)
+		return err
+	}
+	// Uncache the nodes only now that all of them are persisted
+	for i, shard := range this.dbs {
+		shard.lock.Lock()
+		err := batches[i].Replay(&cleaner{shard})
+		shard.lock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	logger := log.Info
+	if !report {
+		logger = log.Debug
+	}
+	livenodes, livesize := this.dirties()
+	logger("Persisted trie from sharded memory database", "nodes", nodes-livenodes, "size", storage-livesize, "time", time.Since(start),
+		"livenodes", livenodes, "livesize", livesize)
+	return nil
+}
+
+// capAtomic flushes the oldest nodes of all the shards through a single write
+// ahead logged batch. The flush lists of the shards aren't ordered relative to
+// each other, so flushing them in pieces could persist a parent node before its
+// children held by another shard.
+func (this *Database) capAtomic(limit common.StorageSize) error {
+	var (
+		start   = time.Now()
+		batch   = this.wal.NewBatch()
+		oldest  [16]common.Hash
+		nodes   [16]int
+		storage [16]common.StorageSize
+	)
+	for i, shard := range this.dbs {
+		nodes[i], storage[i] = len(shard.dirties), shard.dirtiesSize
+
+		var err error
+		if oldest[i], err = shard.flushList(batch.Store(i), limit/common.StorageSize(len(this.dbs)), nil); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Error("Failed to write flush list to disk", "err", err)
+		return err
+	}
+	for i, shard := range this.dbs {
+		shard.uncacheFlushed(oldest[i], nodes[i], storage[i], start)
+	}
+	return nil
+}
+
+// dirties returns the number and size of the dirty nodes across all the shards.
+func (this *Database) dirties() (int, common.StorageSize) {
+	var (
+		nodes int
+		size  common.StorageSize
+	)
+	for _, shard := range this.dbs {
+		shard.lock.RLock()
+		nodes, size = nodes+len(shard.dirties), size+shard.dirtiesSize
+		shard.lock.RUnlock()
+	}
+	return nodes, size
+}
+
+// index returns the position of the shard within the database.
+func (this *Database) index(shard *database) int {
+	for i := range this.dbs {
+		if this.dbs[i] == shard {
+			return i
+		}
+	}
+	panic("unknown shard")
+}
+
+// shardBatch is the batch of a single shard, queueing its writes into a batch
+// spanning all the shards. The writes are also kept locally, so they can be
+// uncached from the shard once persisted.
+type shardBatch struct {
+	batch  *walbatch.Batch
+	writer ethdb.KeyValueWriter
+	keys   [][]byte
+	values [][]byte
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *shardBatch) Put(key []byte, value []byte) error {
+	b.keys = append(b.keys, common.CopyBytes(key))
+	b.values = append(b.values, common.CopyBytes(value))
+	return b.writer.Put(key, value)
+}
+
+// Delete is not supported, trie nodes are never deleted by commits.
+func (b *shardBatch) Delete(key []byte) error {
+	panic("not implemented")
+}
+
+// ValueSize retrieves the amount of data queued up for writing across all the
+// shards, so the shared batch is flushed based on its total size.
+func (b *shardBatch) ValueSize() int {
+	return b.batch.ValueSize()
+}
+
+// Write flushes the shared batch, including the writes of the other shards.
+func (b *shardBatch) Write() error {
+	if err := b.batch.Write(); err != nil {
+		return err
+	}
+	b.batch.Reset()
+	return nil
+}
+
+// Reset drops the writes of this shard, once they've been replayed.
+func (b *shardBatch) Reset() {
+	b.keys, b.values = b.keys[:0], b.values[:0]
+}
+
+// Replay replays the writes of this shard.
+func (b *shardBatch) Replay(w ethdb.KeyValueWriter) error {
+	for i, key := range b.keys {
+		if err := w.Put(key, b.values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}